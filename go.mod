@@ -1,6 +1,6 @@
 module github.com/maximilien/i18n4go
 
-go 1.24.0
+go 1.25.0
 
 require (
 	github.com/go-bindata/go-bindata/v3 v3.1.3
@@ -9,7 +9,9 @@ require (
 	github.com/onsi/gomega v1.38.3
 	github.com/pivotal-cf-experimental/jibber_jabber v0.0.0-20151120183258-bcc4c8345a21
 	github.com/spf13/cobra v1.10.2
-	golang.org/x/text v0.32.0
+	golang.org/x/mod v0.35.0
+	golang.org/x/text v0.36.0
+	golang.org/x/tools v0.44.0
 )
 
 require (
@@ -22,11 +24,9 @@ require (
 	github.com/spf13/pflag v1.0.9 // indirect
 	go.yaml.in/yaml/v3 v3.0.4 // indirect
 	golang.org/x/lint v0.0.0-20191125180803-fdd1cda4f05f // indirect
-	golang.org/x/mod v0.30.0 // indirect
-	golang.org/x/net v0.47.0 // indirect
-	golang.org/x/sync v0.19.0 // indirect
-	golang.org/x/sys v0.38.0 // indirect
-	golang.org/x/tools v0.39.0 // indirect
+	golang.org/x/net v0.53.0 // indirect
+	golang.org/x/sync v0.20.0 // indirect
+	golang.org/x/sys v0.43.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
 )
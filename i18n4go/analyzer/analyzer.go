@@ -0,0 +1,250 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package analyzer defines an analysis.Analyzer reporting the string literals passed to user facing
+// functions without T(...) and the T(...) calls whose IDs are not in the source catalog, so that
+// i18n problems are reported by go vet and editors as the code is written
+package analyzer
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/maximilien/i18n4go/i18n4go/common"
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+// DEFAULT_SINKS are the functions whose string arguments are shown to users
+const DEFAULT_SINKS = "fmt.Print,fmt.Println,fmt.Printf,fmt.Fprint,fmt.Fprintln,fmt.Fprintf,fmt.Errorf,errors.New," +
+	"log.Print,log.Println,log.Printf,log.Fatal,log.Fatalln,log.Fatalf,log.Panic,log.Panicln,log.Panicf"
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "i18n4go",
+	Doc:      i18n.T("reports untranslated string literals passed to user facing functions and T(...) calls with IDs missing from the source catalog"),
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var (
+	qualifierFlag        string
+	catalogFilenameFlag  string
+	excludedFilenameFlag string
+	sinksFlag            string
+	tFuncNamesFlag       string
+)
+
+func init() {
+	Analyzer.Flags.StringVar(&qualifierFlag, "qualifier", "", i18n.T("the qualifier used when calling the T(...) function, e.g., i18n for i18n.T(...)"))
+	Analyzer.Flags.StringVar(&catalogFilenameFlag, "catalog", "", i18n.T("the source language JSON file, e.g., en_US.all.json, used to report T(...) calls with unknown IDs"))
+	Analyzer.Flags.StringVar(&excludedFilenameFlag, "excluded", "", i18n.T("the JSON file with strings and regexps to be excluded, see extract-strings"))
+	Analyzer.Flags.StringVar(&sinksFlag, "sinks", DEFAULT_SINKS, i18n.T("a comma separated list of the functions whose string arguments must be translated, e.g., fmt.Println,(*log.Logger).Printf"))
+	Analyzer.Flags.StringVar(&tFuncNamesFlag, "t-func-names", common.DEFAULT_T_FUNC_NAMES, i18n.T("a comma separated list of the names of the functions translating strings, e.g., T,t"))
+}
+
+// catalog is the loaded catalog of a file, reloaded when the size or the modification time of the file change, e.g.,
+// as it is edited while gopls runs the analyzer
+type catalog struct {
+	modTime time.Time
+	size    int64
+
+	ids map[string]bool
+	err error
+}
+
+var (
+	catalogsMutex sync.Mutex
+	catalogs      = make(map[string]*catalog)
+)
+
+func run(pass *analysis.Pass) (any, error) {
+	ids, err := loadCatalog(catalogFilenameFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	excludedStrings, excludedRegexps, err := loadExcluded(excludedFilenameFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	sinks := make(map[string]bool)
+	for _, sink := range common.ParseStringList(sinksFlag, ",") {
+		sinks[sink] = true
+	}
+
+	tFuncNames := common.ParseStringList(tFuncNamesFlag, ",")
+	if len(tFuncNames) == 0 {
+		tFuncNames = common.ParseStringList(common.DEFAULT_T_FUNC_NAMES, ",")
+	}
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+
+		if common.IsTFuncCall(call, qualifierFlag, tFuncNames) {
+			if ids != nil && len(call.Args) > 0 {
+				if id, ok := stringLiteral(call.Args[0]); ok && !ids[id] {
+					pass.Reportf(call.Args[0].Pos(), "%s", i18n.T("translation ID \"{{.Arg0}}\" is not in the catalog {{.Arg1}}", map[string]any{"Arg0": id, "Arg1": catalogFilenameFlag}))
+				}
+			}
+			return
+		}
+
+		sink := calleeName(pass.TypesInfo, call)
+		if !sinks[sink] {
+			return
+		}
+
+		for _, arg := range call.Args {
+			aString, ok := stringLiteral(arg)
+			if !ok || !hasTranslatableText(aString) || isExcluded(aString, excludedStrings, excludedRegexps) {
+				continue
+			}
+
+			pass.Reportf(arg.Pos(), "%s", i18n.T("string literal passed to {{.Arg0}} is not translated, wrap it with T(...)", map[string]any{"Arg0": sink}))
+		}
+	})
+
+	return nil, nil
+}
+
+// Private
+
+func loadCatalog(fileName string) (map[string]bool, error) {
+	if fileName == "" {
+		return nil, nil
+	}
+
+	fileInfo, err := os.Stat(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	catalogsMutex.Lock()
+	defer catalogsMutex.Unlock()
+
+	if loaded, ok := catalogs[fileName]; ok && loaded.modTime.Equal(fileInfo.ModTime()) && loaded.size == fileInfo.Size() {
+		return loaded.ids, loaded.err
+	}
+
+	loaded := &catalog{modTime: fileInfo.ModTime(), size: fileInfo.Size()}
+	stringInfos, err := common.LoadI18nStringInfos(fileName)
+	if err != nil {
+		loaded.err = err
+	} else {
+		loaded.ids = make(map[string]bool)
+		for _, stringInfo := range stringInfos {
+			loaded.ids[stringInfo.ID] = true
+		}
+	}
+	catalogs[fileName] = loaded
+
+	return loaded.ids, loaded.err
+}
+
+func loadExcluded(fileName string) (map[string]bool, []*regexp.Regexp, error) {
+	if fileName == "" {
+		return nil, nil, nil
+	}
+
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var excluded common.ExcludedStrings
+	err = json.Unmarshal(content, &excluded)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	excludedStrings := make(map[string]bool)
+	for _, excludedString := range excluded.ExcludedStrings {
+		excludedStrings[excludedString] = true
+	}
+
+	var excludedRegexps []*regexp.Regexp
+	for _, regexpString := range excluded.ExcludedRegexps {
+		compiledRegexp, err := regexp.Compile(regexpString)
+		if err != nil {
+			return nil, nil, err
+		}
+		excludedRegexps = append(excludedRegexps, compiledRegexp)
+	}
+
+	return excludedStrings, excludedRegexps, nil
+}
+
+// calleeName returns the name of the called function or method, e.g., fmt.Println or (*log.Logger).Printf
+func calleeName(info *types.Info, call *ast.CallExpr) string {
+	fn, ok := typeutil.Callee(info, call).(*types.Func)
+	if !ok {
+		return ""
+	}
+
+	return fn.FullName()
+}
+
+func stringLiteral(expr ast.Expr) (string, bool) {
+	basicLit, ok := expr.(*ast.BasicLit)
+	if !ok || basicLit.Kind != token.STRING {
+		return "", false
+	}
+
+	aString, err := strconv.Unquote(basicLit.Value)
+	if err != nil {
+		return "", false
+	}
+
+	return aString, true
+}
+
+// hasTranslatableText returns true if the string has letters once its printf verbs are removed, e.g., "%s: %d\n" has none
+func hasTranslatableText(aString string) bool {
+	text := strings.Replace(aString, "%%", "", -1)
+	for _, verb := range common.ParsePrintfVerbs(text) {
+		text = strings.Replace(text, verb.Text, "", -1)
+	}
+
+	return strings.IndexFunc(text, unicode.IsLetter) >= 0
+}
+
+func isExcluded(aString string, excludedStrings map[string]bool, excludedRegexps []*regexp.Regexp) bool {
+	if excludedStrings[aString] {
+		return true
+	}
+
+	for _, excludedRegexp := range excludedRegexps {
+		if excludedRegexp.MatchString(aString) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,28 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// i18n4go-vet runs the i18n4go analyzer standalone or with go vet, e.g.:
+//
+//	go vet -vettool=$(which i18n4go-vet) -catalog=$PWD/i18n/resources/en_US.all.json ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/maximilien/i18n4go/i18n4go/analyzer"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
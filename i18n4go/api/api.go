@@ -0,0 +1,118 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package api runs the i18n4go commands from go code, e.g., build tools embedding i18n4go, with typed options and
+// typed results instead of command line flags and printed reports. The options default like the flags of the
+// commands, except that nothing is excluded or ignored unless set, and the commands log nothing unless a Logger is
+// set.
+package api
+
+import (
+	"io"
+	"strings"
+
+	"github.com/maximilien/i18n4go/i18n4go/common"
+)
+
+const (
+	// TEXT_KEY_FORMAT uses the source text as the ID of the strings, the default
+	TEXT_KEY_FORMAT = common.TEXT_KEY_FORMAT
+
+	// SLUG_KEY_FORMAT generates IDs made of the package name and the first words of the source text
+	SLUG_KEY_FORMAT = common.SLUG_KEY_FORMAT
+
+	// HASH_KEY_FORMAT generates IDs made of the package name and a hash of the source text
+	HASH_KEY_FORMAT = common.HASH_KEY_FORMAT
+)
+
+// SourceOptions select the go files of the walked source trees, the vendor and testdata directories, the files
+// ignored by .gitignore files, the generated files and the files excluded by the build constraints are skipped
+type SourceOptions struct {
+	// GOOS and GOARCH of the build constraints, the host ones by default
+	GOOS   string
+	GOARCH string
+
+	// BuildTags satisfied by the build constraints
+	BuildTags []string
+
+	// Include are the sources skipped by default to include, i.e., vendor, testdata, generated and gitignored
+	Include []string
+}
+
+// FS is the file system the commands read their inputs from and write their outputs to, e.g., common.NewMemFS for
+// in memory source trees, common.NewOverlayFS for unsaved editor buffers or common.NewDryRunFS to record the writes
+type FS = common.WritableFS
+
+// Logger logs the verbose output, warnings and errors of the commands with levels, in the text or JSON format, e.g.,
+// &Logger{Level: common.INFO, Format: common.LOG_FORMAT_JSON, Writer: os.Stderr}
+type Logger = common.Logger
+
+// ValidationError, ParseError, IOError and ProviderError are the categories of the errors returned by the commands,
+// matched with errors.As, e.g., a ValidationError when Verify finds missing translations
+type (
+	ValidationError = common.ValidationError
+	ParseError      = common.ParseError
+	IOError         = common.IOError
+	ProviderError   = common.ProviderError
+)
+
+// Translation is an i18n string of a translation file
+type Translation struct {
+	ID          string
+	Translation string
+}
+
+// Private
+
+func (sources SourceOptions) apply(options *common.Options) {
+	options.GoosFlag = sources.GOOS
+	options.GoarchFlag = sources.GOARCH
+	options.BuildTagsFlag = strings.Join(sources.BuildTags, ",")
+	options.IncludeFlag = strings.Join(sources.Include, ",")
+}
+
+// logger returns the logger of the options, or a logger discarding the messages since the commands log nothing by
+// default
+func logger(logger *Logger) *common.Logger {
+	if logger == nil {
+		return &common.Logger{Level: common.ERROR, Writer: io.Discard}
+	}
+
+	return logger
+}
+
+func defaultDirname(dirName string) string {
+	if dirName == "" {
+		return "."
+	}
+
+	return dirName
+}
+
+func joinList(list []string, defaultList string) string {
+	if len(list) == 0 {
+		return defaultList
+	}
+
+	return strings.Join(list, ",")
+}
+
+func translations(i18nStringInfos []common.I18nStringInfo) []Translation {
+	var translations []Translation
+	for _, i18nStringInfo := range i18nStringInfos {
+		translations = append(translations, Translation{ID: i18nStringInfo.ID, Translation: i18nStringInfo.Translation})
+	}
+
+	return translations
+}
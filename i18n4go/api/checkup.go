@@ -0,0 +1,109 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/maximilien/i18n4go/i18n4go/cmds"
+	"github.com/maximilien/i18n4go/i18n4go/common"
+)
+
+// CheckupOptions are the options of checkup
+type CheckupOptions struct {
+	// SourceDirname is the directory of the go files, and ResourceDirname of the translation files, both the working
+	// directory by default
+	SourceDirname   string
+	ResourceDirname string
+
+	// Qualifier of the i18n.T(...) calls
+	Qualifier string
+
+	// IgnoreRegexp matches the directories of translation files to ignore
+	IgnoreRegexp string
+
+	// GlossaryFilename is the JSON file of the glossary the translations must respect
+	GlossaryFilename string
+
+	// Jobs is the number of files inspected concurrently, the number of CPUs by default, and CacheDirname the
+	// directory of the cache of the IDs of the T(...) calls
+	Jobs         int
+	CacheDirname string
+
+	Sources SourceOptions
+
+	// FS is the file system read and written, the OS file system when nil
+	FS FS
+
+	// Logger logs the verbose output, warnings and errors of the command, nothing when nil
+	Logger *Logger
+}
+
+// CheckupResult are the strings of the code and of the translation files which do not match, and the translations
+// violating the glossary, sorted by locale and ID
+type CheckupResult struct {
+	Mismatches         []Mismatch
+	GlossaryViolations []GlossaryViolation
+}
+
+// Mismatch is a string found in the code or the translation files of a locale, but not in the other, the locale of
+// the code is empty
+type Mismatch struct {
+	ID          string
+	FoundIn     string
+	MissingFrom string
+}
+
+// GlossaryViolation is a translation of a locale violating a term of the glossary
+type GlossaryViolation struct {
+	ID      string
+	Locale  string
+	Term    string
+	Message string
+}
+
+// Checkup checks that the strings of the T(...) calls of the code match the en_US translation files, and that the
+// translation files of the other locales match the en_US ones, like checkup an error is returned when they do not
+// match, detailed by the result
+func Checkup(checkupOptions CheckupOptions) (*CheckupResult, error) {
+	options := common.Options{
+		QualifierFlag:        checkupOptions.Qualifier,
+		IgnoreRegexpFlag:     checkupOptions.IgnoreRegexp,
+		GlossaryFilenameFlag: checkupOptions.GlossaryFilename,
+		JobsFlag:             checkupOptions.Jobs,
+		CacheDirFlag:         checkupOptions.CacheDirname,
+		FS:                   checkupOptions.FS,
+		Logger:               logger(checkupOptions.Logger),
+	}
+	checkupOptions.Sources.apply(&options)
+
+	checkup := cmds.NewCheckup(&options)
+	checkup.SourceDirname = defaultDirname(checkupOptions.SourceDirname)
+	checkup.ResourceDirname = defaultDirname(checkupOptions.ResourceDirname)
+	err := checkup.Run()
+
+	result := &CheckupResult{}
+	for _, mismatch := range checkup.Mismatches {
+		result.Mismatches = append(result.Mismatches, Mismatch{ID: mismatch.ID, FoundIn: mismatch.FoundIn, MissingFrom: mismatch.MissingFrom})
+	}
+	for _, violation := range checkup.GlossaryViolations {
+		result.GlossaryViolations = append(result.GlossaryViolations, GlossaryViolation{
+			ID:      violation.ID,
+			Locale:  violation.Locale,
+			Term:    violation.Term,
+			Message: violation.Message,
+		})
+	}
+
+	return result, err
+}
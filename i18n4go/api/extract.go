@@ -0,0 +1,172 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/maximilien/i18n4go/i18n4go/cmds"
+	"github.com/maximilien/i18n4go/i18n4go/common"
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+const (
+	// OUTPUT_FLAT creates the generated files in the output directory, the default
+	OUTPUT_FLAT = "flat"
+
+	// OUTPUT_MATCH_PACKAGE creates the generated files in directories of the output directory matching the package
+	// names
+	OUTPUT_MATCH_PACKAGE = "match-package"
+
+	// OUTPUT_MATCH_IMPORT creates the generated files in directories of the output directory matching the import
+	// paths of the packages
+	OUTPUT_MATCH_IMPORT = "match-import"
+)
+
+// ExtractOptions are the options of extract-strings
+type ExtractOptions struct {
+	// Filename is the go or template file whose strings are extracted, or Dirname the directory of the go and
+	// template files, and of its subdirectories with Recurse
+	Filename string
+	Dirname  string
+	Recurse  bool
+
+	// OutputDirname is the directory of the generated files, the directory of each source file by default, with the
+	// OutputLayout, OUTPUT_FLAT by default
+	OutputDirname string
+	OutputLayout  string
+
+	// ExcludedFilename is the JSON file with the strings and regexps to exclude, and SubstringFilename the JSON file
+	// of the regexps whose first capturing group is extracted
+	ExcludedFilename  string
+	SubstringFilename string
+
+	// IgnoreRegexp matches the names of the files to ignore
+	IgnoreRegexp string
+
+	// KeyFormat of the IDs of the strings, TEXT_KEY_FORMAT by default
+	KeyFormat string
+
+	// TemplateExtensions are the extensions of the template files, and TFuncNames the names of their functions
+	// translating strings, both default like the flags
+	TemplateExtensions []string
+	TFuncNames         []string
+
+	// Po also generates .po files, Meta the files of the positions of the strings, and DryRun no files at all
+	Po     bool
+	Meta   bool
+	DryRun bool
+
+	// Jobs is the number of files extracted concurrently, the number of CPUs by default, and CacheDirname the
+	// directory of the cache of the extracted strings
+	Jobs         int
+	CacheDirname string
+
+	Sources SourceOptions
+
+	// FS is the file system read and written, the OS file system when nil
+	FS FS
+
+	// Logger logs the verbose output, warnings and errors of the command, nothing when nil
+	Logger *Logger
+}
+
+// ExtractResult are the strings extracted from each file, in the order the files are saved
+type ExtractResult struct {
+	Files        []ExtractedFile
+	TotalFiles   int
+	TotalStrings int
+}
+
+// ExtractedFile are the strings extracted from a file, in the order of the file
+type ExtractedFile struct {
+	Filename    string
+	PackageName string
+	Strings     []ExtractedString
+}
+
+// ExtractedString is a string extracted from a file with its ID and position
+type ExtractedString struct {
+	ID     string
+	Value  string
+	Offset int
+	Line   int
+	Column int
+}
+
+// Extract extracts the strings of the go and template files to translate, the result is returned with the error
+// of the files extracted before it
+func Extract(extractOptions ExtractOptions) (*ExtractResult, error) {
+	if extractOptions.Filename == "" && extractOptions.Dirname == "" {
+		return nil, errors.New(i18n.T("i18n4go: a file or a directory is required"))
+	}
+
+	options := common.Options{
+		FilenameFlag:           extractOptions.Filename,
+		DirnameFlag:            extractOptions.Dirname,
+		RecurseFlag:            extractOptions.Recurse,
+		OutputDirFlag:          extractOptions.OutputDirname,
+		OutputFlatFlag:         true,
+		OutputMatchPackageFlag: extractOptions.OutputLayout == OUTPUT_MATCH_PACKAGE,
+		OutputMatchImportFlag:  extractOptions.OutputLayout == OUTPUT_MATCH_IMPORT,
+		ExcludedFilenameFlag:   extractOptions.ExcludedFilename,
+		SubstringFilenameFlag:  extractOptions.SubstringFilename,
+		IgnoreRegexpFlag:       extractOptions.IgnoreRegexp,
+		KeyFormatFlag:          extractOptions.KeyFormat,
+		TemplateExtensionsFlag: joinList(extractOptions.TemplateExtensions, common.DEFAULT_TEMPLATE_EXTENSIONS),
+		TFuncNamesFlag:         joinList(extractOptions.TFuncNames, common.DEFAULT_T_FUNC_NAMES),
+		PoFlag:                 extractOptions.Po,
+		MetaFlag:               extractOptions.Meta,
+		DryRunFlag:             extractOptions.DryRun,
+		JobsFlag:               extractOptions.Jobs,
+		CacheDirFlag:           extractOptions.CacheDirname,
+		FS:                     extractOptions.FS,
+		Logger:                 logger(extractOptions.Logger),
+	}
+	extractOptions.Sources.apply(&options)
+
+	if extractOptions.OutputLayout != "" && extractOptions.OutputLayout != OUTPUT_FLAT &&
+		!options.OutputMatchPackageFlag && !options.OutputMatchImportFlag {
+		return nil, errors.New(i18n.T("i18n4go: unknown output layout {{.Arg0}}, one of: flat, match-package, match-import", map[string]any{"Arg0": extractOptions.OutputLayout}))
+	}
+
+	extractStrings := cmds.NewExtractStrings(&options)
+	err := extractStrings.Run()
+
+	result := &ExtractResult{
+		TotalFiles:   extractStrings.TotalFiles,
+		TotalStrings: extractStrings.TotalStrings,
+	}
+	for _, fileStringInfos := range extractStrings.ExtractedFiles {
+		extractedFile := ExtractedFile{Filename: fileStringInfos.Filename, PackageName: fileStringInfos.PackageName}
+		for _, stringInfo := range fileStringInfos.StringInfos {
+			extractedFile.Strings = append(extractedFile.Strings, ExtractedString{
+				ID:     stringInfo.StringID(),
+				Value:  stringInfo.Value,
+				Offset: stringInfo.Offset,
+				Line:   stringInfo.Line,
+				Column: stringInfo.Column,
+			})
+		}
+		sort.Slice(extractedFile.Strings, func(i, j int) bool {
+			return extractedFile.Strings[i].Offset < extractedFile.Strings[j].Offset
+		})
+
+		result.Files = append(result.Files, extractedFile)
+	}
+
+	return result, err
+}
@@ -0,0 +1,102 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"io/ioutil"
+
+	"github.com/maximilien/i18n4go/i18n4go/cmds"
+	"github.com/maximilien/i18n4go/i18n4go/common"
+)
+
+// FixupOptions are the options of fixup
+type FixupOptions struct {
+	// SourceDirname is the directory of the go files, and ResourceDirname of the translation files, both the working
+	// directory by default
+	SourceDirname   string
+	ResourceDirname string
+
+	// Qualifier of the i18n.T(...) calls
+	Qualifier string
+
+	// IgnoreRegexp matches the directories of translation files to ignore
+	IgnoreRegexp string
+
+	// ResolveUpdate returns the removed ID updated by a new string of the code, or the empty string if the string is
+	// new, all the strings are new by default
+	ResolveUpdate func(id string, removedIDs []string) string
+
+	// MigrateKeys migrates the source text IDs of the translation files and of the code to keys of the KeyFormat,
+	// SLUG_KEY_FORMAT or HASH_KEY_FORMAT, instead of fixing the translation files
+	MigrateKeys bool
+	KeyFormat   string
+
+	// Jobs is the number of files inspected concurrently, the number of CPUs by default, and CacheDirname the
+	// directory of the cache of the IDs of the T(...) calls
+	Jobs         int
+	CacheDirname string
+
+	Sources SourceOptions
+
+	// FS is the file system read and written, the OS file system when nil
+	FS FS
+
+	// Logger logs the verbose output, warnings and errors of the command, nothing when nil
+	Logger *Logger
+}
+
+// FixupResult are the IDs added to and removed from the translation files, the IDs updated by previous ID, and the
+// keys of the IDs migrated with MigrateKeys
+type FixupResult struct {
+	AddedIDs     []string
+	RemovedIDs   []string
+	UpdatedIDs   map[string]string
+	MigratedKeys map[string][]string
+}
+
+// Fixup adds the new strings of the T(...) calls of the code to the translation files of all locales, and removes
+// the strings which are not used anymore, or migrates their IDs to keys
+func Fixup(fixupOptions FixupOptions) (*FixupResult, error) {
+	options := common.Options{
+		SourceDirFlag:    defaultDirname(fixupOptions.SourceDirname),
+		ResourceDirFlag:  defaultDirname(fixupOptions.ResourceDirname),
+		QualifierFlag:    fixupOptions.Qualifier,
+		IgnoreRegexpFlag: fixupOptions.IgnoreRegexp,
+		MigrateKeysFlag:  fixupOptions.MigrateKeys,
+		KeyFormatFlag:    fixupOptions.KeyFormat,
+		JobsFlag:         fixupOptions.Jobs,
+		CacheDirFlag:     fixupOptions.CacheDirname,
+		FS:               fixupOptions.FS,
+		Logger:           logger(fixupOptions.Logger),
+	}
+	fixupOptions.Sources.apply(&options)
+
+	fixup := cmds.NewFixup(&options)
+	fixup.Output = ioutil.Discard
+	fixup.ResolveUpdate = fixupOptions.ResolveUpdate
+	if fixup.ResolveUpdate == nil {
+		fixup.ResolveUpdate = func(string, []string) string {
+			return ""
+		}
+	}
+	err := fixup.Run()
+
+	return &FixupResult{
+		AddedIDs:     fixup.AddedIDs,
+		RemovedIDs:   fixup.RemovedIDs,
+		UpdatedIDs:   fixup.UpdatedIDs,
+		MigratedKeys: fixup.MigratedKeys,
+	}, err
+}
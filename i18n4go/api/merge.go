@@ -0,0 +1,86 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+
+	"github.com/maximilien/i18n4go/i18n4go/cmds"
+	"github.com/maximilien/i18n4go/i18n4go/common"
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+// MergeOptions are the options of merge-strings
+type MergeOptions struct {
+	// Dirname is the directory of the <filename>.go.<language>.json files to merge, and of its subdirectories with
+	// Recurse
+	Dirname string
+	Recurse bool
+
+	// SourceLanguage of the files, en by default
+	SourceLanguage string
+
+	// DryRun merges the files without saving the all.<language>.json files
+	DryRun bool
+
+	// FS is the file system read and written, the OS file system when nil
+	FS FS
+
+	// Logger logs the verbose output, warnings and errors of the command, nothing when nil
+	Logger *Logger
+}
+
+// MergeResult are the merged all.<language>.json files, in the order they are saved
+type MergeResult struct {
+	Files []MergedFile
+}
+
+// MergedFile is a merged all.<language>.json file with its translations sorted by ID
+type MergedFile struct {
+	Filename     string
+	Translations []Translation
+}
+
+// Merge merges the translation files of the go files of a directory into an all.<language>.json file, the result is
+// returned with the error of the directories merged before it
+func Merge(mergeOptions MergeOptions) (*MergeResult, error) {
+	if mergeOptions.Dirname == "" {
+		return nil, errors.New(i18n.T("i18n4go: a directory is required"))
+	}
+
+	sourceLanguage := mergeOptions.SourceLanguage
+	if sourceLanguage == "" {
+		sourceLanguage = "en"
+	}
+
+	options := common.Options{
+		DirnameFlag:        mergeOptions.Dirname,
+		RecurseFlag:        mergeOptions.Recurse,
+		SourceLanguageFlag: sourceLanguage,
+		DryRunFlag:         mergeOptions.DryRun,
+		FS:                 mergeOptions.FS,
+		Logger:             logger(mergeOptions.Logger),
+	}
+
+	mergeStrings := cmds.NewMergeStrings(&options)
+	err := mergeStrings.Run()
+
+	result := &MergeResult{}
+	for _, mergedFile := range mergeStrings.MergedFiles {
+		result.Files = append(result.Files, MergedFile{Filename: mergedFile.Filename, Translations: translations(mergedFile.I18nStringInfos)})
+	}
+
+	return result, err
+}
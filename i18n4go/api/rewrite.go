@@ -0,0 +1,121 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"io/ioutil"
+
+	"github.com/maximilien/i18n4go/i18n4go/cmds"
+	"github.com/maximilien/i18n4go/i18n4go/common"
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+// RewriteOptions are the options of rewrite-package
+type RewriteOptions struct {
+	// Filename is the go file to rewrite, or Dirname the directory of the go files, and of its subdirectories with
+	// Recurse
+	Filename string
+	Dirname  string
+	Recurse  bool
+
+	// I18nStringsFilename is the JSON file of the strings to translate, or I18nStringsDirname the directory of the
+	// JSON files of each go file
+	I18nStringsFilename string
+	I18nStringsDirname  string
+
+	// OutputDirname is the directory of the rewritten files, the files are overwritten by default
+	OutputDirname string
+
+	// RootPath is the root of the rewritten packages, the working directory by default
+	RootPath string
+
+	// InitCodeSnippetFilename is the template of the generated i18n_init.go files, regenerated with RegenerateInit
+	InitCodeSnippetFilename string
+	RegenerateInit          bool
+
+	// Qualifier of the i18n.T(...) calls and TFuncNames the names of the functions translating strings, whose calls
+	// are not rewritten again
+	Qualifier  string
+	TFuncNames []string
+
+	// IgnoreRegexp matches the names of the files to ignore
+	IgnoreRegexp string
+
+	// KeyFormat of the IDs of the rewritten strings, TEXT_KEY_FORMAT by default
+	KeyFormat string
+
+	// Diff returns the unified diff of the files instead of writing them
+	Diff bool
+
+	Sources SourceOptions
+
+	// FS is the file system read and written, the OS file system when nil
+	FS FS
+
+	// Logger logs the verbose output, warnings and errors of the command, nothing when nil
+	Logger *Logger
+}
+
+// RewriteResult are the files written by rewrite-package, or the unified diff of the files with the diff option
+type RewriteResult struct {
+	WrittenFiles []string
+	Diff         string
+	TotalFiles   int
+	TotalStrings int
+}
+
+// Rewrite wraps the strings of the go files to translate with T(...) calls, the result is returned with the error of
+// the files rewritten before it
+func Rewrite(rewriteOptions RewriteOptions) (*RewriteResult, error) {
+	if rewriteOptions.Filename == "" && rewriteOptions.Dirname == "" {
+		return nil, errors.New(i18n.T("i18n4go: a file or a directory is required"))
+	}
+
+	options := common.Options{
+		FilenameFlag:                rewriteOptions.Filename,
+		DirnameFlag:                 rewriteOptions.Dirname,
+		RecurseFlag:                 rewriteOptions.Recurse,
+		I18nStringsFilenameFlag:     rewriteOptions.I18nStringsFilename,
+		I18nStringsDirnameFlag:      rewriteOptions.I18nStringsDirname,
+		OutputDirFlag:               rewriteOptions.OutputDirname,
+		RootPathFlag:                rewriteOptions.RootPath,
+		InitCodeSnippetFilenameFlag: rewriteOptions.InitCodeSnippetFilename,
+		RegenerateInitFlag:          rewriteOptions.RegenerateInit,
+		QualifierFlag:               rewriteOptions.Qualifier,
+		TFuncNamesFlag:              joinList(rewriteOptions.TFuncNames, common.DEFAULT_T_FUNC_NAMES),
+		IgnoreRegexpFlag:            rewriteOptions.IgnoreRegexp,
+		KeyFormatFlag:               rewriteOptions.KeyFormat,
+		DiffFlag:                    rewriteOptions.Diff,
+		FS:                          rewriteOptions.FS,
+		Logger:                      logger(rewriteOptions.Logger),
+	}
+	rewriteOptions.Sources.apply(&options)
+
+	rewritePackage := cmds.NewRewritePackage(&options)
+	rewritePackage.Output = ioutil.Discard
+	err := rewritePackage.Run()
+
+	result := &RewriteResult{
+		WrittenFiles: rewritePackage.WrittenFiles,
+		TotalFiles:   rewritePackage.TotalFiles,
+		TotalStrings: rewritePackage.TotalStrings,
+	}
+	if rewriteOptions.Diff {
+		result.Diff = rewritePackage.Diff()
+	}
+
+	return result, err
+}
@@ -0,0 +1,120 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/maximilien/i18n4go/i18n4go/cmds"
+	"github.com/maximilien/i18n4go/i18n4go/common"
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+// VerifyOptions are the options of verify-strings
+type VerifyOptions struct {
+	// Filename is the source language translation file
+	Filename string
+
+	// SourceLanguage of the source file, and Languages of the target files in the directory of the source file, or
+	// LanguageFilenames the target files
+	SourceLanguage    string
+	Languages         []string
+	LanguageFilenames []string
+
+	// OutputDirname is the directory of the diff files of the problems, the directory of the target files by default
+	OutputDirname string
+
+	// SkipChecks are the translation checks to skip, and GlossaryFilename the JSON file of the glossary
+	SkipChecks       []string
+	GlossaryFilename string
+
+	// FS is the file system read and written, the OS file system when nil
+	FS FS
+
+	// Logger logs the verbose output, warnings and errors of the command, nothing when nil
+	Logger *Logger
+}
+
+// VerifyResult are the problems of each target file, in the order they are verified
+type VerifyResult struct {
+	Files []VerifiedFile
+}
+
+// VerifiedFile are the problems of a target file, i.e., the IDs of the source file missing from the target file, the
+// IDs of the target file not in the source file and the translations failing the checks
+type VerifiedFile struct {
+	Filename            string
+	Locale              string
+	MissingIDs          []string
+	ExtraIDs            []string
+	InvalidTranslations []InvalidTranslation
+}
+
+// InvalidTranslation is a translation failing the checks, with their problems
+type InvalidTranslation struct {
+	ID          string
+	Translation string
+	Problems    []string
+}
+
+// Verify verifies the target translation files against the source translation file, like verify-strings an error
+// is returned when a target file has problems, detailed by the result
+func Verify(verifyOptions VerifyOptions) (*VerifyResult, error) {
+	if verifyOptions.Filename == "" {
+		return nil, errors.New(i18n.T("i18n4go: a source translation file is required"))
+	}
+
+	sourceLanguage := verifyOptions.SourceLanguage
+	if sourceLanguage == "" {
+		sourceLanguage = "en"
+	}
+
+	options := common.Options{
+		FilenameFlag:         verifyOptions.Filename,
+		SourceLanguageFlag:   sourceLanguage,
+		LanguagesFlag:        strings.Join(verifyOptions.Languages, ","),
+		LanguageFilesFlag:    strings.Join(verifyOptions.LanguageFilenames, ","),
+		OutputDirFlag:        verifyOptions.OutputDirname,
+		SkipChecksFlag:       strings.Join(verifyOptions.SkipChecks, ","),
+		GlossaryFilenameFlag: verifyOptions.GlossaryFilename,
+		FS:                   verifyOptions.FS,
+		Logger:               logger(verifyOptions.Logger),
+	}
+
+	verifyStrings := cmds.NewVerifyStrings(&options)
+	err := verifyStrings.Run()
+
+	result := &VerifyResult{}
+	for _, verifiedFile := range verifyStrings.VerifiedFiles {
+		file := VerifiedFile{
+			Filename:   verifiedFile.Filename,
+			Locale:     verifiedFile.Locale,
+			MissingIDs: verifiedFile.MissingIDs,
+			ExtraIDs:   verifiedFile.ExtraIDs,
+		}
+		for _, invalidStringInfo := range verifiedFile.InvalidStringInfos {
+			file.InvalidTranslations = append(file.InvalidTranslations, InvalidTranslation{
+				ID:          invalidStringInfo.ID,
+				Translation: invalidStringInfo.Translation,
+				Problems:    invalidStringInfo.Problems,
+			})
+		}
+
+		result.Files = append(result.Files, file)
+	}
+
+	return result, err
+}
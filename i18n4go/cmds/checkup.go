@@ -17,9 +17,9 @@ package cmds
 import (
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -28,18 +28,50 @@ import (
 	"github.com/maximilien/i18n4go/i18n4go/i18n"
 )
 
+// StringMismatch is a string found in the code or the translation files of a locale, but not in the other, the
+// locale of the code is empty
+type StringMismatch struct {
+	ID          string
+	FoundIn     string
+	MissingFrom string
+}
+
+// StringGlossaryViolation is a translation of a locale violating the glossary
+type StringGlossaryViolation struct {
+	ID string
+	common.GlossaryViolation
+}
+
 type Checkup struct {
+	commandLogger
+
 	options common.Options
 
 	I18nStringInfos []common.I18nStringInfo
 	IgnoreRegexp    *regexp.Regexp
+
+	Glossary *common.Glossary
+
+	// SourceDirname and ResourceDirname are the directories of the go files and of the translation files, the
+	// current directory by default
+	SourceDirname   string
+	ResourceDirname string
+
+	// Mismatches and GlossaryViolations are the problems found, sorted by locale and ID
+	Mismatches         []StringMismatch
+	GlossaryViolations []StringGlossaryViolation
 }
 
 func NewCheckup(options *common.Options) *Checkup {
+	commandLogger := newCommandLogger(*options)
+
 	return &Checkup{
+		commandLogger:   commandLogger,
 		options:         *options,
 		I18nStringInfos: []common.I18nStringInfo{},
-		IgnoreRegexp:    common.GetIgnoreRegexp(options.IgnoreRegexpFlag),
+		IgnoreRegexp:    common.GetIgnoreRegexpWithLogger(commandLogger.logger, options.IgnoreRegexpFlag),
+		SourceDirname:   ".",
+		ResourceDirname: ".",
 	}
 }
 
@@ -56,6 +88,10 @@ func NewCheckupCommand(options *common.Options) *cobra.Command {
 	checkupCmd.Flags().StringVarP(&options.QualifierFlag, "qualifier", "q", "", i18n.T("[optional] the qualifier string that is used when using the i18n.T(...) function, default to nothing but could be set to `i18n` so that all calls would be: i18n.T(...)"))
 	// TODO: Optional flags shouldn't have set defaults. We should look into removing the default
 	checkupCmd.Flags().StringVar(&options.IgnoreRegexpFlag, "ignore-regexp", ".*test.*", i18n.T("recursively extract strings from all files in the same directory as filename or dirName"))
+	checkupCmd.Flags().StringVar(&options.GlossaryFilenameFlag, "glossary", "", i18n.T("[optional] the glossary JSON file with the terms that must not be translated or must be translated with specific terms per locale"))
+	checkupCmd.Flags().IntVar(&options.JobsFlag, "jobs", 0, i18n.T("[optional] the number of files inspected concurrently, defaults to the number of CPUs"))
+	checkupCmd.Flags().StringVar(&options.CacheDirFlag, "cache-dir", "", i18n.T("[optional] the directory of the cache of the strings of the go files, so that only the files which changed are parsed again"))
+	addSourceWalkerFlags(checkupCmd, options)
 	return checkupCmd
 }
 
@@ -63,61 +99,64 @@ func (cu *Checkup) Options() common.Options {
 	return cu.options
 }
 
-func (cu *Checkup) Println(a ...any) (int, error) {
-	if cu.options.VerboseFlag {
-		return fmt.Println(a...)
-	}
-
-	return 0, nil
-}
-
-func (cu *Checkup) Printf(msg string, a ...any) (int, error) {
-	if cu.options.VerboseFlag {
-		return fmt.Printf(msg, a...)
-	}
-
-	return 0, nil
-}
-
 func (cu *Checkup) Run() error {
 	//FIND PROBLEMS HERE AND RETURN AN ERROR
 	sourceStrings, err := cu.findSourceStrings()
 
 	if err != nil {
-		cu.Println(i18n.T("Couldn't find any source strings: {{.Arg0}}", map[string]any{"Arg0": err.Error()}))
+		cu.logger.Debug(i18n.T("Couldn't find any source strings: {{.Arg0}}", map[string]any{"Arg0": err.Error()}))
 		return err
 	}
 
-	locales := findTranslationFiles(".", cu.IgnoreRegexp, false)
+	locales := findTranslationFiles(cu.options.FileSystem(), cu.ResourceDirname, cu.IgnoreRegexp)
 
 	englishFiles := locales["en_US"]
 	if englishFiles == nil {
-		cu.Println(i18n.T("Could not find an i18n file for locale: en_US"))
-		return errors.New(i18n.T("Could not find an i18n file for locale: en_US"))
+		cu.logger.Debug(i18n.T("Could not find an i18n file for locale: en_US"))
+		return common.NewIOError(errors.New(i18n.T("Could not find an i18n file for locale: en_US")))
 	}
 
 	englishStrings, err := cu.findI18nStrings(englishFiles)
 
 	if err != nil {
-		cu.Println(i18n.T("Couldn't find the english strings: {{.Arg0}}", map[string]any{"Arg0": err.Error()}))
+		cu.logger.Debug(i18n.T("Couldn't find the english strings: {{.Arg0}}", map[string]any{"Arg0": err.Error()}))
 		return err
 	}
 
-	err = cu.diffStrings(i18n.T("the code"), "en_US", sourceStrings, englishStrings)
+	if cu.options.GlossaryFilenameFlag != "" {
+		cu.Glossary, err = common.LoadGlossaryFS(cu.options.FileSystem(), cu.options.GlossaryFilenameFlag)
+		if err != nil {
+			cu.logger.Debug(i18n.T("i18n4go: Error loading the glossary file:"), cu.options.GlossaryFilenameFlag)
+			return err
+		}
+	}
 
-	for locale, i18nFiles := range locales {
+	err = cu.diffStrings("", "en_US", sourceStrings, englishStrings)
+
+	var glossaryErr error
+	for _, locale := range sortedKeys(locales) {
 		if locale == "en_US" {
 			continue
 		}
 
-		translatedStrings, err := cu.findI18nStrings(i18nFiles)
+		translatedStrings, err := cu.findI18nStrings(locales[locale])
 
 		if err != nil {
-			cu.Println(i18n.T("Couldn't get the strings from {{.Arg0}}: {{.Arg1}}", map[string]any{"Arg0": locale, "Arg1": err.Error()}))
+			cu.logger.Debug(i18n.T("Couldn't get the strings from {{.Arg0}}: {{.Arg1}}", map[string]any{"Arg0": locale, "Arg1": err.Error()}))
 			return err
 		}
 
 		err = cu.diffStrings("en_US", locale, englishStrings, translatedStrings)
+
+		if cu.Glossary != nil {
+			if violationsErr := cu.checkGlossary(locale, englishStrings, translatedStrings); violationsErr != nil {
+				glossaryErr = violationsErr
+			}
+		}
+	}
+
+	if glossaryErr != nil {
+		err = glossaryErr
 	}
 
 	if err == nil {
@@ -127,19 +166,17 @@ func (cu *Checkup) Run() error {
 	return err
 }
 
-func getGoFiles(dir string) (files []string) {
-	contents, _ := ioutil.ReadDir(dir)
-
-	for _, fileInfo := range contents {
-		if !fileInfo.IsDir() {
-			name := fileInfo.Name()
+// getGoFiles returns the go files of the source tree of a directory, except the tests and the files skipped by the
+// source walker of the options
+func getGoFiles(dir string, options common.Options) (files []string, err error) {
+	walker, err := common.NewSourceWalker(options)
+	if err != nil {
+		return nil, err
+	}
 
-			if strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") {
-				files = append(files, filepath.Join(dir, fileInfo.Name()))
-			}
-		} else {
-			moreFiles := getGoFiles(filepath.Join(dir, fileInfo.Name()))
-			files = append(files, moreFiles...)
+	for _, file := range walker.GoFiles(dir, true) {
+		if !strings.HasSuffix(file, "_test.go") {
+			files = append(files, file)
 		}
 	}
 	return
@@ -147,15 +184,24 @@ func getGoFiles(dir string) (files []string) {
 
 func (cu *Checkup) findSourceStrings() (sourceStrings map[string]string, err error) {
 	sourceStrings = make(map[string]string)
-	files := getGoFiles(".")
+	files, err := getGoFiles(cu.SourceDirname, cu.options)
+	if err != nil {
+		return sourceStrings, err
+	}
 
-	for _, file := range files {
-		fileStrings, err := common.InspectFile(file, cu.options)
+	cache := common.NewTCallsCache(cu.options)
+	filesStrings, err := common.MapConcurrently(files, common.Jobs(cu.options), func(file string) ([]string, error) {
+		fileStrings, err := common.InspectCachedFileWithLogger(cu.logger, cache, file, cu.options)
 		if err != nil {
-			cu.Println(i18n.T("Error when inspecting go file: "), file)
-			return sourceStrings, err
+			cu.logger.Debug(i18n.T("Error when inspecting go file: "), file)
 		}
+		return fileStrings, err
+	})
+	if err != nil {
+		return sourceStrings, err
+	}
 
+	for _, fileStrings := range filesStrings {
 		for _, string := range fileStrings {
 			sourceStrings[string] = string
 		}
@@ -168,8 +214,8 @@ func (cu *Checkup) findSourceStrings() (sourceStrings map[string]string, err err
 // and finds all files that fit a certain pattern (e.g. regex).
 //
 // This would be fairly useful in finding translation files/source files.
-func getI18nFile(locale, dir string) (filePath string) {
-	contents, _ := ioutil.ReadDir(dir)
+func getI18nFile(fsys common.FS, locale, dir string) (filePath string) {
+	contents, _ := fsys.ReadDir(dir)
 
 	for _, fileInfo := range contents {
 		if !fileInfo.IsDir() {
@@ -181,7 +227,7 @@ func getI18nFile(locale, dir string) (filePath string) {
 				break
 			}
 		} else {
-			filePath = getI18nFile(locale, filepath.Join(dir, fileInfo.Name()))
+			filePath = getI18nFile(fsys, locale, filepath.Join(dir, fileInfo.Name()))
 
 			if filePath != "" {
 				break
@@ -192,24 +238,16 @@ func getI18nFile(locale, dir string) (filePath string) {
 	return
 }
 
-func findTranslationFiles(dir string, ignoreRegexp *regexp.Regexp, verbose bool) (locales map[string][]string) {
+func findTranslationFiles(fsys common.FS, dir string, ignoreRegexp *regexp.Regexp) (locales map[string][]string) {
 	locales = make(map[string][]string)
-	contents, _ := ioutil.ReadDir(dir)
+	contents, _ := fsys.ReadDir(dir)
 
 	for _, fileInfo := range contents {
 		if !fileInfo.IsDir() {
 			name := fileInfo.Name()
 
 			if strings.HasSuffix(name, ".json") {
-				parts := strings.Split(name, ".")
-				var locale string
-
-				for _, part := range parts {
-					invalidLangRegexp, _ := regexp.Compile("excluded|json|all")
-					if !invalidLangRegexp.MatchString(part) {
-						locale = part
-					}
-				}
+				locale := common.GetLocaleFromFilename(name)
 
 				// No locale found so skipping
 				if locale == "" {
@@ -228,7 +266,7 @@ func findTranslationFiles(dir string, ignoreRegexp *regexp.Regexp, verbose bool)
 					continue
 				}
 			}
-			for locale, files := range findTranslationFiles(filepath.Join(dir, fileInfo.Name()), ignoreRegexp, verbose) {
+			for locale, files := range findTranslationFiles(fsys, filepath.Join(dir, fileInfo.Name()), ignoreRegexp) {
 				if locales[locale] == nil {
 					locales[locale] = []string{}
 				}
@@ -245,7 +283,7 @@ func (cu *Checkup) findI18nStrings(i18nFiles []string) (i18nStrings map[string]s
 	i18nStrings = make(map[string]string)
 
 	for _, i18nFile := range i18nFiles {
-		stringInfos, err := common.LoadI18nStringInfos(i18nFile)
+		stringInfos, err := common.LoadI18nStringInfosFS(cu.options.FileSystem(), i18nFile)
 
 		if err != nil {
 			return nil, err
@@ -259,18 +297,52 @@ func (cu *Checkup) findI18nStrings(i18nFiles []string) (i18nStrings map[string]s
 	return
 }
 
-func (cu *Checkup) diffStrings(sourceNameOne, sourceNameTwo string, stringsOne, stringsTwo map[string]string) (err error) {
-	for key, _ := range stringsOne {
+// diffStrings reports the strings of a locale which are not in the other, the empty locale is the code
+func (cu *Checkup) diffStrings(localeOne, localeTwo string, stringsOne, stringsTwo map[string]string) (err error) {
+	for _, key := range sortedKeys(stringsOne) {
 		if stringsTwo[key] == "" {
-			cu.Printf(i18n.T("\"{{.Arg0}}\" exists in {{.Arg1}}, but not in {{.Arg2}}\n", map[string]any{"Arg0": key, "Arg1": sourceNameOne, "Arg2": sourceNameTwo}))
-			err = errors.New(i18n.T("Strings don't match"))
+			cu.Printf(i18n.T("\"{{.Arg0}}\" exists in {{.Arg1}}, but not in {{.Arg2}}\n", map[string]any{"Arg0": key, "Arg1": sourceName(localeOne), "Arg2": sourceName(localeTwo)}))
+			cu.Mismatches = append(cu.Mismatches, StringMismatch{ID: key, FoundIn: localeOne, MissingFrom: localeTwo})
+			err = common.NewValidationError(errors.New(i18n.T("Strings don't match")))
 		}
 	}
 
-	for key, _ := range stringsTwo {
+	for _, key := range sortedKeys(stringsTwo) {
 		if stringsOne[key] == "" {
-			cu.Printf(i18n.T("\"{{.Arg0}}\" exists in {{.Arg1}}, but not in {{.Arg2}}\n", map[string]any{"Arg0": key, "Arg1": sourceNameTwo, "Arg2": sourceNameOne}))
-			err = errors.New(i18n.T("Strings don't match"))
+			cu.Printf(i18n.T("\"{{.Arg0}}\" exists in {{.Arg1}}, but not in {{.Arg2}}\n", map[string]any{"Arg0": key, "Arg1": sourceName(localeTwo), "Arg2": sourceName(localeOne)}))
+			cu.Mismatches = append(cu.Mismatches, StringMismatch{ID: key, FoundIn: localeTwo, MissingFrom: localeOne})
+			err = common.NewValidationError(errors.New(i18n.T("Strings don't match")))
+		}
+	}
+
+	return
+}
+
+func sourceName(locale string) string {
+	if locale == "" {
+		return i18n.T("the code")
+	}
+
+	return locale
+}
+
+func (cu *Checkup) checkGlossary(locale string, englishStrings, translatedStrings map[string]string) (err error) {
+	var keys []string
+	for key := range translatedStrings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		source := englishStrings[key]
+		if source == "" {
+			source = key
+		}
+
+		for _, violation := range cu.Glossary.Violations(locale, source, translatedStrings[key]) {
+			cu.Printf(i18n.T("\"{{.Arg0}}\" in {{.Arg1}} violates the glossary for term \"{{.Arg2}}\": {{.Arg3}}\n", map[string]any{"Arg0": key, "Arg1": locale, "Arg2": violation.Term, "Arg3": violation.Message}))
+			cu.GlossaryViolations = append(cu.GlossaryViolations, StringGlossaryViolation{ID: key, GlossaryViolation: violation})
+			err = common.NewValidationError(errors.New(i18n.T("Translations violate the glossary")))
 		}
 	}
 
@@ -21,5 +21,45 @@ import (
 type CommandInterface interface {
 	common.PrinterInterface
 	Options() common.Options
+	Logger() *common.Logger
+	SetLogger(logger *common.Logger)
 	Run() error
 }
+
+// commandLogger is embedded in the commands to log their verbose output, warnings and errors with the logger of
+// their options, or the one injected with SetLogger
+type commandLogger struct {
+	logger *common.Logger
+}
+
+// newCommandLogger returns the logger of the options, or of their log flags, which are checked before the commands
+// are created so that an invalid flag falls back to the default logger
+func newCommandLogger(options common.Options) commandLogger {
+	if options.Logger != nil {
+		return commandLogger{logger: options.Logger}
+	}
+
+	logger, err := common.NewLogger(options)
+	if err != nil {
+		logger, _ = common.NewLogger(common.Options{})
+	}
+
+	return commandLogger{logger: logger}
+}
+
+func (cl *commandLogger) Logger() *common.Logger {
+	return cl.logger
+}
+
+// SetLogger sets the logger of the command, nil to log nothing
+func (cl *commandLogger) SetLogger(logger *common.Logger) {
+	cl.logger = logger
+}
+
+func (cl *commandLogger) Println(a ...any) (int, error) {
+	return cl.logger.Println(a...)
+}
+
+func (cl *commandLogger) Printf(msg string, a ...any) (int, error) {
+	return cl.logger.Printf(msg, a...)
+}
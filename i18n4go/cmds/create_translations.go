@@ -15,6 +15,7 @@
 package cmds
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -32,6 +33,8 @@ import (
 )
 
 type createTranslations struct {
+	commandLogger
+
 	options common.Options
 
 	Filename       string
@@ -63,6 +66,7 @@ func NewCreateTranslations(options *common.Options) *createTranslations {
 	languages := common.ParseStringList(options.LanguagesFlag, ",")
 
 	return &createTranslations{options: *options,
+		commandLogger:  newCommandLogger(*options),
 		Filename:       options.FilenameFlag,
 		OutputDirname:  options.OutputDirFlag,
 		SourceLanguage: options.SourceLanguageFlag,
@@ -97,22 +101,6 @@ func (ct *createTranslations) Options() common.Options {
 	return ct.options
 }
 
-func (ct *createTranslations) Println(a ...interface{}) (int, error) {
-	if ct.options.VerboseFlag {
-		return fmt.Println(a...)
-	}
-
-	return 0, nil
-}
-
-func (ct *createTranslations) Printf(msg string, a ...interface{}) (int, error) {
-	if ct.options.VerboseFlag {
-		return fmt.Printf(msg, a...)
-	}
-
-	return 0, nil
-}
-
 func (ct *createTranslations) Run() error {
 	ct.Println(i18n.T("i18n4go: creating translation files for:"), ct.Filename)
 	ct.Println()
@@ -123,13 +111,13 @@ func (ct *createTranslations) Run() error {
 		if ct.options.GoogleTranslateApiKeyFlag != "" {
 			destFilename, err := ct.createTranslationFileWithGoogleTranslate(language)
 			if err != nil {
-				return fmt.Errorf(i18n.T("i18n4go: could not create translation file for language: {{.Arg0}} with Google Translate", map[string]interface{}{"Arg0": language}))
+				return common.WithCategoryOf(err, fmt.Errorf(i18n.T("i18n4go: could not create translation file for language: {{.Arg0}} with Google Translate", map[string]interface{}{"Arg0": language})))
 			}
 			ct.Println(i18n.T("i18n4go: created translation file with Google Translate:"), destFilename)
 		} else {
 			destFilename, err := ct.createTranslationFile(ct.Filename, language)
 			if err != nil {
-				return fmt.Errorf(i18n.T("i18n4go: could not create default translation file for language: {{.Arg0}}\nerr:{{.Arg1}}", map[string]interface{}{"Arg0": language, "Arg1": err.Error()}))
+				return common.WithCategoryOf(err, fmt.Errorf(i18n.T("i18n4go: could not create default translation file for language: {{.Arg0}}\nerr:{{.Arg1}}", map[string]interface{}{"Arg0": language, "Arg1": err.Error()})))
 			}
 			ct.Println(i18n.T("i18n4go: created default translation file:"), destFilename)
 		}
@@ -141,35 +129,40 @@ func (ct *createTranslations) Run() error {
 }
 
 func (ct *createTranslations) createTranslationFileWithGoogleTranslate(language string) (string, error) {
-	fileName, _, err := common.CheckFile(ct.Filename)
+	fileName, _, err := common.CheckFileFS(ct.options.FileSystem(), ct.Filename)
 	if err != nil {
 		return "", err
 	}
 
-	err = common.CreateOutputDirsIfNeeded(ct.OutputDirname)
+	err = common.CreateOutputDirsIfNeededFS(ct.options.FileSystem(), ct.OutputDirname)
 	if err != nil {
 		ct.Println(err)
-		return "", fmt.Errorf(i18n.T("i18n4go: could not create output directory: {{.Arg0}}", map[string]interface{}{"Arg0": ct.OutputDirname}))
+		return "", common.NewIOError(fmt.Errorf(i18n.T("i18n4go: could not create output directory: {{.Arg0}}", map[string]interface{}{"Arg0": ct.OutputDirname})))
 	}
 
 	destFilename := filepath.Join(ct.OutputDirname, strings.Replace(fileName, ct.options.SourceLanguageFlag, language, -1))
 
-	i18nStringInfos, err := common.LoadI18nStringInfos(ct.Filename)
+	i18nStringInfos, err := common.LoadI18nStringInfosFS(ct.options.FileSystem(), ct.Filename)
 	if err != nil {
 		ct.Println(err)
-		return "", fmt.Errorf(i18n.T("i18n4go: could not load i18n strings from file: {{.Arg0}}", map[string]interface{}{"Arg0": ct.Filename}))
+		return "", common.WithCategoryOf(err, fmt.Errorf(i18n.T("i18n4go: could not load i18n strings from file: {{.Arg0}}", map[string]interface{}{"Arg0": ct.Filename})))
 	}
 
 	if len(i18nStringInfos) == 0 {
-		return "", fmt.Errorf(i18n.T("i18n4go: input file: {{.Arg0}} is empty", map[string]interface{}{"Arg0": ct.Filename}))
+		return "", common.NewValidationError(fmt.Errorf(i18n.T("i18n4go: input file: {{.Arg0}} is empty", map[string]interface{}{"Arg0": ct.Filename})))
 	}
 
 	ct.Println(i18n.T("i18n4go: attempting to use Google Translate to translate source strings in: "), language)
+	// the strings which could not be translated are left empty, the file is saved and the first error returned
+	var translateErr error
 	modifiedI18nStringInfos := make([]common.I18nStringInfo, len(i18nStringInfos))
 	for i, i18nStringInfo := range i18nStringInfos {
 		translation, _, err := ct.googleTranslate(i18nStringInfo.Translation, language)
 		if err != nil {
-			ct.Println(i18n.T("i18n4go: error invoking Google Translate for string:"), i18nStringInfo.Translation)
+			ct.logger.Warn(i18n.T("i18n4go: error invoking Google Translate for string:"), i18nStringInfo.Translation)
+			if translateErr == nil {
+				translateErr = err
+			}
 		} else {
 			modifiedI18nStringInfos[i] = common.I18nStringInfo{ID: i18nStringInfo.ID, Translation: translation}
 		}
@@ -178,7 +171,7 @@ func (ct *createTranslations) createTranslationFileWithGoogleTranslate(language
 	err = common.SaveI18nStringInfos(ct, ct.Options(), modifiedI18nStringInfos, destFilename)
 	if err != nil {
 		ct.Println(err)
-		return "", fmt.Errorf(i18n.T("i18n4go: could not save Google Translate i18n strings to file: {{.Arg0}}", map[string]interface{}{"Arg0": destFilename}))
+		return "", common.NewIOError(fmt.Errorf(i18n.T("i18n4go: could not save Google Translate i18n strings to file: {{.Arg0}}", map[string]interface{}{"Arg0": destFilename})))
 	}
 
 	if ct.options.PoFlag {
@@ -186,35 +179,35 @@ func (ct *createTranslations) createTranslationFileWithGoogleTranslate(language
 		err = common.SaveI18nStringsInPo(ct, ct.Options(), modifiedI18nStringInfos, poFilename)
 		if err != nil {
 			ct.Println(err)
-			return "", fmt.Errorf(i18n.T("i18n4go: could not save PO file: {{.Arg0}}", map[string]interface{}{"Arg0": poFilename}))
+			return "", common.NewIOError(fmt.Errorf(i18n.T("i18n4go: could not save PO file: {{.Arg0}}", map[string]interface{}{"Arg0": poFilename})))
 		}
 	}
 
 	ct.Println()
 
-	return destFilename, nil
+	return destFilename, translateErr
 }
 
 func (ct *createTranslations) createTranslationFile(sourceFilename string, language string) (string, error) {
-	fileName, _, err := common.CheckFile(sourceFilename)
+	fileName, _, err := common.CheckFileFS(ct.options.FileSystem(), sourceFilename)
 	if err != nil {
 		return "", err
 	}
 
-	i18nStringInfos, err := common.LoadI18nStringInfos(sourceFilename)
+	i18nStringInfos, err := common.LoadI18nStringInfosFS(ct.options.FileSystem(), sourceFilename)
 	if err != nil {
 		ct.Println(err)
-		return "", fmt.Errorf(i18n.T("i18n4go: could not load i18n strings from file: {{.Arg0}}", map[string]interface{}{"Arg0": sourceFilename}))
+		return "", common.WithCategoryOf(err, fmt.Errorf(i18n.T("i18n4go: could not load i18n strings from file: {{.Arg0}}", map[string]interface{}{"Arg0": sourceFilename})))
 	}
 
 	if len(i18nStringInfos) == 0 {
-		return "", fmt.Errorf(i18n.T("i18n4go: input file: {{.Arg0}} is empty", map[string]interface{}{"Arg0": sourceFilename}))
+		return "", common.NewValidationError(fmt.Errorf(i18n.T("i18n4go: input file: {{.Arg0}} is empty", map[string]interface{}{"Arg0": sourceFilename})))
 	}
 
 	destFilename := filepath.Join(ct.OutputDirname, strings.Replace(fileName, ct.options.SourceLanguageFlag, language, -1))
 	ct.Println(i18n.T("i18n4go: creating translation file:"), destFilename)
 
-	return destFilename, common.CopyFileContents(sourceFilename, destFilename)
+	return destFilename, common.CopyFileContentsFS(ct.options.FileSystem(), sourceFilename, destFilename)
 }
 
 func (ct *createTranslations) googleTranslate(translateString string, language string) (string, string, error) {
@@ -223,24 +216,38 @@ func (ct *createTranslations) googleTranslate(translateString string, language s
 
 	response, err := http.Get(googleTranslateUrl)
 	if err != nil {
-		ct.Println(i18n.T("i18n4go: ERROR invoking Google Translate: "), googleTranslateUrl)
-		return "", "", err
+		// the error of the URL is not logged, it contains the API key
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		ct.logger.Debug(i18n.T("i18n4go: ERROR invoking Google Translate: "), err)
+		return "", "", common.NewProviderError(err)
 	}
 
 	defer response.Body.Close()
 
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		ct.logger.Debug(i18n.T("i18n4go: ERROR invoking Google Translate: "), response.Status)
+		return "", "", common.NewProviderError(errors.New(i18n.T("i18n4go: Google Translate responded with status {{.Arg0}}", map[string]any{"Arg0": response.Status})))
+	}
+
 	body, err := ioutil.ReadAll(response.Body)
 	if err != nil {
-		ct.Println(i18n.T("i18n4go: ERROR parsing Google Translate response body"))
-		return "", "", err
+		ct.logger.Debug(i18n.T("i18n4go: ERROR parsing Google Translate response body"))
+		return "", "", common.NewProviderError(err)
 	}
 
 	var googleTranslateData GoogleTranslateData
 	err = json.Unmarshal(body, &googleTranslateData)
 	if err != nil {
-		ct.Println(i18n.T("i18n4go: ERROR parsing Google Translate response body"))
-		return "", "", err
+		ct.logger.Debug(i18n.T("i18n4go: ERROR parsing Google Translate response body"))
+		return "", "", common.NewProviderError(err)
+	}
+
+	if len(googleTranslateData.Data.Translations) == 0 {
+		return "", "", common.NewProviderError(errors.New(i18n.T("i18n4go: Google Translate returned no translation for: {{.Arg0}}", map[string]any{"Arg0": translateString})))
 	}
 
-	return googleTranslateData.Data.Translations[0].TranslatedText, googleTranslateData.Data.Translations[0].DetectedSourceLanguage, err
+	return googleTranslateData.Data.Translations[0].TranslatedText, googleTranslateData.Data.Translations[0].DetectedSourceLanguage, nil
 }
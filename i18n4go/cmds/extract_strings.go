@@ -25,11 +25,11 @@ import (
 	"go/build"
 	"go/parser"
 	"go/token"
+	"io/fs"
 
 	"path/filepath"
 
 	"encoding/json"
-	"io/ioutil"
 
 	"github.com/spf13/cobra"
 
@@ -38,6 +38,8 @@ import (
 )
 
 type extractStrings struct {
+	commandLogger
+
 	options common.Options
 
 	i18nFilename string
@@ -57,11 +59,57 @@ type extractStrings struct {
 	TotalStrings    int
 	TotalFiles      int
 
+	// ExtractedFiles are the strings extracted from each file with their IDs, in the order the files are saved
+	ExtractedFiles []common.FileStringInfos
+
 	IgnoreRegexp *regexp.Regexp
+
+	// keyGenerator generates the IDs of the extracted strings with the key format
+	keyGenerator *common.KeyGenerator
+
+	// templateExtensions are the extensions of the template files extracted with the go files of a directory, and
+	// tFuncNames the names of the template functions whose string args are extracted
+	templateExtensions []string
+	tFuncNames         []string
+
+	// inspectedTemplateFiles are the template files already extracted, e.g., both embedded and found by extension
+	inspectedTemplateFiles map[string]bool
+
+	// filtersLoaded is set once the excluded strings and regexps and the substring regexps are loaded
+	filtersLoaded bool
+
+	// absFilePath, packageName, embedPatterns and excludedImports of the file whose strings are extracted by a copy
+	// of the extractor
+	absFilePath     string
+	packageName     string
+	embedPatterns   []string
+	excludedImports map[string]bool
+
+	// cache of the strings extracted from the go files, nil unless a cache directory is set
+	cache *common.Cache
+
+	// walker skips the directories and files of the inspected source tree which are not built or ignored
+	walker *common.SourceWalker
+}
+
+// cachedFile are the strings extracted from a go file saved in the cache, with its package name and the patterns of
+// its //go:embed directives
+type cachedFile struct {
+	PackageName   string                       `json:"packageName"`
+	Strings       map[string]common.StringInfo `json:"strings"`
+	EmbedPatterns []string                     `json:"embedPatterns"`
 }
 
 func NewExtractStrings(options *common.Options) *extractStrings {
+	commandLogger := newCommandLogger(*options)
+
+	tFuncNames := common.ParseStringList(options.TFuncNamesFlag, ",")
+	if len(tFuncNames) == 0 {
+		tFuncNames = common.ParseStringList(common.DEFAULT_T_FUNC_NAMES, ",")
+	}
+
 	return &extractStrings{options: *options,
+		commandLogger:    commandLogger,
 		Filename:         "extracted_strings.json",
 		OutputDirname:    options.OutputDirFlag,
 		ExtractedStrings: nil,
@@ -71,7 +119,11 @@ func NewExtractStrings(options *common.Options) *extractStrings {
 		TotalStringsDir:  0,
 		TotalStrings:     0,
 		TotalFiles:       0,
-		IgnoreRegexp:     common.GetIgnoreRegexp(options.IgnoreRegexpFlag),
+		IgnoreRegexp:     common.GetIgnoreRegexpWithLogger(commandLogger.logger, options.IgnoreRegexpFlag),
+
+		templateExtensions:     common.ParseStringList(options.TemplateExtensionsFlag, ","),
+		tFuncNames:             tFuncNames,
+		inspectedTemplateFiles: make(map[string]bool),
 	}
 }
 
@@ -79,7 +131,7 @@ func NewExtractStrings(options *common.Options) *extractStrings {
 func NewExtractStringsCommand(options *common.Options) *cobra.Command {
 	extractTranslationsCmd := &cobra.Command{
 		Use:   "extract-strings",
-		Short: i18n.T("Extract the translation strings from go source files"),
+		Short: i18n.T("Extract the translation strings from go source files and templates"),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return NewExtractStrings(options).Run()
 		},
@@ -97,11 +149,18 @@ func NewExtractStringsCommand(options *common.Options) *cobra.Command {
 	// We should rename this flag to have the default be fault
 	extractTranslationsCmd.Flags().BoolVar(&options.OutputFlatFlag, "output-flat", true, i18n.T("generated files are created in the specified output directory"))
 	extractTranslationsCmd.Flags().BoolVar(&options.OutputMatchPackageFlag, "output-match-package", false, i18n.T("generated files are created in directory to match the package name"))
+	extractTranslationsCmd.Flags().BoolVar(&options.OutputMatchImportFlag, "output-match-import", false, i18n.T("generated files are created in directory to match the import path of the package, resolved with the go.mod file of its module"))
 	extractTranslationsCmd.Flags().StringVarP(&options.OutputDirFlag, "output", "o", "", i18n.T("output directory where the translation files will be placed"))
 	extractTranslationsCmd.Flags().StringVarP(&options.DirnameFlag, "directory", "d", "", i18n.T("the dir name for which all .go files will have their strings extracted"))
 	extractTranslationsCmd.Flags().BoolVarP(&options.RecurseFlag, "recursive", "r", false, i18n.T("recursively extract strings from all files in the same directory as filename or dirName"))
 	// Same as NOTE in L78-79
 	extractTranslationsCmd.Flags().StringVar(&options.IgnoreRegexpFlag, "ignore-regexp", ".*test.*", i18n.T("recursively extract strings from all files in the same directory as filename or dirName"))
+	extractTranslationsCmd.Flags().StringVar(&options.TemplateExtensionsFlag, "template-extensions", common.DEFAULT_TEMPLATE_EXTENSIONS, i18n.T("[optional] a comma separated list of the extensions of the text/template and html/template files whose strings are extracted with the go files of a directory"))
+	extractTranslationsCmd.Flags().StringVar(&options.TFuncNamesFlag, "t-func-names", common.DEFAULT_T_FUNC_NAMES, i18n.T("[optional] a comma separated list of the names of the template functions translating strings, whose string args are extracted from the templates"))
+	extractTranslationsCmd.Flags().StringVar(&options.KeyFormatFlag, "key-format", common.TEXT_KEY_FORMAT, i18n.T("[optional] the format of the IDs of the strings, one of: text, slug, hash, the slug and hash IDs are scoped by package, e.g., greetings.hello_world"))
+	extractTranslationsCmd.Flags().IntVar(&options.JobsFlag, "jobs", 0, i18n.T("[optional] the number of files inspected concurrently, defaults to the number of CPUs"))
+	extractTranslationsCmd.Flags().StringVar(&options.CacheDirFlag, "cache-dir", "", i18n.T("[optional] the directory of the cache of the strings of the go files, so that only the files which changed are parsed again"))
+	addSourceWalkerFlags(extractTranslationsCmd, options)
 
 	return extractTranslationsCmd
 }
@@ -110,23 +169,13 @@ func (es *extractStrings) Options() common.Options {
 	return es.options
 }
 
-func (es *extractStrings) Println(a ...interface{}) (int, error) {
-	if es.options.VerboseFlag {
-		return fmt.Println(a...)
-	}
-
-	return 0, nil
-}
-
-func (es *extractStrings) Printf(msg string, a ...interface{}) (int, error) {
-	if es.options.VerboseFlag {
-		return fmt.Printf(msg, a...)
+func (es *extractStrings) Run() error {
+	keyGenerator, err := common.NewKeyGenerator(es.options.KeyFormatFlag)
+	if err != nil {
+		return err
 	}
+	es.keyGenerator = keyGenerator
 
-	return 0, nil
-}
-
-func (es *extractStrings) Run() error {
 	if es.options.FilenameFlag != "" {
 		return es.InspectFile(es.options.FilenameFlag)
 	} else {
@@ -135,82 +184,232 @@ func (es *extractStrings) Run() error {
 			es.Println(i18n.T("i18n4go: could not extract strings from directory:"), es.options.DirnameFlag)
 			return err
 		}
-		es.Println()
-		es.Println(i18n.T("Total files parsed:"), es.TotalFiles)
-		es.Println(i18n.T("Total extracted strings:"), es.TotalStrings)
+		es.logger.Info()
+		es.logger.Info(i18n.T("Total files parsed:"), es.TotalFiles)
+		es.logger.Info(i18n.T("Total extracted strings:"), es.TotalStrings)
 	}
 	return nil
 }
 
 func (es *extractStrings) InspectFile(filename string) error {
-	es.Println(i18n.T("i18n4go: extracting strings from file:"), filename)
-	if es.options.DryRunFlag {
-		es.Println(i18n.T("WARNING running in -dry-run mode"))
+	err := es.loadFilters()
+	if err != nil {
+		return err
 	}
 
-	es.ExtractedStrings = make(map[string]common.StringInfo)
-	es.FilteredStrings = make(map[string]string)
-	es.FilteredRegexps = []*regexp.Regexp{}
+	fileExtractor, err := es.extractFile(filename)
+	if err != nil {
+		return err
+	}
 
-	es.setFilename(filename)
-	es.setI18nFilename(filename)
-	es.setPoFilename(filename)
+	return es.saveFile(fileExtractor)
+}
 
-	fset := token.NewFileSet()
+func (es *extractStrings) InspectDir(dirName string, recursive bool) error {
+	es.Printf(i18n.T("i18n4go: inspecting dir {{.Arg0}}, recursive: {{.Arg1}}\n", map[string]interface{}{"Arg0": dirName, "Arg1": recursive}))
+	es.Println()
 
-	var absFilePath = filename
-	if !filepath.IsAbs(absFilePath) {
-		absFilePath = filepath.Join(os.Getenv("PWD"), absFilePath)
-	}
+	es.TotalStringsDir = 0
 
-	fileInfo, err := common.GetAbsFileInfo(absFilePath)
+	err := es.loadFilters()
 	if err != nil {
-		es.Println(err)
-	}
-
-	if strings.HasPrefix(fileInfo.Name(), ".") {
-		es.Println(i18n.T("WARNING ignoring file:"), absFilePath)
-		return nil
+		return err
 	}
 
-	astFile, err := parser.ParseFile(fset, absFilePath, nil, parser.ParseComments|parser.AllErrors)
+	fileNames, err := es.goFileNames(dirName)
 	if err != nil {
 		es.Println(err)
 		return err
 	}
 
-	err = es.loadExcludedStrings()
+	// the files are extracted concurrently and saved in order so that the generated keys are stable
+	fileExtractors, err := common.MapConcurrently(fileNames, common.Jobs(es.options), es.extractFile)
 	if err != nil {
 		es.Println(err)
 		return err
 	}
-	es.Println(fmt.Sprintf(i18n.T("Loaded {{.Arg0}} excluded strings", map[string]interface{}{"Arg0": len(es.FilteredStrings)})))
 
-	err = es.loadExcludedRegexps()
+	var packageName string
+	var patterns []string
+	for _, fileExtractor := range fileExtractors {
+		if fileExtractor == nil {
+			continue
+		}
+
+		if fileExtractor.packageName != packageName {
+			packageName = fileExtractor.packageName
+			es.Println(i18n.T("Extracting strings in package:"), packageName)
+		}
+		patterns = append(patterns, fileExtractor.embedPatterns...)
+
+		err = es.saveFile(fileExtractor)
+		if err != nil {
+			es.logger.Error(err)
+		}
+	}
+
+	err = es.inspectTemplateFiles(dirName, patterns)
+	if err != nil {
+		es.logger.Error(err)
+	}
+	es.Printf(i18n.T("Extracted total of {{.Arg0}} strings\n\n", map[string]interface{}{"Arg0": es.TotalStringsDir}))
+
+	if recursive {
+		fileInfos, _ := es.options.FileSystem().ReadDir(dirName)
+		for _, fileInfo := range fileInfos {
+			subDirName := filepath.Join(dirName, fileInfo.Name())
+			if fileInfo.IsDir() && !es.walker.SkipDir(subDirName) {
+				err = es.InspectDir(subDirName, recursive)
+				if err != nil {
+					es.logger.Error(err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// goFileNames returns the sorted names of the go files of a directory which are neither skipped by the source walker
+// nor ignored
+func (es *extractStrings) goFileNames(dirName string) ([]string, error) {
+	fileInfos, err := es.options.FileSystem().ReadDir(dirName)
+	if err != nil {
+		return nil, common.NewIOError(err)
+	}
+
+	var fileNames []string
+	for _, fileInfo := range fileInfos {
+		if fileInfo.IsDir() || !strings.HasSuffix(fileInfo.Name(), ".go") {
+			continue
+		}
+
+		fileName := filepath.Join(dirName, fileInfo.Name())
+		if es.walker.SkipFile(fileName) {
+			es.Println(i18n.T("i18n4go: skipping file:"), fileName)
+			continue
+		}
+
+		if es.IgnoreRegexp != nil && es.IgnoreRegexp.MatchString(fileName) {
+			es.Println(i18n.T("Using ignore-regexp:"), es.options.IgnoreRegexpFlag)
+			continue
+		} else {
+			es.Println(i18n.T("No match for ignore-regexp:"), es.options.IgnoreRegexpFlag)
+		}
+
+		fileNames = append(fileNames, fileName)
+	}
+
+	return fileNames, nil
+}
+
+// extractFile extracts the strings of a go or template file with a copy of the extractor, so that the files of a
+// directory are extracted concurrently, it returns nil if the file is ignored
+func (es *extractStrings) extractFile(filename string) (*extractStrings, error) {
+	es.Println(i18n.T("i18n4go: extracting strings from file:"), filename)
+	if es.options.DryRunFlag {
+		es.logger.Warn(i18n.T("WARNING running in -dry-run mode"))
+	}
+
+	fileExtractor := *es
+	fileExtractor.ExtractedStrings = make(map[string]common.StringInfo)
+	fileExtractor.excludedImports = make(map[string]bool)
+
+	fileExtractor.setFilename(filename)
+	fileExtractor.setI18nFilename(filename)
+	fileExtractor.setPoFilename(filename)
+
+	fset := token.NewFileSet()
+
+	absFilePath := common.AbsPath(es.options.FileSystem(), filename)
+
+	fileInfo, err := common.GetAbsFileInfoFS(es.options.FileSystem(), absFilePath)
 	if err != nil {
 		es.Println(err)
-		return err
+		return nil, err
 	}
-	es.Println(fmt.Sprintf(i18n.T("Loaded {{.Arg0}} excluded regexps", map[string]interface{}{"Arg0": len(es.FilteredRegexps)})))
 
-	if es.options.SubstringFilenameFlag != "" {
-		err := es.loadSubstringRegexps()
+	if strings.HasPrefix(fileInfo.Name(), ".") {
+		es.logger.Warn(i18n.T("WARNING ignoring file:"), absFilePath)
+		return nil, nil
+	}
+
+	if !isTemplateFile(absFilePath) {
+		err = fileExtractor.extractGoFile(absFilePath, fset)
 		if err != nil {
 			es.Println(err)
-			return err
+			return nil, err
+		}
+	} else {
+		fileExtractor.packageName, err = fileExtractor.extractTemplateStrings(absFilePath)
+		if err != nil {
+			es.Println(err)
+			return nil, err
 		}
-		es.Println(fmt.Sprintf(i18n.T("Loaded {{.Arg0}} substring regexps", map[string]interface{}{"Arg0": len(es.FilteredRegexps)})))
+	}
+	fileExtractor.absFilePath = absFilePath
+
+	return &fileExtractor, nil
+}
+
+// extractGoFile extracts the strings of a go file, or loads them from the cache if the file did not change
+func (es *extractStrings) extractGoFile(absFilePath string, fset *token.FileSet) error {
+	content, err := es.options.FileSystem().ReadFile(absFilePath)
+	if err != nil {
+		return common.NewIOError(err)
+	}
+
+	key := es.cache.Key(absFilePath, content)
+	var cached cachedFile
+	if es.cache.Load(key, &cached) && cached.Strings != nil {
+		es.Println(i18n.T("i18n4go: using the cached strings of file:"), absFilePath)
+		es.ExtractedStrings = cached.Strings
+		es.packageName = cached.PackageName
+		es.embedPatterns = cached.EmbedPatterns
+		return nil
+	}
+
+	astFile, err := parser.ParseFile(fset, absFilePath, content, parser.ParseComments|parser.AllErrors)
+	if err != nil {
+		return common.NewParseError(err)
 	}
 
 	es.excludeImports(astFile)
 
 	es.extractString(astFile, fset)
-	es.TotalStringsDir += len(es.ExtractedStrings)
-	es.TotalStrings += len(es.ExtractedStrings)
+	es.packageName = astFile.Name.Name
+	es.embedPatterns = embedPatterns(astFile)
+
+	err = es.cache.Save(key, cachedFile{PackageName: es.packageName, Strings: es.ExtractedStrings, EmbedPatterns: es.embedPatterns})
+	if err != nil {
+		es.logger.Warn(err)
+	}
+
+	return nil
+}
+
+// saveFile saves the strings extracted from a file, the files are saved one at a time in order so that the keys
+// generated for the strings are stable
+func (es *extractStrings) saveFile(fileExtractor *extractStrings) error {
+	if fileExtractor == nil {
+		return nil
+	}
+
+	fileExtractor.setStringIDs(fileExtractor.packageName)
+	es.ExtractedStrings = fileExtractor.ExtractedStrings
+	es.TotalStringsDir += len(fileExtractor.ExtractedStrings)
+	es.TotalStrings += len(fileExtractor.ExtractedStrings)
 	es.TotalFiles += 1
 
-	es.Printf(i18n.T("Extracted {{.Arg0}} strings from file: {{.Arg1}}\n", map[string]interface{}{"Arg0": len(es.ExtractedStrings), "Arg1": absFilePath}))
+	absFilePath := fileExtractor.absFilePath
+	es.ExtractedFiles = append(es.ExtractedFiles, common.FileStringInfos{
+		Filename:    absFilePath,
+		PackageName: fileExtractor.packageName,
+		StringInfos: fileExtractor.ExtractedStrings,
+	})
+	es.Printf(i18n.T("Extracted {{.Arg0}} strings from file: {{.Arg1}}\n", map[string]interface{}{"Arg0": len(fileExtractor.ExtractedStrings), "Arg1": absFilePath}))
 
+	var err error
 	var outputDirname = es.OutputDirname
 	if es.options.OutputDirFlag != "" {
 		if es.options.OutputMatchImportFlag {
@@ -227,7 +426,7 @@ func (es *extractStrings) InspectFile(filename string) error {
 			}
 		}
 	} else {
-		outputDirname, err = common.FindFilePath(absFilePath)
+		outputDirname, err = common.FindFilePathFS(es.options.FileSystem(), absFilePath)
 		if err != nil {
 			es.Println(err)
 			return err
@@ -235,21 +434,21 @@ func (es *extractStrings) InspectFile(filename string) error {
 	}
 
 	if es.options.MetaFlag {
-		err = es.saveExtractedStrings(outputDirname)
+		err = fileExtractor.saveExtractedStrings(outputDirname)
 		if err != nil {
 			es.Println(err)
 			return err
 		}
 	}
 
-	err = common.SaveStrings(es, es.Options(), es.ExtractedStrings, outputDirname, es.i18nFilename)
+	err = common.SaveStrings(es, es.Options(), fileExtractor.ExtractedStrings, outputDirname, fileExtractor.i18nFilename)
 	if err != nil {
 		es.Println(err)
 		return err
 	}
 
 	if es.options.PoFlag {
-		err = common.SaveStringsInPo(es, es.Options(), es.ExtractedStrings, outputDirname, es.poFilename)
+		err = common.SaveStringsInPo(es, es.Options(), fileExtractor.ExtractedStrings, outputDirname, fileExtractor.poFilename)
 		if err != nil {
 			es.Println(err)
 			return err
@@ -259,84 +458,37 @@ func (es *extractStrings) InspectFile(filename string) error {
 	return nil
 }
 
-func (es *extractStrings) InspectDir(dirName string, recursive bool) error {
-	es.Printf(i18n.T("i18n4go: inspecting dir {{.Arg0}}, recursive: {{.Arg1}}\n", map[string]interface{}{"Arg0": dirName, "Arg1": recursive}))
-	es.Println()
-
-	fset := token.NewFileSet()
-	es.TotalStringsDir = 0
-
-	packages, err := parser.ParseDir(fset, dirName, nil, parser.ParseComments)
-	if err != nil {
-		es.Println(err)
-		return err
-	}
-
-	for k, pkg := range packages {
-		es.Println(i18n.T("Extracting strings in package:"), k)
-		for fileName, _ := range pkg.Files {
-			if es.IgnoreRegexp != nil && es.IgnoreRegexp.MatchString(fileName) {
-				es.Println(i18n.T("Using ignore-regexp:"), es.options.IgnoreRegexpFlag)
-				continue
-			} else {
-				es.Println(i18n.T("No match for ignore-regexp:"), es.options.IgnoreRegexpFlag)
-			}
-
-			if strings.HasSuffix(fileName, ".go") {
-				err = es.InspectFile(fileName)
-				if err != nil {
-					es.Println(err)
-				}
-			}
-		}
-	}
-	es.Printf(i18n.T("Extracted total of {{.Arg0}} strings\n\n", map[string]interface{}{"Arg0": es.TotalStringsDir}))
-
-	if recursive {
-		fileInfos, _ := ioutil.ReadDir(dirName)
-		for _, fileInfo := range fileInfos {
-			if fileInfo.IsDir() && !strings.HasPrefix(fileInfo.Name(), ".") {
-				err = es.InspectDir(filepath.Join(dirName, fileInfo.Name()), recursive)
-				if err != nil {
-					es.Println(err)
-				}
-			}
-		}
-	}
-
-	return nil
-}
-
+// findImportPath returns the output directory of a file matching the import path of its package, resolved with the
+// go.mod file of its module
 func (es *extractStrings) findImportPath(filename string) (string, error) {
-	path := es.OutputDirname
-
-	filePath, err := common.FindFilePath(filename)
+	filePath, err := common.FindFilePathFS(es.options.FileSystem(), filename)
 	if err != nil {
-		fmt.Println(i18n.T("ERROR opening file"), err)
+		es.logger.Debug(i18n.T("ERROR opening file"), err)
 		return "", err
 	}
 
-	pkg, err := build.ImportDir(filePath, 0)
-	srcPath := i18n.T("src") + string(os.PathSeparator)
-	if strings.HasPrefix(pkg.Dir, srcPath) {
-		path = filepath.Join(path, pkg.Dir[len(srcPath):len(pkg.Dir)])
+	importPath, err := common.ImportPathFS(es.options.FileSystem(), filePath)
+	if err != nil {
+		es.logger.Debug(i18n.T("ERROR opening file"), err)
+		return "", err
 	}
 
-	return path, nil
+	return filepath.Join(es.OutputDirname, filepath.FromSlash(importPath)), nil
 }
 
 func (es *extractStrings) findPackagePath(filename string) (string, error) {
 	path := es.OutputDirname
 
-	filePath, err := common.FindFilePath(filename)
+	filePath, err := common.FindFilePathFS(es.options.FileSystem(), filename)
 	if err != nil {
-		fmt.Println(i18n.T("ERROR opening file"), err)
+		es.logger.Debug(i18n.T("ERROR opening file"), err)
 		return "", err
 	}
 
-	pkg, err := build.ImportDir(filePath, 0)
+	buildContext := common.BuildContextFS(es.options.FileSystem(), build.Default)
+	pkg, err := buildContext.ImportDir(filePath, 0)
 	if err != nil {
-		fmt.Println(i18n.T("ERROR opening file"), err)
+		es.logger.Debug(i18n.T("ERROR opening file"), err)
 		return "", err
 	}
 
@@ -349,7 +501,7 @@ func (es *extractStrings) saveExtractedStrings(outputDirname string) error {
 	}
 
 	if !es.options.DryRunFlag {
-		err := common.CreateOutputDirsIfNeeded(outputDirname)
+		err := common.CreateOutputDirsIfNeededFS(es.options.FileSystem(), outputDirname)
 		if err != nil {
 			es.Println(err)
 			return err
@@ -357,7 +509,7 @@ func (es *extractStrings) saveExtractedStrings(outputDirname string) error {
 	}
 
 	stringInfos := make([]common.StringInfo, 0)
-	for _, stringInfo := range es.ExtractedStrings {
+	for _, stringInfo := range common.SortedStringInfos(es.ExtractedStrings) {
 		stringInfo.Filename = strings.Split(es.Filename, ".extracted.json")[0]
 
 		stringInfos = append(stringInfos, stringInfo)
@@ -371,14 +523,11 @@ func (es *extractStrings) saveExtractedStrings(outputDirname string) error {
 	jsonData = common.UnescapeHTML(jsonData)
 
 	if !es.options.DryRunFlag && len(stringInfos) != 0 {
-		file, err := os.Create(filepath.Join(outputDirname, es.Filename[strings.LastIndex(es.Filename, string(os.PathSeparator))+1:len(es.Filename)]))
-		defer file.Close()
+		err := es.options.FileSystem().WriteFile(filepath.Join(outputDirname, es.Filename[strings.LastIndex(es.Filename, string(os.PathSeparator))+1:len(es.Filename)]), jsonData, 0644)
 		if err != nil {
 			es.Println(err)
-			return err
+			return common.NewIOError(err)
 		}
-
-		file.Write(jsonData)
 	}
 
 	return nil
@@ -396,8 +545,56 @@ func (es *extractStrings) setPoFilename(filename string) {
 	es.poFilename = filename + ".en.po"
 }
 
+// loadFilters loads the excluded strings and regexps and the substring regexps once for all the files
+func (es *extractStrings) loadFilters() error {
+	if es.filtersLoaded {
+		return nil
+	}
+	es.filtersLoaded = true
+
+	walker, err := common.NewSourceWalker(es.options)
+	if err != nil {
+		es.Println(err)
+		return err
+	}
+	es.walker = walker
+
+	excludedContent, _ := es.options.FileSystem().ReadFile(es.options.ExcludedFilenameFlag)
+	substringContent, _ := es.options.FileSystem().ReadFile(es.options.SubstringFilenameFlag)
+	es.cache = common.NewCache(es.options.CacheDirFlag, "extract-strings", string(excludedContent), string(substringContent), strings.Join(es.tFuncNames, ","))
+
+	es.FilteredStrings = make(map[string]string)
+	es.FilteredRegexps = []*regexp.Regexp{}
+	es.SubstringRegexps = []*regexp.Regexp{}
+
+	err = es.loadExcludedStrings()
+	if err != nil {
+		es.Println(err)
+		return err
+	}
+	es.Println(fmt.Sprintf(i18n.T("Loaded {{.Arg0}} excluded strings", map[string]interface{}{"Arg0": len(es.FilteredStrings)})))
+
+	err = es.loadExcludedRegexps()
+	if err != nil {
+		es.Println(err)
+		return err
+	}
+	es.Println(fmt.Sprintf(i18n.T("Loaded {{.Arg0}} excluded regexps", map[string]interface{}{"Arg0": len(es.FilteredRegexps)})))
+
+	if es.options.SubstringFilenameFlag != "" {
+		err := es.loadSubstringRegexps()
+		if err != nil {
+			es.Println(err)
+			return err
+		}
+		es.Println(fmt.Sprintf(i18n.T("Loaded {{.Arg0}} substring regexps", map[string]interface{}{"Arg0": len(es.SubstringRegexps)})))
+	}
+
+	return nil
+}
+
 func (es *extractStrings) loadExcludedStrings() error {
-	_, err := os.Stat(es.options.ExcludedFilenameFlag)
+	_, err := es.options.FileSystem().Stat(es.options.ExcludedFilenameFlag)
 	if os.IsNotExist(err) {
 		es.Println(i18n.T("Could not find:"), es.options.ExcludedFilenameFlag)
 		return nil
@@ -405,17 +602,17 @@ func (es *extractStrings) loadExcludedStrings() error {
 
 	es.Println(i18n.T("Excluding strings in file:"), es.options.ExcludedFilenameFlag)
 
-	content, err := ioutil.ReadFile(es.options.ExcludedFilenameFlag)
+	content, err := es.options.FileSystem().ReadFile(es.options.ExcludedFilenameFlag)
 	if err != nil {
-		fmt.Print(err)
-		return err
+		es.logger.Debug(err)
+		return common.NewIOError(err)
 	}
 
 	var excludedStrings common.ExcludedStrings
 	err = json.Unmarshal(content, &excludedStrings)
 	if err != nil {
-		fmt.Print(err)
-		return err
+		es.logger.Debug(err)
+		return common.NewParseError(err)
 	}
 
 	for i := range excludedStrings.ExcludedStrings {
@@ -426,7 +623,7 @@ func (es *extractStrings) loadExcludedStrings() error {
 }
 
 func (es *extractStrings) loadExcludedRegexps() error {
-	_, err := os.Stat(es.options.ExcludedFilenameFlag)
+	_, err := es.options.FileSystem().Stat(es.options.ExcludedFilenameFlag)
 	if os.IsNotExist(err) {
 		es.Println(i18n.T("Could not find:"), es.options.ExcludedFilenameFlag)
 		return nil
@@ -434,23 +631,23 @@ func (es *extractStrings) loadExcludedRegexps() error {
 
 	es.Println(i18n.T("Excluding regexps in file:"), es.options.ExcludedFilenameFlag)
 
-	content, err := ioutil.ReadFile(es.options.ExcludedFilenameFlag)
+	content, err := es.options.FileSystem().ReadFile(es.options.ExcludedFilenameFlag)
 	if err != nil {
-		fmt.Print(err)
-		return err
+		es.logger.Debug(err)
+		return common.NewIOError(err)
 	}
 
 	var excludedRegexps common.ExcludedStrings
 	err = json.Unmarshal(content, &excludedRegexps)
 	if err != nil {
-		fmt.Print(err)
-		return err
+		es.logger.Debug(err)
+		return common.NewParseError(err)
 	}
 
 	for _, regexpString := range excludedRegexps.ExcludedRegexps {
 		compiledRegexp, err := regexp.Compile(regexpString)
 		if err != nil {
-			fmt.Println(i18n.T("WARNING error compiling regexp:"), regexpString)
+			es.logger.Warn(i18n.T("WARNING error compiling regexp:"), regexpString)
 		}
 
 		es.FilteredRegexps = append(es.FilteredRegexps, compiledRegexp)
@@ -464,7 +661,7 @@ type CaptureGroupSubstrings struct {
 }
 
 func (es *extractStrings) loadSubstringRegexps() error {
-	_, err := os.Stat(es.options.SubstringFilenameFlag)
+	_, err := es.options.FileSystem().Stat(es.options.SubstringFilenameFlag)
 	if os.IsNotExist(err) {
 		es.Println(i18n.T("Could not find:"), es.options.SubstringFilenameFlag)
 		return nil
@@ -472,23 +669,23 @@ func (es *extractStrings) loadSubstringRegexps() error {
 
 	es.Println(i18n.T("Capturing substrings in file:"), es.options.SubstringFilenameFlag)
 
-	content, err := ioutil.ReadFile(es.options.SubstringFilenameFlag)
+	content, err := es.options.FileSystem().ReadFile(es.options.SubstringFilenameFlag)
 	if err != nil {
-		fmt.Print(err)
-		return err
+		es.logger.Debug(err)
+		return common.NewIOError(err)
 	}
 
 	var captureGroupStrings CaptureGroupSubstrings
 	err = json.Unmarshal(content, &captureGroupStrings)
 	if err != nil {
-		fmt.Print(err)
-		return err
+		es.logger.Debug(err)
+		return common.NewParseError(err)
 	}
 
 	for _, regexpString := range captureGroupStrings.RegexpsStrings {
 		compiledRegexp, err := regexp.Compile(regexpString)
 		if err != nil {
-			fmt.Println(i18n.T("WARNING error compiling regexp:"), regexpString)
+			es.logger.Warn(i18n.T("WARNING error compiling regexp:"), regexpString)
 		}
 
 		es.SubstringRegexps = append(es.SubstringRegexps, compiledRegexp)
@@ -499,13 +696,20 @@ func (es *extractStrings) loadSubstringRegexps() error {
 
 func (es *extractStrings) extractString(f *ast.File, fset *token.FileSet) error {
 	shouldProcessBasicLit := true
+	templateBasicLits := make(map[*ast.BasicLit]bool)
+	untranslatableBasicLits := common.UntranslatableBasicLits(f)
 	ast.Inspect(f, func(n ast.Node) bool {
 		switch x := n.(type) {
 		case *ast.BasicLit:
-			if shouldProcessBasicLit {
+			if shouldProcessBasicLit && !templateBasicLits[x] && !untranslatableBasicLits[x] {
 				es.processBasicLit(x, n, fset)
 			}
 			shouldProcessBasicLit = true
+		case *ast.CallExpr:
+			if basicLit, ok := templateParseCallArg(x); ok {
+				es.processTemplateBasicLit(basicLit, fset)
+				templateBasicLits[basicLit] = true
+			}
 		case *ast.IndexExpr:
 			_, ok := x.Index.(*ast.BasicLit)
 			if ok {
@@ -528,7 +732,7 @@ func (es *extractStrings) processBasicLit(basicLit *ast.BasicLit, n ast.Node, fs
 		if compiledRegexp.MatchString(basicLit.Value) {
 			submatches := compiledRegexp.FindStringSubmatch(basicLit.Value)
 			if submatches == nil {
-				es.Println(fmt.Sprintf(i18n.T("WARNING No capturing group found in {{.Arg0}}", map[string]interface{}{"Arg0": compiledRegexp.String()})))
+				es.logger.Warn(fmt.Sprintf(i18n.T("WARNING No capturing group found in {{.Arg0}}", map[string]interface{}{"Arg0": compiledRegexp.String()})))
 				return
 			}
 			captureGroup := submatches[1]
@@ -555,10 +759,147 @@ func (es *extractStrings) processBasicLit(basicLit *ast.BasicLit, n ast.Node, fs
 	}
 }
 
+// processTemplateBasicLit extracts the strings of a template parsed from a go string literal, e.g.,
+// template.New("mail").Parse(`Hello {{.Name}}`), positioned in the go file when the literal is a raw string
+func (es *extractStrings) processTemplateBasicLit(basicLit *ast.BasicLit, fset *token.FileSet) {
+	s, _ := strconv.Unquote(basicLit.Value)
+	position := fset.Position(basicLit.Pos())
+
+	templateStrings, err := common.ExtractTemplateStrings(position.Filename, s, es.tFuncNames, false)
+	if err != nil {
+		es.Println(err)
+		return
+	}
+
+	for _, templateString := range templateStrings {
+		if !strings.HasPrefix(basicLit.Value, "`") {
+			es.addTemplateString(templateString.Value, position)
+			continue
+		}
+
+		stringPosition := position
+		stringPosition.Offset += 1 + templateString.Offset
+		stringPosition.Line += templateString.Line - 1
+		stringPosition.Column = templateString.Column
+		if templateString.Line == 1 {
+			stringPosition.Column = position.Column + templateString.Column
+		}
+		es.addTemplateString(templateString.Value, stringPosition)
+	}
+}
+
+// extractTemplateStrings extracts the strings of a text/template or html/template file, and returns the name of
+// the go package of its directory, or the name of the directory if it has no go files
+func (es *extractStrings) extractTemplateStrings(fileName string) (string, error) {
+	content, err := es.options.FileSystem().ReadFile(fileName)
+	if err != nil {
+		return "", common.NewIOError(err)
+	}
+
+	templateStrings, err := common.ExtractTemplateStrings(filepath.Base(fileName), string(content), es.tFuncNames, common.IsHTMLTemplateFilename(fileName))
+	if err != nil {
+		return "", err
+	}
+
+	for _, templateString := range templateStrings {
+		es.addTemplateString(templateString.Value, token.Position{
+			Filename: fileName,
+			Offset:   templateString.Offset,
+			Line:     templateString.Line,
+			Column:   templateString.Column,
+		})
+	}
+
+	dirName := filepath.Dir(fileName)
+	buildContext := common.BuildContextFS(es.options.FileSystem(), build.Default)
+	pkg, err := buildContext.ImportDir(dirName, 0)
+	if err != nil {
+		return filepath.Base(dirName), nil
+	}
+
+	return pkg.Name, nil
+}
+
+func (es *extractStrings) addTemplateString(value string, position token.Position) {
+	if es.filter(value) {
+		return
+	}
+
+	es.ExtractedStrings[value] = common.StringInfo{Value: value,
+		Filename: position.Filename,
+		Offset:   position.Offset,
+		Line:     position.Line,
+		Column:   position.Column}
+}
+
+// inspectTemplateFiles extracts the strings of the template files of a directory, i.e., the files with one of the
+// template extensions and the files embedded by the //go:embed directives of its go files which are templates
+func (es *extractStrings) inspectTemplateFiles(dirName string, embedPatterns []string) error {
+	fileInfos, err := es.options.FileSystem().ReadDir(dirName)
+	if err != nil {
+		return common.NewIOError(err)
+	}
+
+	var fileNames []string
+	for _, fileInfo := range fileInfos {
+		fileName := filepath.Join(dirName, fileInfo.Name())
+		if !fileInfo.IsDir() && common.IsTemplateFilename(fileInfo.Name(), es.templateExtensions) && !es.walker.SkipFile(fileName) {
+			fileNames = append(fileNames, fileName)
+		}
+	}
+
+	for _, fileName := range embeddedFileNames(es.options.FileSystem(), dirName, embedPatterns) {
+		if common.IsTemplateFilename(fileName, es.templateExtensions) || isTemplateContentFile(es.options.FileSystem(), fileName) {
+			fileNames = append(fileNames, fileName)
+		}
+	}
+
+	var templateFileNames []string
+	for _, fileName := range fileNames {
+		absFileName := common.AbsPath(es.options.FileSystem(), fileName)
+		if es.inspectedTemplateFiles[absFileName] {
+			continue
+		}
+		es.inspectedTemplateFiles[absFileName] = true
+
+		if es.IgnoreRegexp != nil && es.IgnoreRegexp.MatchString(fileName) {
+			es.Println(i18n.T("Using ignore-regexp:"), es.options.IgnoreRegexpFlag)
+			continue
+		}
+
+		templateFileNames = append(templateFileNames, fileName)
+	}
+
+	// the errors are printed when the files are extracted, so that the other template files are still saved
+	fileExtractors, _ := common.MapConcurrently(templateFileNames, common.Jobs(es.options), es.extractFile)
+	for _, fileExtractor := range fileExtractors {
+		err = es.saveFile(fileExtractor)
+		if err != nil {
+			es.logger.Error(err)
+		}
+	}
+
+	return nil
+}
+
+// setStringIDs sets the IDs of the extracted strings of a file generated with the key format, the strings are
+// sorted so that the keys do not depend on the order of the map
+func (es *extractStrings) setStringIDs(packageName string) {
+	if es.keyGenerator == nil || es.keyGenerator.KeyFormat == common.TEXT_KEY_FORMAT {
+		return
+	}
+
+	for _, value := range sortedKeys(es.ExtractedStrings) {
+		stringInfo := es.ExtractedStrings[value]
+		stringInfo.ID = es.keyGenerator.Key(packageName, value)
+		es.ExtractedStrings[value] = stringInfo
+	}
+}
+
 func (es *extractStrings) excludeImports(astFile *ast.File) {
 	for i := range astFile.Imports {
 		importString, _ := strconv.Unquote(astFile.Imports[i].Path.Value)
-		es.FilteredStrings[importString] = importString
+		es.excludedImports[importString] = true
 	}
 
 }
@@ -574,6 +915,10 @@ func (es *extractStrings) filter(aString string) bool {
 		return true
 	}
 
+	if es.excludedImports[aString] {
+		return true
+	}
+
 	for _, compiledRegexp := range es.FilteredRegexps {
 		if compiledRegexp.MatchString(aString) {
 			return true
@@ -582,3 +927,90 @@ func (es *extractStrings) filter(aString string) bool {
 
 	return false
 }
+
+func isTemplateFile(fileName string) bool {
+	return !strings.HasSuffix(fileName, ".go")
+}
+
+// isTemplateContentFile returns true if the content of the file is a template with at least one action, so that
+// the embedded data files, e.g., JSON files or images, are not extracted
+func isTemplateContentFile(fsys common.FS, fileName string) bool {
+	content, err := fsys.ReadFile(fileName)
+	if err != nil {
+		return false
+	}
+
+	return common.HasTemplateActions(filepath.Base(fileName), string(content))
+}
+
+// templateParseCallArg returns the go string literal parsed as a template by a Parse(...) call, i.e., with at
+// least one template action, e.g., template.New("mail").Parse("Hello {{.Name}}")
+func templateParseCallArg(callExpr *ast.CallExpr) (*ast.BasicLit, bool) {
+	selectorExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+	if !ok || selectorExpr.Sel.Name != "Parse" || len(callExpr.Args) != 1 {
+		return nil, false
+	}
+
+	basicLit, ok := callExpr.Args[0].(*ast.BasicLit)
+	if !ok || basicLit.Kind != token.STRING {
+		return nil, false
+	}
+
+	s, err := strconv.Unquote(basicLit.Value)
+	if err != nil || !common.HasTemplateActions("", s) {
+		return nil, false
+	}
+
+	return basicLit, true
+}
+
+// embeddedFileNames returns the files of the directory matching the patterns of the //go:embed directives of its go
+// files, and the files of the matching directories except the hidden ones
+func embeddedFileNames(fsys common.FS, dirName string, patterns []string) []string {
+	var fileNames []string
+	for _, pattern := range patterns {
+		matches, _ := common.Glob(fsys, filepath.Join(dirName, filepath.FromSlash(strings.TrimPrefix(pattern, "all:"))))
+		for _, match := range matches {
+			common.WalkDir(fsys, match, func(path string, info fs.DirEntry, err error) error {
+				if err != nil {
+					return nil
+				}
+
+				if path != match && (strings.HasPrefix(info.Name(), ".") || strings.HasPrefix(info.Name(), "_")) {
+					if info.IsDir() {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+
+				if !info.IsDir() {
+					fileNames = append(fileNames, path)
+				}
+				return nil
+			})
+		}
+	}
+
+	return fileNames
+}
+
+// embedPatterns returns the patterns of the //go:embed directives of a go file
+func embedPatterns(astFile *ast.File) []string {
+	var patterns []string
+	for _, commentGroup := range astFile.Comments {
+		for _, comment := range commentGroup.List {
+			if !strings.HasPrefix(comment.Text, "//go:embed ") {
+				continue
+			}
+
+			for _, pattern := range strings.Fields(strings.TrimPrefix(comment.Text, "//go:embed ")) {
+				if unquoted, err := strconv.Unquote(pattern); err == nil {
+					pattern = unquoted
+				}
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+
+	return patterns
+}
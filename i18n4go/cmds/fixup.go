@@ -18,18 +18,26 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"os"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
+	"go/ast"
+	"go/parser"
+	"go/token"
+
 	"github.com/maximilien/i18n4go/i18n4go/common"
 	"github.com/maximilien/i18n4go/i18n4go/i18n"
 	"github.com/spf13/cobra"
 )
 
 type fixup struct {
+	commandLogger
+
 	options common.Options
 
 	I18nStringInfos []common.I18nStringInfo
@@ -37,13 +45,31 @@ type fixup struct {
 	Source          map[string]int
 	Locales         map[string]map[string]string
 	IgnoreRegexp    *regexp.Regexp
+
+	// Output is where the changes of the translation files are reported, the standard output by default
+	Output io.Writer
+
+	// ResolveUpdate returns the removed ID updated by a new string of the code, or the empty string if the string is
+	// new, the user is prompted by default
+	ResolveUpdate func(id string, removedIDs []string) string
+
+	// AddedIDs, RemovedIDs and UpdatedIDs, by previous ID, are the changes of the IDs of the translation files, and
+	// MigratedKeys the keys of the IDs migrated with the migrate keys option
+	AddedIDs     []string
+	RemovedIDs   []string
+	UpdatedIDs   map[string]string
+	MigratedKeys map[string][]string
 }
 
 func NewFixup(options *common.Options) *fixup {
+	commandLogger := newCommandLogger(*options)
+
 	return &fixup{
+		commandLogger:   commandLogger,
 		options:         *options,
 		I18nStringInfos: []common.I18nStringInfo{},
-		IgnoreRegexp:    common.GetIgnoreRegexp(options.IgnoreRegexpFlag),
+		IgnoreRegexp:    common.GetIgnoreRegexpWithLogger(commandLogger.logger, options.IgnoreRegexpFlag),
+		Output:          os.Stdout,
 	}
 }
 
@@ -62,6 +88,11 @@ func NewFixupCommand(options *common.Options) *cobra.Command {
 	fixupCmd.Flags().StringVarP(&options.QualifierFlag, "qualifier", "q", "i18n", i18n.T("[optional] the qualifier string that is used when importing the package for i18n4go to use the i18n.T(...) function"))
 	fixupCmd.Flags().StringVar(&options.SourceDirFlag, "source", ".", i18n.T("[optional] the directory where the source go files are located, defaults to current directory"))
 	fixupCmd.Flags().StringVar(&options.ResourceDirFlag, "resource", ".", i18n.T("[optional] the directory where the translation files are located, defaults to current directory"))
+	fixupCmd.Flags().BoolVar(&options.MigrateKeysFlag, "migrate-keys", false, i18n.T("[optional] migrate the source text IDs of the translation files of all locales and of the source files to keys of the key format"))
+	fixupCmd.Flags().StringVar(&options.KeyFormatFlag, "key-format", common.TEXT_KEY_FORMAT, i18n.T("[optional] the format of the IDs of the strings, one of: text, slug, hash, the slug and hash IDs are scoped by package, e.g., greetings.hello_world"))
+	fixupCmd.Flags().IntVar(&options.JobsFlag, "jobs", 0, i18n.T("[optional] the number of files inspected concurrently, defaults to the number of CPUs"))
+	fixupCmd.Flags().StringVar(&options.CacheDirFlag, "cache-dir", "", i18n.T("[optional] the directory of the cache of the strings of the go files, so that only the files which changed are parsed again"))
+	addSourceWalkerFlags(fixupCmd, options)
 
 	return fixupCmd
 }
@@ -70,23 +101,11 @@ func (fix *fixup) Options() common.Options {
 	return fix.options
 }
 
-func (fix *fixup) Println(a ...interface{}) (int, error) {
-	if fix.options.VerboseFlag {
-		return fmt.Println(a...)
-	}
-
-	return 0, nil
-}
-
-func (fix *fixup) Printf(msg string, a ...interface{}) (int, error) {
-	if fix.options.VerboseFlag {
-		return fmt.Printf(msg, a...)
+func (fix *fixup) Run() error {
+	if fix.options.MigrateKeysFlag {
+		return fix.migrateKeys()
 	}
 
-	return 0, nil
-}
-
-func (fix *fixup) Run() error {
 	//FIND PROBLEMS HERE AND RETURN AN ERROR
 	var (
 		translationsAdded   bool
@@ -97,29 +116,29 @@ func (fix *fixup) Run() error {
 	fix.Source = source
 
 	if err != nil {
-		fmt.Println(i18n.T("Couldn't find any source strings: {{.Arg0}}", map[string]interface{}{
+		fmt.Fprintln(fix.Output, i18n.T("Couldn't find any source strings: {{.Arg0}}", map[string]interface{}{
 			"Arg0": err.Error(),
 		}))
 		return err
 	}
 
-	locales := findTranslationFiles(fix.options.ResourceDirFlag, fix.IgnoreRegexp, fix.options.VerboseFlag)
+	locales := findTranslationFiles(fix.options.FileSystem(), fix.options.ResourceDirFlag, fix.IgnoreRegexp)
 	englishFiles, ok := locales["en_US"]
 	if !ok {
-		fmt.Println(i18n.T("Unable to find english translation files"))
-		return errors.New(i18n.T("Unable to find english translation files"))
+		fmt.Fprintln(fix.Output, i18n.T("Unable to find english translation files"))
+		return common.NewIOError(errors.New(i18n.T("Unable to find english translation files")))
 	}
 
 	englishFile := englishFiles[0]
 	if englishFile == "" {
-		fmt.Println(i18n.T("Could not find an i18n file for locale: en_US"))
-		return errors.New(i18n.T("Could not find an i18n file for locale: en_US"))
+		fmt.Fprintln(fix.Output, i18n.T("Could not find an i18n file for locale: en_US"))
+		return common.NewIOError(errors.New(i18n.T("Could not find an i18n file for locale: en_US")))
 	}
 
 	englishStringInfos, err := fix.findI18nStrings(englishFile)
 
 	if err != nil {
-		fmt.Println(i18n.T("Couldn't find the english strings: {{.Arg0}}", map[string]interface{}{
+		fmt.Fprintln(fix.Output, i18n.T("Couldn't find the english strings: {{.Arg0}}", map[string]interface{}{
 			"Arg0": err.Error(),
 		}))
 		return err
@@ -134,14 +153,14 @@ func (fix *fixup) Run() error {
 			foreignMissingTranslations := getMissingForeignTranslations(englishStringInfos, foreignStringInfos)
 
 			if len(foreignMissingTranslations) > 0 {
-				addTranslations(foreignStringInfos, i18nFile[0], foreignMissingTranslations)
+				fix.addTranslations(foreignStringInfos, i18nFile[0], foreignMissingTranslations)
 				translationsAdded = true
 			} else {
 				translationsAdded = false
 			}
 
 			if len(foreignAdditionalTranslations) > 0 {
-				removeTranslations(foreignStringInfos, i18nFile[0], foreignAdditionalTranslations)
+				fix.removeTranslations(foreignStringInfos, i18nFile[0], foreignAdditionalTranslations)
 				translationsRemoved = true
 			} else {
 				translationsRemoved = false
@@ -149,7 +168,7 @@ func (fix *fixup) Run() error {
 
 			// update only if there were changes
 			if translationsAdded || translationsRemoved {
-				writeStringInfoMapToJSON(foreignStringInfos, i18nFile[0])
+				writeStringInfoMapToJSON(fix.options.FileSystem(), foreignStringInfos, i18nFile[0])
 			}
 
 			// reset flags
@@ -166,56 +185,20 @@ func (fix *fixup) Run() error {
 	updatedTranslations := make(map[string]string)
 
 	if len(potentialAdditionalTranslations) > 0 && len(removedTranslations) > 0 {
+		resolveUpdate := fix.ResolveUpdate
+		if resolveUpdate == nil {
+			resolveUpdate = fix.promptUpdate
+		}
+
 		for _, newUpdatedTranslation := range potentialAdditionalTranslations {
 			if len(removedTranslations) > 0 {
-				var input string
-
-				escape := false
-				updated := false
-
-				for !escape {
-					fmt.Printf(i18n.T("Is the string \"%s\" a new or updated string? [new/upd]\n"), newUpdatedTranslation)
-
-					_, err := fmt.Scanf("%s\n", &input)
-					if err != nil {
-						panic(err)
-					}
-
-					input = strings.ToLower(input)
-
-					switch input {
-					case "new":
-						additionalTranslations = append(additionalTranslations, newUpdatedTranslation)
-						escape = true
-					case "upd":
-						fmt.Println(i18n.T("Select the number for the previous translation:"))
-						for index, value := range removedTranslations {
-							fmt.Printf("\t%d. %s\n", (index + 1), value)
-						}
-
-						var updSelection int
-						for !updated {
-							_, err := fmt.Scanf("%d\n", &updSelection)
-
-							if err == nil && updSelection > 0 && updSelection <= len(removedTranslations) {
-								updSelection = updSelection - 1
-
-								updatedTranslations[removedTranslations[updSelection]] = newUpdatedTranslation
-
-								removedTranslations = removeFromSlice(removedTranslations, updSelection)
-
-								updated = true
-							} else {
-								fmt.Println(i18n.T("Invalid response."))
-							}
-						}
-						escape = true
-					case "exit":
-						fmt.Println(i18n.T("Canceling fixup"))
-						os.Exit(0)
-					default:
-						fmt.Println(i18n.T("Invalid response."))
-					}
+				updSelection := slices.Index(removedTranslations, resolveUpdate(newUpdatedTranslation, removedTranslations))
+				if updSelection < 0 {
+					additionalTranslations = append(additionalTranslations, newUpdatedTranslation)
+				} else {
+					updatedTranslations[removedTranslations[updSelection]] = newUpdatedTranslation
+
+					removedTranslations = removeFromSlice(removedTranslations, updSelection)
 				}
 			} else {
 				additionalTranslations = append(additionalTranslations, newUpdatedTranslation)
@@ -228,26 +211,26 @@ func (fix *fixup) Run() error {
 	for locale, i18nFiles := range locales {
 		translatedStrings, err := fix.findI18nStrings(i18nFiles[0])
 		if err != nil {
-			fmt.Println(fmt.Sprintf(i18n.T("Couldn't get the strings from {{.Arg0}}: {{.Arg1}}"), locale, err.Error()))
+			fmt.Fprintln(fix.Output, fmt.Sprintf(i18n.T("Couldn't get the strings from {{.Arg0}}: {{.Arg1}}"), locale, err.Error()))
 			return err
 		}
 
 		if len(updatedTranslations) > 0 {
-			updateTranslations(translatedStrings, i18nFiles[0], locale, updatedTranslations)
+			fix.updateTranslations(translatedStrings, i18nFiles[0], locale, updatedTranslations)
 			translationsUpdated = true
 		} else {
 			translationsUpdated = false
 		}
 
 		if len(additionalTranslations) > 0 {
-			addTranslations(translatedStrings, i18nFiles[0], additionalTranslations)
+			fix.addTranslations(translatedStrings, i18nFiles[0], additionalTranslations)
 			translationsAdded = true
 		} else {
 			translationsAdded = false
 		}
 
 		if len(removedTranslations) > 0 {
-			removeTranslations(translatedStrings, i18nFiles[0], removedTranslations)
+			fix.removeTranslations(translatedStrings, i18nFiles[0], removedTranslations)
 			translationsRemoved = true
 		} else {
 			translationsRemoved = false
@@ -255,7 +238,7 @@ func (fix *fixup) Run() error {
 
 		// update only if there is a change
 		if translationsAdded || translationsRemoved || translationsUpdated {
-			err = writeStringInfoMapToJSON(translatedStrings, i18nFiles[0])
+			err = writeStringInfoMapToJSON(fix.options.FileSystem(), translatedStrings, i18nFiles[0])
 		}
 
 		// reset flags
@@ -265,24 +248,222 @@ func (fix *fixup) Run() error {
 
 	}
 
+	fix.AddedIDs = additionalTranslations
+	fix.RemovedIDs = removedTranslations
+	fix.UpdatedIDs = updatedTranslations
+
 	if err == nil {
-		fmt.Print(i18n.T("OK"))
+		fmt.Fprint(fix.Output, i18n.T("OK"))
 	}
 
 	return err
 }
 
+// promptUpdate prompts the user whether a string of the code is new or updates one of the removed IDs
+func (fix *fixup) promptUpdate(id string, removedIDs []string) string {
+	var input string
+	for {
+		fmt.Printf(i18n.T("Is the string \"%s\" a new or updated string? [new/upd]\n"), id)
+
+		_, err := fmt.Scanf("%s\n", &input)
+		if err != nil {
+			panic(err)
+		}
+
+		input = strings.ToLower(input)
+
+		switch input {
+		case "new":
+			return ""
+		case "upd":
+			fmt.Println(i18n.T("Select the number for the previous translation:"))
+			for index, value := range removedIDs {
+				fmt.Printf("\t%d. %s\n", (index + 1), value)
+			}
+
+			var updSelection int
+			for {
+				_, err := fmt.Scanf("%d\n", &updSelection)
+
+				if err == nil && updSelection > 0 && updSelection <= len(removedIDs) {
+					return removedIDs[updSelection-1]
+				}
+				fmt.Println(i18n.T("Invalid response."))
+			}
+		case "exit":
+			fmt.Println(i18n.T("Canceling fixup"))
+			os.Exit(0)
+		default:
+			fmt.Println(i18n.T("Invalid response."))
+		}
+	}
+}
+
+// migrateKeys replaces the source text IDs of the T(...) calls of the source files, and of the translation files of
+// all locales, by the keys generated with the key format, so that fixing a source text does not invalidate the
+// translations. An ID used by several packages gets one key per package with the same translations.
+func (fix *fixup) migrateKeys() error {
+	keyGenerator, err := common.NewKeyGenerator(fix.options.KeyFormatFlag)
+	if err != nil {
+		return err
+	}
+
+	if keyGenerator.KeyFormat == common.TEXT_KEY_FORMAT {
+		return errors.New(i18n.T("i18n4go: migrating the keys requires the slug or hash key format"))
+	}
+
+	locales := findTranslationFiles(fix.options.FileSystem(), fix.options.ResourceDirFlag, fix.IgnoreRegexp)
+	englishFiles, ok := locales["en_US"]
+	if !ok {
+		fmt.Fprintln(fix.Output, i18n.T("Unable to find english translation files"))
+		return common.NewIOError(errors.New(i18n.T("Unable to find english translation files")))
+	}
+
+	englishStringInfos, err := fix.findI18nStrings(englishFiles[0])
+	if err != nil {
+		fmt.Fprintln(fix.Output, i18n.T("Couldn't find the english strings: {{.Arg0}}", map[string]interface{}{
+			"Arg0": err.Error(),
+		}))
+		return err
+	}
+
+	for _, stringInfo := range englishStringInfos {
+		if !common.IsTextKey(stringInfo) {
+			keyGenerator.Reserve(stringInfo.ID, stringInfo.Translation)
+		}
+	}
+
+	migratedKeys := make(map[string][]string)
+	files, err := getGoFiles(fix.options.SourceDirFlag, fix.options)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		err := fix.migrateFileKeys(file, englishStringInfos, keyGenerator, migratedKeys)
+		if err != nil {
+			fmt.Fprintln(fix.Output, i18n.T("Error when migrating the keys of go file: "), file)
+			return err
+		}
+	}
+
+	for _, locale := range sortedKeys(locales) {
+		for _, i18nFile := range locales[locale] {
+			translatedStrings, err := fix.findI18nStrings(i18nFile)
+			if err != nil {
+				fmt.Fprintln(fix.Output, i18n.T("Couldn't get the strings from {{.Arg0}}: {{.Arg1}}", map[string]interface{}{"Arg0": locale, "Arg1": err.Error()}))
+				return err
+			}
+
+			migrated := false
+			for id, keys := range migratedKeys {
+				stringInfo, ok := translatedStrings[id]
+				if !ok {
+					continue
+				}
+
+				for _, key := range keys {
+					translatedStrings[key] = common.I18nStringInfo{ID: key, Translation: stringInfo.Translation}
+				}
+				delete(translatedStrings, id)
+				migrated = true
+			}
+
+			if migrated {
+				fix.Println(i18n.T("Migrating the keys of the translation file:"), i18nFile)
+				err = writeStringInfoMapToJSON(fix.options.FileSystem(), translatedStrings, i18nFile)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	fix.MigratedKeys = migratedKeys
+	fmt.Fprintln(fix.Output, i18n.T("Migrated {{.Arg0}} IDs to {{.Arg1}} keys", map[string]interface{}{"Arg0": len(migratedKeys), "Arg1": keyGenerator.KeyFormat}))
+	return nil
+}
+
+// migrateFileKeys replaces the literal source text IDs of the T(...) calls of a go file by their keys, the IDs which
+// are not literals are reported since they cannot be migrated
+func (fix *fixup) migrateFileKeys(file string, englishStringInfos map[string]common.I18nStringInfo, keyGenerator *common.KeyGenerator, migratedKeys map[string][]string) error {
+	src, err := fix.options.FileSystem().ReadFile(file)
+	if err != nil {
+		return common.NewIOError(err)
+	}
+
+	fileSet := token.NewFileSet()
+	astFile, err := parser.ParseFile(fileSet, file, src, parser.ParseComments)
+	if err != nil {
+		return common.NewParseError(err)
+	}
+
+	tFuncNames := common.ParseStringList(common.DEFAULT_T_FUNC_NAMES, ",")
+
+	var edits []common.SourceEdit
+	ast.Inspect(astFile, func(node ast.Node) bool {
+		callExpr, ok := node.(*ast.CallExpr)
+		if !ok || len(callExpr.Args) == 0 || !common.IsTFuncCall(callExpr, fix.options.QualifierFlag, tFuncNames) {
+			return true
+		}
+
+		basicLit, ok := callExpr.Args[0].(*ast.BasicLit)
+		if !ok || basicLit.Kind != token.STRING {
+			fmt.Fprintln(fix.Output, i18n.T("WARNING cannot migrate the ID of the T(...) call at {{.Arg0}}, it is not a string literal", map[string]interface{}{"Arg0": fileSet.Position(callExpr.Pos()).String()}))
+			return true
+		}
+
+		id, err := strconv.Unquote(basicLit.Value)
+		if err != nil {
+			return true
+		}
+
+		stringInfo, ok := englishStringInfos[id]
+		if !ok || !common.IsTextKey(stringInfo) {
+			return true
+		}
+
+		key := keyGenerator.Key(astFile.Name.Name, id)
+		if !slices.Contains(migratedKeys[id], key) {
+			migratedKeys[id] = append(migratedKeys[id], key)
+		}
+
+		edits = append(edits, common.SourceEdit{Pos: basicLit.Pos(), End: basicLit.End(), Text: strconv.Quote(key)})
+		return true
+	})
+
+	if len(edits) == 0 {
+		return nil
+	}
+
+	content, err := common.ApplySourceEdits(fileSet, src, astFile.Comments, edits)
+	if err != nil {
+		return err
+	}
+
+	fix.Println(i18n.T("Migrating the keys of the go file:"), file)
+	return common.NewIOError(fix.options.FileSystem().WriteFile(file, content, 0644))
+}
+
 func (fix *fixup) findSourceStrings(dir string) (sourceStrings map[string]int, err error) {
 	sourceStrings = make(map[string]int)
-	files := getGoFiles(dir)
+	files, err := getGoFiles(dir, fix.options)
+	if err != nil {
+		return sourceStrings, err
+	}
 
-	for _, file := range files {
-		fileStrings, err := common.InspectFile(file, fix.options)
+	cache := common.NewTCallsCache(fix.options)
+	filesStrings, err := common.MapConcurrently(files, common.Jobs(fix.options), func(file string) ([]string, error) {
+		fileStrings, err := common.InspectCachedFileWithLogger(fix.logger, cache, file, fix.options)
 		if err != nil {
-			fmt.Println(i18n.T("Error when inspecting go file: "), file)
-			return sourceStrings, err
+			fmt.Fprintln(fix.Output, i18n.T("Error when inspecting go file: "), file)
 		}
+		return fileStrings, err
+	})
+	if err != nil {
+		return sourceStrings, err
+	}
 
+	for _, fileStrings := range filesStrings {
 		for _, string := range fileStrings {
 			sourceStrings[string]++
 		}
@@ -294,7 +475,7 @@ func (fix *fixup) findSourceStrings(dir string) (sourceStrings map[string]int, e
 func (fix *fixup) findI18nStrings(i18nFile string) (i18nStrings map[string]common.I18nStringInfo, err error) {
 	i18nStrings = make(map[string]common.I18nStringInfo)
 
-	stringInfos, err := common.LoadI18nStringInfos(i18nFile)
+	stringInfos, err := common.LoadI18nStringInfosFS(fix.options.FileSystem(), i18nFile)
 
 	if err != nil {
 		return nil, err
@@ -346,7 +527,7 @@ func getMissingForeignTranslations(englishTranslations, foreignTranslations map[
 	return missingForeignTranslations
 }
 
-func writeStringInfoMapToJSON(localeMap map[string]common.I18nStringInfo, localeFile string) error {
+func writeStringInfoMapToJSON(fsys common.WritableFS, localeMap map[string]common.I18nStringInfo, localeFile string) error {
 	localeArray := common.I18nStringInfoMapValues2Array(localeMap)
 
 	sort.Sort(array(localeArray))
@@ -356,39 +537,39 @@ func writeStringInfoMapToJSON(localeMap map[string]common.I18nStringInfo, locale
 		return err
 	}
 
-	err = ioutil.WriteFile(localeFile, encodedLocale, 0644)
+	err = fsys.WriteFile(localeFile, encodedLocale, 0644)
 	if err != nil {
-		return err
+		return common.NewIOError(err)
 	}
 	return nil
 }
 
-func addTranslations(localeMap map[string]common.I18nStringInfo, localeFile string, addTranslations []string) {
-	fmt.Printf(i18n.T("Adding these strings to the %s translation file:\n"), localeFile)
+func (fix *fixup) addTranslations(localeMap map[string]common.I18nStringInfo, localeFile string, addTranslations []string) {
+	fmt.Fprintf(fix.Output, i18n.T("Adding these strings to the %s translation file:\n"), localeFile)
 
 	for _, id := range addTranslations {
 		localeMap[id] = common.I18nStringInfo{ID: id, Translation: id}
-		fmt.Println("\t", id)
+		fmt.Fprintln(fix.Output, "\t", id)
 	}
 }
 
-func removeTranslations(localeMap map[string]common.I18nStringInfo, localeFile string, remTranslations []string) error {
+func (fix *fixup) removeTranslations(localeMap map[string]common.I18nStringInfo, localeFile string, remTranslations []string) error {
 	var err error
-	fmt.Printf(i18n.T("Removing these strings from the %s translation file:\n"), localeFile)
+	fmt.Fprintf(fix.Output, i18n.T("Removing these strings from the %s translation file:\n"), localeFile)
 
 	for _, id := range remTranslations {
 		delete(localeMap, id)
-		fmt.Println("\t", id)
+		fmt.Fprintln(fix.Output, "\t", id)
 	}
 
 	return err
 }
 
-func updateTranslations(localMap map[string]common.I18nStringInfo, localeFile string, locale string, updTranslations map[string]string) {
-	fmt.Printf(i18n.T("Updating the following strings from the %s translation file:\n"), localeFile)
+func (fix *fixup) updateTranslations(localMap map[string]common.I18nStringInfo, localeFile string, locale string, updTranslations map[string]string) {
+	fmt.Fprintf(fix.Output, i18n.T("Updating the following strings from the %s translation file:\n"), localeFile)
 
 	for key, value := range updTranslations {
-		fmt.Println("\t", key)
+		fmt.Fprintln(fix.Output, "\t", key)
 
 		if locale == "en_US" {
 			localMap[value] = common.I18nStringInfo{ID: value, Translation: value}
@@ -0,0 +1,482 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmds
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"unicode"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maximilien/i18n4go/i18n4go/common"
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+const (
+	GENERATED_MESSAGES_FILENAME = "i18n_messages.go"
+
+	// PLURAL_COUNT_ARG is the template arg set to the count of the plural messages
+	PLURAL_COUNT_ARG = "Count"
+
+	// FUNC_NAME_HASH_LENGTH is the length of the hash of the ID suffixing the names of the functions of the messages
+	// which would have the same name, e.g., HelloWorld315f5b for "Hello, world!"
+	FUNC_NAME_HASH_LENGTH = 6
+
+	MESSAGES_CODE_TEMPLATE = `// Code generated by i18n4go generate from {{.SourceFilename}}. DO NOT EDIT.
+
+package {{.PackageName}}
+
+import "github.com/maximilien/i18n4go/i18n4go/i18n"
+
+// T translates the messages, it must be set, e.g., to the function returned by i18n.Init
+var T i18n.TranslateFunc
+{{range .Messages}}
+// {{.FuncName}} translates {{.Comment}}
+func {{.FuncName}}({{.Params}}) string {
+	return T({{.QuotedID}}{{.Args}})
+}
+{{end}}`
+)
+
+var templateActionRegexp = regexp.MustCompile(`{{.*?}}`)
+
+// MessageAccessor is a generated function translating one message of a resource file, with one parameter per
+// template arg of the message and a count parameter for the plural messages
+type MessageAccessor struct {
+	ID       string
+	FuncName string
+	ArgNames []string
+	Plural   bool
+
+	// Comment is the quoted translation of the message, or its other plural form
+	Comment string
+}
+
+type generate struct {
+	commandLogger
+
+	options common.Options
+
+	Filename      string
+	OutputDirname string
+	PackageName   string
+
+	Messages []MessageAccessor
+}
+
+func NewGenerate(options *common.Options) *generate {
+	outputDirname := options.OutputDirFlag
+	if outputDirname == "" {
+		outputDirname = "."
+	}
+
+	return &generate{options: *options,
+		commandLogger: newCommandLogger(*options),
+		Filename:      options.FilenameFlag,
+		OutputDirname: outputDirname,
+		PackageName:   options.PackageNameFlag,
+		Messages:      []MessageAccessor{},
+	}
+}
+
+// NewGenerateCommand implements 'i18n4go generate' command
+func NewGenerateCommand(options *common.Options) *cobra.Command {
+	generateCmd := &cobra.Command{
+		Use:   "generate",
+		Short: i18n.T("Generates typed Go functions translating the messages of a resource file"),
+		Long:  i18n.T("Generates a Go package with one function per message of a source language resource file, whose parameters are the template args of the message, and a count for the plural messages, so that the IDs and args are checked at compile time"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return NewGenerate(options).Run()
+		},
+	}
+
+	generateCmd.Flags().StringVarP(&options.FilenameFlag, "file", "f", "", i18n.T("the source language JSON resource file, e.g., en_US.all.json"))
+	generateCmd.Flags().StringVarP(&options.OutputDirFlag, "output", "o", "", i18n.T("[optional] the output directory of the generated package, defaults to current directory"))
+	generateCmd.Flags().StringVar(&options.PackageNameFlag, "package", "", i18n.T("[optional] the name of the generated package, defaults to the name of the output directory"))
+	generateCmd.Flags().BoolVar(&options.DryRunFlag, "dry-run", false, i18n.T("[optional] print the generated code instead of writing the i18n_messages.go file"))
+	return generateCmd
+}
+
+func (gen *generate) Options() common.Options {
+	return gen.options
+}
+
+func (gen *generate) Run() error {
+	if gen.Filename == "" {
+		return errors.New(i18n.T("i18n4go: a source language resource file is required"))
+	}
+
+	err := gen.setPackageName()
+	if err != nil {
+		return err
+	}
+
+	err = gen.loadMessages()
+	if err != nil {
+		return err
+	}
+
+	content, err := gen.generateCode()
+	if err != nil {
+		return err
+	}
+
+	if gen.options.DryRunFlag {
+		fmt.Print(string(content))
+		return nil
+	}
+
+	err = common.CreateOutputDirsIfNeededFS(gen.options.FileSystem(), gen.OutputDirname)
+	if err != nil {
+		gen.Println(err)
+		return err
+	}
+
+	fileName := filepath.Join(gen.OutputDirname, GENERATED_MESSAGES_FILENAME)
+	err = gen.options.FileSystem().WriteFile(fileName, content, 0644)
+	if err != nil {
+		gen.logger.Debug(i18n.T("i18n4go: Error writing the generated messages file:"), fileName)
+		return common.NewIOError(err)
+	}
+
+	gen.Println(i18n.T("i18n4go: generated {{.Arg0}} message functions in file: {{.Arg1}}", map[string]any{"Arg0": len(gen.Messages), "Arg1": fileName}))
+	return nil
+}
+
+func (gen *generate) setPackageName() error {
+	if gen.PackageName == "" {
+		absOutputDirname := common.AbsPath(gen.options.FileSystem(), gen.OutputDirname)
+		gen.PackageName = strings.ReplaceAll(strings.ToLower(filepath.Base(absOutputDirname)), "-", "_")
+	}
+
+	if !token.IsIdentifier(gen.PackageName) {
+		return errors.New(i18n.T("i18n4go: invalid package name: {{.Arg0}}, use the --package option", map[string]any{"Arg0": gen.PackageName}))
+	}
+
+	return nil
+}
+
+// loadMessages loads the messages of the resource file, whose translation is either a string or an object with
+// the plural forms of the message, e.g., {"one": "{{.Count}} apple", "other": "{{.Count}} apples"}
+func (gen *generate) loadMessages() error {
+	content, err := gen.options.FileSystem().ReadFile(gen.Filename)
+	if err != nil {
+		return common.NewIOError(err)
+	}
+
+	var rawMessages []struct {
+		ID          string          `json:"id"`
+		Translation json.RawMessage `json:"translation"`
+	}
+
+	err = json.Unmarshal(content, &rawMessages)
+	if err != nil {
+		return common.NewParseError(err)
+	}
+
+	funcNameCounts := make(map[string]int)
+	for _, rawMessage := range rawMessages {
+		message := MessageAccessor{ID: rawMessage.ID}
+
+		var translation string
+		var pluralForms map[string]string
+		var translations []string
+		if err := json.Unmarshal(rawMessage.Translation, &translation); err == nil {
+			translations = []string{translation}
+			message.Comment = strconv.Quote(translation)
+		} else if err := json.Unmarshal(rawMessage.Translation, &pluralForms); err == nil {
+			translations = pluralFormTranslations(pluralForms)
+			message.Plural = true
+			message.Comment = strconv.Quote(pluralForms["other"])
+		} else {
+			return common.NewParseError(errors.New(i18n.T("i18n4go: invalid translation of ID: {{.Arg0}}", map[string]any{"Arg0": rawMessage.ID})))
+		}
+
+		// the args of the printf verbs are not passed to T(...), so the function would drop them
+		if isPrintfMessage(append([]string{rawMessage.ID}, translations...)) {
+			gen.logger.Warn(i18n.T("WARNING: skipping the ID {{.Arg0}} whose printf verbs cannot be generated as parameters, use templated args instead", map[string]any{"Arg0": rawMessage.ID}))
+			continue
+		}
+		message.ArgNames = templateArgNames(translations)
+
+		message.FuncName = messageFuncName(rawMessage.ID)
+		funcNameCounts[message.FuncName]++
+
+		gen.Messages = append(gen.Messages, message)
+	}
+
+	// the names of the messages with the same name are suffixed with a hash of their ID rather than a number, so that
+	// the name of a function does not depend on the order of the messages
+	funcNameIDs := make(map[string]string)
+	for i, message := range gen.Messages {
+		if funcNameCounts[message.FuncName] > 1 {
+			gen.Messages[i].FuncName = message.FuncName + idHash(message.ID)
+		}
+
+		if id, ok := funcNameIDs[gen.Messages[i].FuncName]; ok {
+			return common.NewValidationError(errors.New(i18n.T("i18n4go: the IDs {{.Arg0}} and {{.Arg1}} have the same function name {{.Arg2}}, use key based IDs", map[string]any{"Arg0": id, "Arg1": message.ID, "Arg2": gen.Messages[i].FuncName})))
+		}
+		funcNameIDs[gen.Messages[i].FuncName] = message.ID
+	}
+
+	return nil
+}
+
+func (gen *generate) generateCode() ([]byte, error) {
+	type messageCode struct {
+		FuncName string
+		Comment  string
+		Params   string
+		QuotedID string
+		Args     string
+	}
+
+	var messages []messageCode
+	for _, message := range gen.Messages {
+		messages = append(messages, messageCode{
+			FuncName: message.FuncName,
+			Comment:  message.Comment,
+			Params:   message.params(),
+			QuotedID: strconv.Quote(message.ID),
+			Args:     message.args(),
+		})
+	}
+
+	codeTemplate := template.Must(template.New("messages").Parse(MESSAGES_CODE_TEMPLATE))
+
+	var buffer bytes.Buffer
+	err := codeTemplate.Execute(&buffer, map[string]any{
+		"SourceFilename": filepath.Base(gen.Filename),
+		"PackageName":    gen.PackageName,
+		"Messages":       messages,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return format.Source(buffer.Bytes())
+}
+
+// params returns the parameters of the function, the count of a plural message first
+func (message MessageAccessor) params() string {
+	var params []string
+	if message.Plural {
+		params = append(params, "count int")
+	}
+
+	for i, name := range message.paramNames() {
+		if message.Plural && message.ArgNames[i] == PLURAL_COUNT_ARG {
+			continue
+		}
+		params = append(params, name+" interface{}")
+	}
+
+	return strings.Join(params, ", ")
+}
+
+// args returns the args of the T(...) call following the ID
+func (message MessageAccessor) args() string {
+	var args string
+	if message.Plural {
+		args += ", count"
+	}
+
+	if len(message.ArgNames) == 0 {
+		return args
+	}
+
+	var entries []string
+	for i, name := range message.paramNames() {
+		entries = append(entries, strconv.Quote(message.ArgNames[i])+": "+name)
+	}
+
+	return args + ", map[string]interface{}{" + strings.Join(entries, ", ") + "}"
+}
+
+// paramNames returns the names of the parameters of the template args in the order of ArgNames, count for the
+// PLURAL_COUNT_ARG of a plural message. The names which are already used, e.g., name for both Name and NAME or count
+// for the count arg of a plural message, are suffixed with a number, e.g., name2.
+func (message MessageAccessor) paramNames() []string {
+	usedNames := make(map[string]bool)
+	if message.Plural {
+		usedNames["count"] = true
+	}
+
+	var names []string
+	for _, argName := range message.ArgNames {
+		if message.Plural && argName == PLURAL_COUNT_ARG {
+			names = append(names, "count")
+			continue
+		}
+
+		name := paramName(argName)
+		for i := 2; usedNames[name]; i++ {
+			name = paramName(argName) + strconv.Itoa(i)
+		}
+		usedNames[name] = true
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// Private
+
+// templateArgNames returns the names of the template args of the translations in order of first use, i.e., the
+// name of the field of the template data, e.g., User for {{.User.Name}} and Ratio for {{printf "%.1f" .Ratio}}
+func templateArgNames(translations []string) []string {
+	var argNames []string
+	for _, translation := range translations {
+		tree := parse.New("translation")
+		tree.Mode = parse.SkipFuncCheck
+		if _, err := tree.Parse(translation, "", "", make(map[string]*parse.Tree)); err != nil {
+			continue
+		}
+		argNames = appendFieldNames(argNames, tree.Root)
+	}
+
+	return argNames
+}
+
+// appendFieldNames appends the names of the fields of the template data used by the node which are not in names yet
+func appendFieldNames(names []string, node parse.Node) []string {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return names
+		}
+		for _, child := range node.Nodes {
+			names = appendFieldNames(names, child)
+		}
+	case *parse.ActionNode:
+		names = appendFieldNames(names, node.Pipe)
+	case *parse.PipeNode:
+		if node == nil {
+			return names
+		}
+		for _, command := range node.Cmds {
+			for _, arg := range command.Args {
+				names = appendFieldNames(names, arg)
+			}
+		}
+	case *parse.IfNode:
+		names = appendBranchFieldNames(names, &node.BranchNode)
+	case *parse.RangeNode:
+		names = appendBranchFieldNames(names, &node.BranchNode)
+	case *parse.WithNode:
+		names = appendBranchFieldNames(names, &node.BranchNode)
+	case *parse.FieldNode:
+		if !slices.Contains(names, node.Ident[0]) {
+			names = append(names, node.Ident[0])
+		}
+	}
+
+	return names
+}
+
+func appendBranchFieldNames(names []string, node *parse.BranchNode) []string {
+	names = appendFieldNames(names, node.Pipe)
+	names = appendFieldNames(names, node.List)
+	return appendFieldNames(names, node.ElseList)
+}
+
+// isPrintfMessage returns true if the ID or a translation of a message has printf verbs outside of its template
+// actions, e.g., "Hello %s" but not "{{printf "%5.2f" .Arg0}}"
+func isPrintfMessage(texts []string) bool {
+	for _, text := range texts {
+		if common.IsPrintfString(templateActionRegexp.ReplaceAllString(text, "")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pluralFormTranslations returns the translations of the plural forms, the other form first
+func pluralFormTranslations(pluralForms map[string]string) []string {
+	var translations []string
+	if translation, ok := pluralForms["other"]; ok {
+		translations = append(translations, translation)
+	}
+
+	for _, form := range sortedKeys(pluralForms) {
+		if form != "other" {
+			translations = append(translations, pluralForms[form])
+		}
+	}
+
+	return translations
+}
+
+// messageFuncName returns the exported name of the function of the message ID, made of the words of the ID, e.g.,
+// GreetingsHelloWorld for greetings.hello_world
+func messageFuncName(id string) string {
+	var funcName string
+	for _, word := range strings.Split(common.TextSlug(id), "_") {
+		funcName += capitalize(word)
+	}
+
+	if funcName == "" || !unicode.IsUpper([]rune(funcName)[0]) {
+		funcName = "Message" + funcName
+	}
+
+	return funcName
+}
+
+// idHash returns the first FUNC_NAME_HASH_LENGTH hex digits of the SHA-256 of the ID
+func idHash(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])[:FUNC_NAME_HASH_LENGTH]
+}
+
+// paramName returns the name of the parameter of a template arg, e.g., fileName for FileName and url for URL
+func paramName(argName string) string {
+	name := argName
+	if strings.ToUpper(argName) == argName {
+		name = strings.ToLower(argName)
+	} else {
+		runes := []rune(argName)
+		name = string(unicode.ToLower(runes[0])) + string(runes[1:])
+	}
+
+	if token.IsKeyword(name) {
+		name += "Arg"
+	}
+
+	return name
+}
+
+func capitalize(word string) string {
+	if word == "" {
+		return word
+	}
+
+	runes := []rune(word)
+	return string(unicode.ToUpper(runes[0])) + string(runes[1:])
+}
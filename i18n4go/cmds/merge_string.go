@@ -15,8 +15,6 @@
 package cmds
 
 import (
-	"fmt"
-	"io/ioutil"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -26,7 +24,15 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// MergedFile is a combined language file with its i18n strings
+type MergedFile struct {
+	Filename        string
+	I18nStringInfos []common.I18nStringInfo
+}
+
 type mergeStrings struct {
+	commandLogger
+
 	options common.Options
 
 	I18nStringInfos []common.I18nStringInfo
@@ -34,10 +40,14 @@ type mergeStrings struct {
 	Recurse        bool
 	SourceLanguage string
 	Directory      string
+
+	// MergedFiles are the combined language files, in the order they are saved
+	MergedFiles []MergedFile
 }
 
 func NewMergeStrings(options *common.Options) *mergeStrings {
 	return &mergeStrings{
+		commandLogger:   newCommandLogger(*options),
 		options:         *options,
 		I18nStringInfos: []common.I18nStringInfo{},
 		Recurse:         options.RecurseFlag,
@@ -67,33 +77,17 @@ func (ms *mergeStrings) Options() common.Options {
 	return ms.options
 }
 
-func (ms *mergeStrings) Println(a ...interface{}) (int, error) {
-	if ms.options.VerboseFlag {
-		return fmt.Println(a...)
-	}
-
-	return 0, nil
-}
-
-func (ms *mergeStrings) Printf(msg string, a ...interface{}) (int, error) {
-	if ms.options.VerboseFlag {
-		return fmt.Printf(msg, a...)
-	}
-
-	return 0, nil
-}
-
 func (ms *mergeStrings) Run() error {
 	return ms.combineStringInfosPerDirectory(ms.Directory)
 }
 
 func (ms *mergeStrings) combineStringInfosPerDirectory(directory string) error {
-	files, directories := getFilesAndDir(directory)
+	files, directories := getFilesAndDir(ms.options.FileSystem(), directory)
 	fileList := ms.matchFileToSourceLanguage(files, ms.SourceLanguage)
 
 	combinedMap := map[string]common.I18nStringInfo{}
 	for _, file := range fileList {
-		StringInfos, err := common.LoadI18nStringInfos(file)
+		StringInfos, err := common.LoadI18nStringInfosFS(ms.options.FileSystem(), file)
 		if err != nil {
 			return err
 		}
@@ -106,6 +100,9 @@ func (ms *mergeStrings) combineStringInfosPerDirectory(directory string) error {
 	sort.Sort(ms)
 	common.SaveI18nStringInfos(ms, ms.Options(), ms.I18nStringInfos, filePath)
 	ms.Println(i18n.T("i18n4go: saving combined language file: ") + filePath)
+	if len(ms.I18nStringInfos) != 0 {
+		ms.MergedFiles = append(ms.MergedFiles, MergedFile{Filename: filePath, I18nStringInfos: ms.I18nStringInfos})
+	}
 
 	if ms.Recurse {
 		for _, directory = range directories {
@@ -119,8 +116,8 @@ func (ms *mergeStrings) combineStringInfosPerDirectory(directory string) error {
 	return nil
 }
 
-func getFilesAndDir(dir string) (files []string, dirs []string) {
-	contents, _ := ioutil.ReadDir(dir)
+func getFilesAndDir(fsys common.FS, dir string) (files []string, dirs []string) {
+	contents, _ := fsys.ReadDir(dir)
 
 	for _, fileInfo := range contents {
 		if !fileInfo.IsDir() {
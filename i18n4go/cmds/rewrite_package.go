@@ -15,30 +15,43 @@
 package cmds
 
 import (
-	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
 
 	"go/ast"
 	"go/build"
-	"go/format"
 	"go/parser"
 	"go/token"
-	"io/ioutil"
+	"go/types"
+	"io"
+	"path"
 
 	"github.com/go-bindata/go-bindata/v3"
 	"github.com/maximilien/i18n4go/i18n4go/common"
 	"github.com/maximilien/i18n4go/i18n4go/i18n"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/tools/go/ast/astutil"
 
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 )
 
 const (
+	I18N_PACKAGE_PATH = "github.com/maximilien/i18n4go/i18n4go/i18n"
+
+	// PRINTF_FUNC_NAMES are the names of the functions and methods taking a fmt format, e.g., fmt.Sprintf, log.Fatalf,
+	// (*testing.T).Errorf or i18n.Errorf, matched whatever their package or receiver since the calls are not typed
+	PRINTF_FUNC_NAMES = "Printf,Sprintf,Fprintf,Appendf,Errorf,Fatalf,Panicf,Logf,Skipf"
+
+	// PRINTF_WRITER_FUNC_NAMES are the printf-like functions whose format follows the writer or buffer, e.g., fmt.Fprintf
+	PRINTF_WRITER_FUNC_NAMES = "Fprintf,Appendf"
+
 	INIT_CODE_SNIPPET = `package __PACKAGE__NAME__
 
 import (
@@ -56,7 +69,13 @@ func init() {
 }`
 )
 
+// typeErrorLitRegexp matches the string literals quoted by the messages of the type errors, wrapped with T() or not,
+// since the literals of a rewritten file are wrapped and may be replaced by their key
+var typeErrorLitRegexp = regexp.MustCompile(`\bT\("(?:[^"\\]|\\.)*"\)|"(?:[^"\\]|\\.)*"`)
+
 type rewritePackage struct {
+	commandLogger
+
 	options common.Options
 
 	Filename                string
@@ -75,25 +94,94 @@ type rewritePackage struct {
 	UpdatedExtractedStrings map[string]common.I18nStringInfo
 	SaveExtractedStrings    bool
 
+	// extractedStringIDs are the IDs of UpdatedExtractedStrings in the order of the i18n strings file
+	extractedStringIDs []string
+
+	// extractedTextIDs are the IDs of the key based i18n strings by source text
+	extractedTextIDs map[string]string
+
+	// keyGenerator generates the IDs of the rewritten strings of packageName with the key format
+	keyGenerator *common.KeyGenerator
+	packageName  string
+
 	TotalStrings int
 	TotalFiles   int
 
+	// WrittenFiles are the rewritten go files, i18n init files and i18n strings files, in the order they are written
+	WrittenFiles []string
+
+	// Output is where the diff and the skipped strings are reported, the standard output by default
+	Output io.Writer
+
 	IgnoreRegexp *regexp.Regexp
+
+	// tFuncNames are the names of the functions whose calls are already translated, qualified or not
+	tFuncNames []string
+
+	// i18nQualifier is the name of the i18n package in the file being rewritten, imported when i18nImportNeeded
+	i18nQualifier    string
+	i18nImportNeeded bool
+
+	// fmtQualifier is the name of the fmt package in the file being rewritten, empty if it is not imported
+	fmtQualifier string
+
+	// fileSet and typesInfo of the file being rewritten, used to skip the literals which cannot be translated
+	fileSet   *token.FileSet
+	typesInfo *types.Info
+
+	// untranslatableLits of the file being rewritten, e.g., the Use of the cobra commands and the names of the flags
+	untranslatableLits map[*ast.BasicLit]bool
+
+	// typeChecker type checks the packages of the rewritten files, before and after they are rewritten
+	typeChecker *common.TypeChecker
+
+	// walker skips the directories and files of the rewritten source tree which are not built or ignored
+	walker *common.SourceWalker
+
+	// edits of the source of the file being rewritten, so that the code around the rewritten expressions is kept
+	edits []common.SourceEdit
+
+	// diffFiles are the files written with the diff option, printed as a unified diff once all files are rewritten
+	diffFiles     map[string]*diffFile
+	diffFileNames []string
+}
+
+type diffFile struct {
+	oldContent []byte
+	content    []byte
+	exists     bool
 }
 
 func NewRewritePackage(options *common.Options) *rewritePackage {
+	commandLogger := newCommandLogger(*options)
+
+	outputDirname := options.OutputDirFlag
+	if options.DiffFlag {
+		outputDirname = options.DirnameFlag
+	}
+
 	var compiledRegexp *regexp.Regexp
 	if options.IgnoreRegexpFlag != "" {
 		compiledReg, err := regexp.Compile(options.IgnoreRegexpFlag)
 		if err != nil {
-			fmt.Println(i18n.T("WARNING compiling ignore-regexp:"), err)
+			commandLogger.logger.Warn(i18n.T("WARNING compiling ignore-regexp:"), err)
 		}
 		compiledRegexp = compiledReg
 	}
 
+	tFuncNames := common.ParseStringList(options.TFuncNamesFlag, ",")
+	if len(tFuncNames) == 0 {
+		tFuncNames = common.ParseStringList(common.DEFAULT_T_FUNC_NAMES, ",")
+	}
+	if !slices.Contains(tFuncNames, "T") {
+		// the T(...) calls added by a previous run
+		tFuncNames = append(tFuncNames, "T")
+	}
+
 	return &rewritePackage{options: *options,
+		commandLogger:           commandLogger,
 		Filename:                options.FilenameFlag,
-		OutputDirname:           options.OutputDirFlag,
+		OutputDirname:           outputDirname,
 		I18nStringsFilename:     options.I18nStringsFilenameFlag,
 		I18nStringsDirname:      options.I18nStringsDirnameFlag,
 		RootPath:                options.RootPathFlag,
@@ -106,6 +194,11 @@ func NewRewritePackage(options *common.Options) *rewritePackage {
 		Dirname:      options.DirnameFlag,
 		Recurse:      options.RecurseFlag,
 		IgnoreRegexp: compiledRegexp,
+
+		tFuncNames:  tFuncNames,
+		typeChecker: common.NewTypeChecker(),
+		diffFiles:   make(map[string]*diffFile),
+		Output:      os.Stdout,
 	}
 }
 
@@ -130,6 +223,12 @@ func NewRewritePackageCommand(options *common.Options) *cobra.Command {
 	rewritePackageCmd.Flags().StringVar(&options.RootPathFlag, "root-path", "", i18n.T("the root path to the Go source files whose packages are being rewritten, defaults to working directory, if not specified"))
 	rewritePackageCmd.Flags().StringVar(&options.InitCodeSnippetFilenameFlag, "init-code-snippet-filename", "", i18n.T("[optional] the path to a file containing the template snippet for the code that is used for go-i18n initialization"))
 	rewritePackageCmd.Flags().StringVar(&options.IgnoreRegexpFlag, "ignore-regexp", ".*test.*", i18n.T("a perl-style regular expression for files to ignore, e.g., \".*test.*\""))
+	rewritePackageCmd.Flags().StringVarP(&options.QualifierFlag, "qualifier", "q", "", i18n.T("[optional] the qualifier string that is used when using the i18n.T(...) function, default to nothing but could be set to `i18n` so that all calls would be: i18n.T(...)"))
+	rewritePackageCmd.Flags().StringVar(&options.TFuncNamesFlag, "t-func-names", common.DEFAULT_T_FUNC_NAMES, i18n.T("[optional] a comma separated list of the names of the functions translating strings, whose calls are not rewritten again"))
+	rewritePackageCmd.Flags().BoolVar(&options.RegenerateInitFlag, "regenerate-init", false, i18n.T("[optional] regenerate the i18n_init.go files which already exist"))
+	rewritePackageCmd.Flags().StringVar(&options.KeyFormatFlag, "key-format", common.TEXT_KEY_FORMAT, i18n.T("[optional] the format of the IDs of the strings, one of: text, slug, hash, the slug and hash IDs are scoped by package, e.g., greetings.hello_world"))
+	rewritePackageCmd.Flags().BoolVar(&options.DiffFlag, "diff", false, i18n.T("print a unified diff of the rewritten files and updated i18n strings files instead of writing them"))
+	addSourceWalkerFlags(rewritePackageCmd, options)
 	return rewritePackageCmd
 }
 
@@ -137,23 +236,22 @@ func (rp *rewritePackage) Options() common.Options {
 	return rp.options
 }
 
-func (rp *rewritePackage) Println(a ...interface{}) (int, error) {
-	if rp.options.VerboseFlag {
-		return fmt.Println(a...)
+func (rp *rewritePackage) Run() error {
+	keyGenerator, err := common.NewKeyGenerator(rp.options.KeyFormatFlag)
+	if err != nil {
+		return err
 	}
 
-	return 0, nil
-}
-
-func (rp *rewritePackage) Printf(msg string, a ...interface{}) (int, error) {
-	if rp.options.VerboseFlag {
-		return fmt.Printf(msg, a...)
+	if keyGenerator.KeyFormat != common.TEXT_KEY_FORMAT && rp.I18nStringsFilename == "" && rp.I18nStringsDirname == "" {
+		return errors.New(i18n.T("i18n4go: the {{.Arg0}} key format requires the i18n strings file or dirname to save the source texts", map[string]interface{}{"Arg0": keyGenerator.KeyFormat}))
 	}
+	rp.keyGenerator = keyGenerator
 
-	return 0, nil
-}
-
-func (rp *rewritePackage) Run() error {
+	walker, err := common.NewSourceWalker(rp.options)
+	if err != nil {
+		return err
+	}
+	rp.walker = walker
 
 	if rp.options.FilenameFlag != "" {
 		if err := rp.loadStringsToBeTranslated(rp.I18nStringsFilename); err != nil {
@@ -169,7 +267,10 @@ func (rp *rewritePackage) Run() error {
 		}
 	}
 
-	if err := rp.generateBindataFromI18nStrings(); err != nil {
+	if rp.options.DiffFlag {
+		rp.Println(i18n.T("i18n4go: not generating the i18n_resources.go file with the diff option"))
+		rp.printDiff()
+	} else if err := rp.generateBindataFromI18nStrings(); err != nil {
 		return err
 	}
 
@@ -190,17 +291,94 @@ func (rp *rewritePackage) generateBindataFromI18nStrings() error {
 				Recursive: false,
 			})
 		}
-		err := bindata.Translate(bindataConfig)
+		var err error
+		if _, ok := rp.options.FileSystem().(common.OsFS); ok {
+			err = bindata.Translate(bindataConfig)
+		} else {
+			err = rp.translateBindataFS(bindataConfig)
+		}
 		if err != nil {
-			return err
+			return common.NewIOError(err)
 		}
+		rp.WrittenFiles = append(rp.WrittenFiles, bindataConfig.Output)
 	}
 	return nil
 }
 
+// translateBindataFS generates the bindata of the i18n strings files of a file system which is not the OS one, since
+// go-bindata only reads and writes the OS one, the files are copied to a temporary directory at their paths so that
+// the assets keep their names, except the paths out of the working directory which are made absolute
+func (rp *rewritePackage) translateBindataFS(bindataConfig *bindata.Config) error {
+	fsys := rp.options.FileSystem()
+
+	tmpDirname, err := os.MkdirTemp("", "i18n4go_bindata")
+	if err != nil {
+		return common.NewIOError(err)
+	}
+	defer os.RemoveAll(tmpDirname)
+
+	inputDirname := filepath.Join(tmpDirname, "input")
+	for i, input := range bindataConfig.Input {
+		fileName := input.Path
+		if strings.HasPrefix(fileName, "..") {
+			fileName = common.AbsPath(fsys, fileName)
+		}
+
+		fileInfo, err := fsys.Stat(input.Path)
+		if err != nil {
+			return err
+		}
+
+		content, err := fsys.ReadFile(input.Path)
+		if err != nil {
+			return common.NewIOError(err)
+		}
+
+		tmpFilename := filepath.Join(inputDirname, fileName)
+		err = os.MkdirAll(filepath.Dir(tmpFilename), 0755)
+		if err != nil {
+			return common.NewIOError(err)
+		}
+
+		err = os.WriteFile(tmpFilename, content, fileInfo.Mode().Perm())
+		if err != nil {
+			return common.NewIOError(err)
+		}
+
+		err = os.Chtimes(tmpFilename, fileInfo.ModTime(), fileInfo.ModTime())
+		if err != nil {
+			return err
+		}
+
+		bindataConfig.Input[i].Path = tmpFilename
+	}
+
+	outputFilename := bindataConfig.Output
+	bindataConfig.Prefix = inputDirname
+	bindataConfig.Output = filepath.Join(tmpDirname, filepath.Base(outputFilename))
+	defer func() { bindataConfig.Output = outputFilename }()
+
+	err = bindata.Translate(bindataConfig)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(bindataConfig.Output)
+	if err != nil {
+		return common.NewIOError(err)
+	}
+
+	err = common.CreateOutputDirsIfNeededFS(fsys, filepath.Dir(outputFilename))
+	if err != nil {
+		return err
+	}
+
+	return common.NewIOError(fsys.WriteFile(outputFilename, content, 0644))
+}
+
 func (rp *rewritePackage) loadStringsToBeTranslated(fileName string) error {
 	if fileName != "" {
-		stringList, err := common.LoadI18nStringInfos(fileName)
+		stringList, err := rp.loadI18nStringInfos(fileName)
 		if err != nil {
 			return err
 		}
@@ -211,6 +389,16 @@ func (rp *rewritePackage) loadStringsToBeTranslated(fileName string) error {
 		}
 
 		rp.UpdatedExtractedStrings = common.CopyI18nStringInfoMap(rp.ExtractedStrings)
+
+		rp.extractedStringIDs = nil
+		rp.extractedTextIDs = make(map[string]string)
+		for _, stringInfo := range stringList {
+			rp.extractedStringIDs = append(rp.extractedStringIDs, stringInfo.ID)
+			if !common.IsTextKey(stringInfo) {
+				rp.extractedTextIDs[stringInfo.Translation] = stringInfo.ID
+				rp.keyGenerator.Reserve(stringInfo.ID, stringInfo.Translation)
+			}
+		}
 	}
 
 	return nil
@@ -220,15 +408,15 @@ func (rp *rewritePackage) processDir(dirName string, recursive bool) error {
 	rp.Printf(i18n.T("i18n4go: rewriting strings in dir {{.Arg0}}, recursive: {{.Arg1}}\n", map[string]interface{}{"Arg0": dirName, "Arg1": recursive}))
 	rp.Println()
 
-	fileInfos, _ := ioutil.ReadDir(dirName)
+	fileInfos, _ := rp.options.FileSystem().ReadDir(dirName)
 	for _, fileInfo := range fileInfos {
 		if fileInfo.IsDir() {
-			if recursive {
+			if recursive && !rp.walker.SkipDir(filepath.Join(dirName, fileInfo.Name())) {
 				rp.processDir(filepath.Join(dirName, fileInfo.Name()), recursive)
 			} else {
 				continue
 			}
-		} else if rp.ignoreFile(filepath.Base(fileInfo.Name())) {
+		} else if rp.ignoreFile(filepath.Base(fileInfo.Name())) && !rp.walker.SkipFile(filepath.Join(dirName, fileInfo.Name())) {
 			i18nFilename := rp.I18nStringsFilename
 			if rp.I18nStringsDirname != "" {
 				i18nFilename = filepath.Base(fileInfo.Name()) + "." + rp.options.SourceLanguageFlag + ".json"
@@ -238,7 +426,7 @@ func (rp *rewritePackage) processDir(dirName string, recursive bool) error {
 			rp.I18nStringsFilePaths = append(rp.I18nStringsFilePaths, rp.I18nStringsFilename)
 			rp.Printf(i18n.T("i18n4go: loading JSON strings from file: {{.Arg0}}\n", map[string]interface{}{"Arg0": rp.I18nStringsFilename}))
 			if err := rp.loadStringsToBeTranslated(rp.I18nStringsFilename); err != nil {
-				rp.Println(i18n.T("i18n4go: WARNING could not find JSON file:"), rp.I18nStringsFilename, err.Error())
+				rp.logger.Warn(i18n.T("i18n4go: WARNING could not find JSON file:"), rp.I18nStringsFilename, err.Error())
 				rp.resetProcessing()
 				continue
 			}
@@ -260,6 +448,8 @@ func (rp *rewritePackage) processDir(dirName string, recursive bool) error {
 func (rp *rewritePackage) resetProcessing() {
 	rp.ExtractedStrings = nil
 	rp.UpdatedExtractedStrings = nil
+	rp.extractedStringIDs = nil
+	rp.extractedTextIDs = nil
 	rp.I18nStringsFilename = ""
 	rp.RootPackageName = ""
 	rp.SaveExtractedStrings = false
@@ -269,7 +459,7 @@ func (rp *rewritePackage) ignoreFile(fileName string) bool {
 	return fileName != "i18n_init.go" &&
 		!strings.HasPrefix(fileName, ".") &&
 		strings.HasSuffix(fileName, ".go") &&
-		rp.IgnoreRegexp != nil && !rp.IgnoreRegexp.MatchString(fileName)
+		(rp.IgnoreRegexp == nil || !rp.IgnoreRegexp.MatchString(fileName))
 }
 
 func (rp *rewritePackage) processFilename(fileName string) error {
@@ -278,15 +468,18 @@ func (rp *rewritePackage) processFilename(fileName string) error {
 
 	fileSet := token.NewFileSet()
 
-	var absFilePath = fileName
-	if !filepath.IsAbs(absFilePath) {
-		absFilePath = filepath.Join(os.Getenv("PWD"), absFilePath)
+	absFilePath := common.AbsPath(rp.options.FileSystem(), fileName)
+
+	src, err := rp.options.FileSystem().ReadFile(absFilePath)
+	if err != nil {
+		rp.Println(err)
+		return common.NewIOError(err)
 	}
 
-	astFile, err := parser.ParseFile(fileSet, absFilePath, nil, parser.ParseComments|parser.AllErrors)
+	astFile, err := parser.ParseFile(fileSet, absFilePath, src, parser.ParseComments|parser.AllErrors)
 	if err != nil {
 		rp.Println(err)
-		return err
+		return common.NewParseError(err)
 	}
 
 	if strings.HasSuffix(fileName, "_test.go") {
@@ -296,7 +489,7 @@ func (rp *rewritePackage) processFilename(fileName string) error {
 
 	importPath, err := rp.determineImportPath(absFilePath)
 	if err != nil {
-		rp.Println(i18n.T("i18n4go: error determining the import path:"), err.Error())
+		rp.logger.Debug(i18n.T("i18n4go: error determining the import path:"), err.Error())
 		return err
 	}
 
@@ -307,32 +500,83 @@ func (rp *rewritePackage) processFilename(fileName string) error {
 	if rp.RootPackageName == "" {
 		rp.RootPackageName = astFile.Name.Name
 	}
+	rp.packageName = astFile.Name.Name
 
-	outputDir := filepath.Join(rp.OutputDirname, filepath.Dir(rp.relativePathForFile(fileName)))
-	err = rp.addInitFuncToPackage(astFile.Name.Name, outputDir, importPath)
+	packageFiles, err := common.ParsePackageFilesFS(rp.options.FileSystem(), fileSet, absFilePath)
 	if err != nil {
-		rp.Println(i18n.T("i18n4go: error adding init() func to package:"), err.Error())
+		rp.logger.Debug(i18n.T("i18n4go: error parsing the package files:"), err.Error())
 		return err
 	}
 
+	pkg, typesInfo, typeErrors := rp.typeChecker.TypeCheckFiles(fileSet, append(packageFiles, astFile))
+	rp.fileSet, rp.typesInfo = fileSet, typesInfo
+
+	var i18nImported bool
+	rp.i18nQualifier, i18nImported = i18nImportName(astFile)
+	rp.fmtQualifier = common.ImportName(astFile, "fmt")
+	rp.i18nImportNeeded = false
+	rp.edits = nil
+
+	importDecls := importDeclsForASTFile(astFile)
+
 	err = rp.insertTFuncCall(astFile)
 	if err != nil {
-		rp.Println(i18n.T("i18n4go: error appending i18n.T() to AST file:"), err.Error())
+		rp.logger.Debug(i18n.T("i18n4go: error appending i18n.T() to AST file:"), err.Error())
+		return err
+	}
+
+	if rp.i18nImportNeeded && !i18nImported {
+		if rp.i18nQualifier == path.Base(I18N_PACKAGE_PATH) {
+			astutil.AddImport(fileSet, astFile, I18N_PACKAGE_PATH)
+		} else {
+			astutil.AddNamedImport(fileSet, astFile, rp.i18nQualifier, I18N_PACKAGE_PATH)
+		}
+	}
+
+	// fmt.Errorf(...) may have been the only use of fmt before the i18n.Errorf(...) rewrite
+	if rp.i18nImportNeeded && !astutil.UsesImport(astFile, "fmt") {
+		if rp.fmtQualifier == "fmt" {
+			astutil.DeleteImport(fileSet, astFile, "fmt")
+		} else {
+			astutil.DeleteNamedImport(fileSet, astFile, rp.fmtQualifier, "fmt")
+		}
+	}
+
+	if rp.i18nImportNeeded {
+		rp.edits = append(rp.edits, importDeclsEdits(astFile, importDecls)...)
+	}
+
+	content, err := common.ApplySourceEdits(fileSet, src, astFile.Comments, rp.edits)
+	if err != nil {
+		rp.logger.Debug(i18n.T("i18n4go: error applying the edits to the source file:"), err.Error())
+		return err
+	}
+
+	err = rp.typeCheckRewrittenFile(absFilePath, content, pkg, typeErrors)
+	if err != nil {
+		rp.logger.Debug(i18n.T("i18n4go: error type checking the rewritten file:"), err.Error())
+		return err
+	}
+
+	// the init func is only added once the rewritten file type checks
+	outputDir := filepath.Join(rp.OutputDirname, filepath.Dir(rp.relativePathForFile(fileName)))
+	err = rp.addInitFuncToPackage(astFile.Name.Name, outputDir, importPath)
+	if err != nil {
+		rp.logger.Debug(i18n.T("i18n4go: error adding init() func to package:"), err.Error())
 		return err
 	}
 
 	relativeFilePath := rp.relativePathForFile(fileName)
-	err = rp.saveASTFile(relativeFilePath, fileName, astFile, fileSet)
+	err = rp.saveRewrittenFile(relativeFilePath, fileName, content)
 	if err != nil {
-		rp.Println(i18n.T("i18n4go: error saving AST file:"), err.Error())
+		rp.logger.Debug(i18n.T("i18n4go: error saving AST file:"), err.Error())
 		return err
 	}
 
 	if rp.SaveExtractedStrings {
-		i18nStringInfos := common.I18nStringInfoMapValues2Array(rp.UpdatedExtractedStrings)
-		err := common.SaveI18nStringInfos(rp, rp.Options(), i18nStringInfos, rp.I18nStringsFilename)
+		err := rp.saveExtractedStrings()
 		if err != nil {
-			rp.Println(i18n.T("i18n4go: error saving updated i18n strings file:"), err.Error())
+			rp.logger.Debug(i18n.T("i18n4go: error saving updated i18n strings file:"), err.Error())
 			return err
 		}
 	}
@@ -347,13 +591,14 @@ func (rp *rewritePackage) determineImportPath(filePath string) (string, error) {
 		otherPkgImportPath string
 	)
 	if rp.options.RootPathFlag == "" {
-		rp.Println(i18n.T("i18n4go: using the PWD as the rootPath:"), os.Getenv("PWD"))
-		rp.RootPath = os.Getenv("PWD")
+		rp.RootPath = common.AbsPath(rp.options.FileSystem(), ".")
+		rp.Println(i18n.T("i18n4go: using the PWD as the rootPath:"), rp.RootPath)
 	}
 	rp.Println(i18n.T("i18n4go: determining import path using root path:"), rp.RootPath)
-	pkg, err := build.Default.ImportDir(rp.RootPath, build.ImportMode(1))
+	buildContext := common.BuildContextFS(rp.options.FileSystem(), build.Default)
+	pkg, err := buildContext.ImportDir(rp.RootPath, build.ImportMode(1))
 	if err != nil {
-		rp.Println(i18n.T("i18n4go: error getting root path import:"), err.Error())
+		rp.logger.Debug(i18n.T("i18n4go: error getting root path import:"), err.Error())
 		return "", err
 	}
 
@@ -365,9 +610,9 @@ func (rp *rewritePackage) determineImportPath(filePath string) (string, error) {
 		rp.Println(i18n.T("i18n4go: got a root pkg with import path:"), pkg.ImportPath)
 	}
 
-	otherPkg, err := build.Default.ImportDir(dirName, build.ImportMode(0))
+	otherPkg, err := buildContext.ImportDir(dirName, build.ImportMode(0))
 	if err != nil {
-		rp.Println(i18n.T("i18n4go: error getting root path import:"), err.Error())
+		rp.logger.Debug(i18n.T("i18n4go: error getting root path import:"), err.Error())
 		return "", err
 	}
 
@@ -391,6 +636,8 @@ func (rp *rewritePackage) determineImportPath(filePath string) (string, error) {
 
 func (rp *rewritePackage) insertTFuncCall(astFile *ast.File) error {
 	rp.Println(i18n.T("i18n4go: inserting i18n.T() calls for strings that need to be translated"))
+	rp.untranslatableLits = common.UntranslatableBasicLits(astFile)
+
 	var declarations []ast.Decl
 	if len(astFile.Imports) > 0 {
 		declarations = astFile.Decls[1:]
@@ -398,36 +645,79 @@ func (rp *rewritePackage) insertTFuncCall(astFile *ast.File) error {
 		declarations = astFile.Decls[0:]
 	}
 
-	for _, decl := range declarations {
-		ast.Inspect(decl, func(node ast.Node) bool {
-			switch node.(type) {
-			case *ast.CallExpr:
-				if !rp.callExprTFunc(node.(*ast.CallExpr)) {
-					return false // don't recurse infinitely
-				}
-			case *ast.AssignStmt:
-				rp.assignStmtTFunc(node.(*ast.AssignStmt))
-			case *ast.ValueSpec:
-				rp.valueSpecTFunc(node.(*ast.ValueSpec))
-			case *ast.CompositeLit:
-				rp.compositeLitTFunc(node.(*ast.CompositeLit))
-			case *ast.KeyValueExpr:
-				rp.keyValueExprTFunc(node.(*ast.KeyValueExpr))
-			case *ast.ReturnStmt:
-				rp.returnStmtTFunc(node.(*ast.ReturnStmt))
-			case *ast.BinaryExpr:
-				rp.binaryExprTFunc(node.(*ast.BinaryExpr))
-			case *ast.IndexExpr:
-				rp.indexExprTFunc(node.(*ast.IndexExpr))
+	var inspectNode func(node ast.Node) bool
+	inspectNode = func(node ast.Node) bool {
+		switch node.(type) {
+		case *ast.GenDecl:
+			if node.(*ast.GenDecl).Tok == token.CONST {
+				rp.skipStringLits(node, i18n.T("a constant expression is required"))
+				return false
 			}
+		case *ast.ArrayType:
+			arrayType := node.(*ast.ArrayType)
+			if arrayType.Len != nil {
+				rp.skipStringLits(arrayType.Len, i18n.T("a constant expression is required"))
+				ast.Inspect(arrayType.Elt, inspectNode)
+				return false
+			}
+		case *ast.CaseClause:
+			caseClause := node.(*ast.CaseClause)
+			for _, expr := range caseClause.List {
+				rp.skipStringLits(expr, i18n.T("switch cases are compared with the untranslated values"))
+			}
+			for _, stmt := range caseClause.Body {
+				ast.Inspect(stmt, inspectNode)
+			}
+			return false
+		case *ast.CallExpr:
+			if !rp.callExprTFunc(node.(*ast.CallExpr)) {
+				return false // don't recurse infinitely
+			}
+		case *ast.AssignStmt:
+			rp.assignStmtTFunc(node.(*ast.AssignStmt))
+		case *ast.ValueSpec:
+			rp.valueSpecTFunc(node.(*ast.ValueSpec))
+		case *ast.CompositeLit:
+			rp.compositeLitTFunc(node.(*ast.CompositeLit))
+		case *ast.KeyValueExpr:
+			rp.keyValueExprTFunc(node.(*ast.KeyValueExpr))
+		case *ast.ReturnStmt:
+			rp.returnStmtTFunc(node.(*ast.ReturnStmt))
+		case *ast.BinaryExpr:
+			rp.binaryExprTFunc(node.(*ast.BinaryExpr))
+		case *ast.IndexExpr:
+			rp.indexExprTFunc(node.(*ast.IndexExpr))
+		}
 
-			return true
-		})
+		return true
+	}
+
+	for _, decl := range declarations {
+		ast.Inspect(decl, inspectNode)
 	}
 
 	return nil
 }
 
+// skipStringLits reports the string literals of node which are not rewritten, e.g., in constant expressions
+func (rp *rewritePackage) skipStringLits(node ast.Node, reason string) {
+	ast.Inspect(node, func(node ast.Node) bool {
+		if basicLit, ok := node.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
+			rp.skipStringLit(basicLit, reason)
+		}
+		return true
+	})
+}
+
+func (rp *rewritePackage) skipStringLit(basicLit *ast.BasicLit, reason string) {
+	valueWithoutQuotes, _ := strconv.Unquote(basicLit.Value)
+	if _, ok := rp.extractedString(valueWithoutQuotes); !ok && rp.ExtractedStrings != nil {
+		return
+	}
+
+	fmt.Fprintf(rp.Output, i18n.T("i18n4go: WARNING skipping string {{.Arg0}} at {{.Arg1}}, {{.Arg2}}\n", map[string]interface{}{"Arg0": basicLit.Value, "Arg1": rp.fileSet.Position(basicLit.Pos()), "Arg2": reason}))
+}
+
 func (rp *rewritePackage) indexExprTFunc(indexExpr *ast.IndexExpr) {
 	if index, ok := indexExpr.Index.(*ast.BasicLit); ok {
 		indexExpr.Index = rp.wrapBasicLitWithT(index)
@@ -493,9 +783,8 @@ func (rp *rewritePackage) valueSpecTFunc(valueSpec *ast.ValueSpec) bool {
 }
 
 func (rp *rewritePackage) callExprTFunc(callExpr *ast.CallExpr) bool {
-	callFuncIdent, ok := callExpr.Fun.(*ast.Ident)
-	if ok && (callFuncIdent.Name == "T") { // yeah, not the best
-		return false
+	if common.IsTFuncCall(callExpr, rp.options.QualifierFlag, rp.tFuncNames) {
+		return false // the strings are already translated
 	}
 
 	switch len(callExpr.Args) {
@@ -513,6 +802,13 @@ func (rp *rewritePackage) callExprTFunc(callExpr *ast.CallExpr) bool {
 }
 
 func (rp *rewritePackage) wrapMultiArgsCallExpr(callExpr *ast.CallExpr) {
+	// the args of a spread slice, e.g., fmt.Printf("%v %v", args...), cannot be mapped to templated args, so that
+	// the format is only wrapped with T()
+	if callExpr.Ellipsis.IsValid() {
+		rp.wrapExprArgs(callExpr.Args)
+		return
+	}
+
 	for i, arg := range callExpr.Args {
 		if basicLit, ok := arg.(*ast.BasicLit); ok {
 			if basicLit.Kind == token.STRING {
@@ -520,8 +816,9 @@ func (rp *rewritePackage) wrapMultiArgsCallExpr(callExpr *ast.CallExpr) {
 
 				if common.IsTemplatedString(valueWithoutQuotes) {
 					rp.wrapCallExprWithTemplatedT(basicLit, callExpr, i)
-				} else if common.IsInterpolatedString(valueWithoutQuotes) {
+				} else if common.IsInterpolatedString(valueWithoutQuotes) || (isPrintfCallExpr(callExpr) && common.IsPrintfString(valueWithoutQuotes)) {
 					rp.wrapCallExprWithInterpolatedT(basicLit, callExpr, i)
+					return
 				} else {
 					rp.wrapExprArgs(callExpr.Args)
 				}
@@ -533,26 +830,93 @@ func (rp *rewritePackage) wrapMultiArgsCallExpr(callExpr *ast.CallExpr) {
 	}
 }
 
+// wrapCallExprWithInterpolatedT converts the fmt format at argIndex to a templated string and replaces it and
+// the args it formats with a T() call, any args not used by the format are kept after the T() call
 func (rp *rewritePackage) wrapCallExprWithInterpolatedT(basicLit *ast.BasicLit, callExpr *ast.CallExpr, argIndex int) {
 	valueWithoutQuotes, _ := strconv.Unquote(basicLit.Value)
+	pos, end := callExpr.Pos(), callExpr.End()
 
-	i18nStringInfo, ok := rp.ExtractedStrings[valueWithoutQuotes]
-	if !ok && rp.ExtractedStrings != nil {
+	i18nStringInfo, ok := rp.extractedString(valueWithoutQuotes)
+	if (!ok && rp.ExtractedStrings != nil) || rp.untranslatableLits[basicLit] {
 		rp.wrapExprArgs(callExpr.Args)
 		return
 	}
 
-	templatedString := common.ConvertToTemplatedString(valueWithoutQuotes)
-	basicLit.Value = strconv.Quote(templatedString)
+	formatArgs := callExpr.Args[argIndex+1:]
+	argsCount := common.PrintfArgsCount(valueWithoutQuotes)
+
+	templatedString, argIndexes := common.ConvertPrintfToTemplatedString(valueWithoutQuotes)
+
+	// the fmt.Errorf(...) calls wrapping errors are rewritten with i18n.Errorf(...), their message is not a format
+	var wrappedArgIndexes []int
+	if rp.isErrorfCallExpr(callExpr) && argsCount == len(formatArgs) {
+		if errorfString, errorfArgIndexes, errorfWrappedArgIndexes := common.ConvertErrorfToTemplatedString(valueWithoutQuotes); len(errorfWrappedArgIndexes) > 0 {
+			templatedString, argIndexes, wrappedArgIndexes = errorfString, errorfArgIndexes, errorfWrappedArgIndexes
+		}
+	}
+
+	id, ok := rp.extractedTextIDs[valueWithoutQuotes]
+	if !ok {
+		id = rp.keyGenerator.Key(rp.packageName, templatedString)
+	}
+	basicLit.Value = strconv.Quote(id)
 
 	if rp.ExtractedStrings != nil {
-		rp.updateExtractedStrings(i18nStringInfo, templatedString)
+		rp.updateExtractedStrings(i18nStringInfo, id, templatedString)
 	}
 
-	rp.wrapCallExprWithTemplatedT(basicLit, callExpr, argIndex)
+	keyValueExprs := []ast.Expr{}
+	for _, index := range argIndexes {
+		if index >= len(formatArgs) {
+			continue
+		}
+
+		if wrappedIndex := slices.Index(wrappedArgIndexes, index); wrappedIndex >= 0 {
+			keyValueExprs = append(keyValueExprs, rp.wrappedArgKeyValueExpr("Arg"+strconv.Itoa(index), formatArgs[index], wrappedIndex))
+			continue
+		}
+
+		keyValueExprs = append(keyValueExprs, rp.templatedArgKeyValueExpr("Arg"+strconv.Itoa(index), formatArgs[index]))
+	}
+
+	rp.TotalStrings++
+	tCallExpr := newTemplatedTCallExpr(basicLit, keyValueExprs)
+
+	if len(wrappedArgIndexes) > 0 {
+		rp.wrapErrorfCallExpr(callExpr, tCallExpr, formatArgs, wrappedArgIndexes)
+		rp.edits = append(rp.edits, common.SourceEdit{Pos: pos, End: end, Node: callExpr})
+		return
+	}
+
+	newArgs := append([]ast.Expr{}, callExpr.Args[:argIndex]...)
+	newArgs = append(newArgs, tCallExpr)
+
+	if argsCount < len(formatArgs) {
+		rp.wrapExprArgs(formatArgs[argsCount:])
+		newArgs = append(newArgs, formatArgs[argsCount:]...)
+	}
+
+	callExpr.Args = newArgs
+	rp.edits = append(rp.edits, common.SourceEdit{Pos: pos, End: end, Node: callExpr})
+}
+
+// wrapErrorfCallExpr replaces a fmt.Errorf(...) call wrapping errors with %w by an i18n.Errorf(T(...), errs...)
+// call, so that the wrapped errors can still be inspected with errors.Is and errors.As
+func (rp *rewritePackage) wrapErrorfCallExpr(callExpr *ast.CallExpr, tCallExpr *ast.CallExpr, formatArgs []ast.Expr, wrappedArgIndexes []int) {
+	newArgs := []ast.Expr{tCallExpr}
+	for _, index := range wrappedArgIndexes {
+		newArgs = append(newArgs, formatArgs[index])
+	}
+
+	callExpr.Fun = &ast.SelectorExpr{X: &ast.Ident{Name: rp.i18nQualifier}, Sel: &ast.Ident{Name: "Errorf"}}
+	callExpr.Args = newArgs
+
+	rp.i18nImportNeeded = true
 }
 
 func (rp *rewritePackage) wrapCallExprWithTemplatedT(basicLit *ast.BasicLit, callExpr *ast.CallExpr, argIndex int) {
+	pos, end := callExpr.Pos(), callExpr.End()
+
 	templatedCallExpr := rp.wrapBasicLitWithTemplatedT(basicLit, callExpr.Args, callExpr, argIndex)
 	if templatedCallExpr != callExpr {
 		newArgs := []ast.Expr{}
@@ -566,6 +930,7 @@ func (rp *rewritePackage) wrapCallExprWithTemplatedT(basicLit *ast.BasicLit, cal
 		}
 
 		callExpr.Args = append(newArgs, templatedCallExpr)
+		rp.edits = append(rp.edits, common.SourceEdit{Pos: pos, End: end, Node: callExpr})
 	}
 }
 
@@ -582,67 +947,147 @@ func (rp *rewritePackage) wrapExprArgs(exprArgs []ast.Expr) {
 func (rp *rewritePackage) wrapBasicLitWithTemplatedT(basicLit *ast.BasicLit, args []ast.Expr, callExpr *ast.CallExpr, argIndex int) ast.Expr {
 	valueWithoutQuotes, _ := strconv.Unquote(basicLit.Value) //basicLit.Value[1 : len(basicLit.Value)-1]
 
-	_, ok := rp.ExtractedStrings[valueWithoutQuotes]
-	if !ok && rp.ExtractedStrings != nil {
+	_, ok := rp.extractedString(valueWithoutQuotes)
+	if (!ok && rp.ExtractedStrings != nil) || rp.untranslatableLits[basicLit] {
 		return callExpr
 	}
 
 	rp.TotalStrings++
-	tIdent := &ast.Ident{Name: "T"}
 	argNames := common.GetTemplatedStringArgs(valueWithoutQuotes)
+	rp.setStringID(basicLit, valueWithoutQuotes)
 
 	compositeExpr := []ast.Expr{}
 	processedArgsMap := make(map[string]bool)
 
 	for i, argName := range argNames {
-		if callExpr, ok := args[argIndex+i+1].(*ast.CallExpr); ok {
-			rp.callExprTFunc(callExpr)
-		} else if basicLit, ok := args[argIndex+i+1].(*ast.BasicLit); ok {
-			args[argIndex+i] = rp.wrapBasicLitWithT(basicLit)
+		if argIndex+i+1 >= len(args) {
+			break
 		}
 
 		if processedArgsMap[argName] != true {
-			quotedArgName := "\"" + argName + "\""
-			basicLit.ValuePos = 0
-
-			valueExpr := args[argIndex+i+1]
-			if basicLit, ok := args[argIndex+i+1].(*ast.BasicLit); ok {
-				valueExpr = rp.wrapBasicLitWithT(basicLit)
-			}
-
-			keyValueExpr := &ast.KeyValueExpr{Key: &ast.BasicLit{Kind: 9, Value: quotedArgName}, Value: valueExpr}
 			processedArgsMap[argName] = true
-			compositeExpr = append(compositeExpr, keyValueExpr)
+			compositeExpr = append(compositeExpr, rp.templatedArgKeyValueExpr(argName, args[argIndex+i+1]))
 		}
 	}
 
-	mapInterfaceType := &ast.InterfaceType{Interface: 142, Methods: &ast.FieldList{List: nil, Opening: 1, Closing: 2}, Incomplete: false}
-	mapType := &ast.MapType{Map: 131, Key: &ast.Ident{Name: "string"}, Value: mapInterfaceType}
-	compositeLit := &ast.CompositeLit{Type: mapType, Elts: compositeExpr}
+	return newTemplatedTCallExpr(basicLit, compositeExpr)
+}
+
+func (rp *rewritePackage) templatedArgKeyValueExpr(argName string, arg ast.Expr) ast.Expr {
+	valueExpr := arg
+	if callExpr, ok := arg.(*ast.CallExpr); ok {
+		rp.callExprTFunc(callExpr)
+	} else if basicLit, ok := arg.(*ast.BasicLit); ok {
+		valueExpr = rp.wrapBasicLitWithT(basicLit)
+	}
+
+	// the key is positioned at the arg so that the comments before the arg are printed before the key
+	return &ast.KeyValueExpr{Key: &ast.BasicLit{ValuePos: arg.Pos(), Kind: token.STRING, Value: strconv.Quote(argName)}, Colon: arg.Pos(), Value: valueExpr}
+}
+
+// wrappedArgKeyValueExpr returns the templated arg of an error wrapped by an i18n.Errorf(...) call, whose value is the
+// i18n.WrappedArg(...) placeholder of the error, since the error itself is passed to i18n.Errorf(...)
+func (rp *rewritePackage) wrappedArgKeyValueExpr(argName string, arg ast.Expr, wrappedIndex int) ast.Expr {
+	valueExpr := &ast.CallExpr{
+		Fun:  &ast.SelectorExpr{X: &ast.Ident{Name: rp.i18nQualifier}, Sel: &ast.Ident{Name: "WrappedArg"}},
+		Args: []ast.Expr{&ast.BasicLit{Kind: token.INT, Value: strconv.Itoa(wrappedIndex)}},
+	}
 
-	return &ast.CallExpr{Fun: tIdent, Args: []ast.Expr{basicLit, compositeLit}}
+	return &ast.KeyValueExpr{Key: &ast.BasicLit{ValuePos: arg.Pos(), Kind: token.STRING, Value: strconv.Quote(argName)}, Colon: arg.Pos(), Value: valueExpr}
 }
 
 func (rp *rewritePackage) wrapBasicLitWithT(basicLit *ast.BasicLit) ast.Expr {
-	if basicLit.Kind != token.STRING {
+	if basicLit.Kind != token.STRING || rp.untranslatableLits[basicLit] {
 		return basicLit
 	}
 
 	valueWithoutQuotes, _ := strconv.Unquote(basicLit.Value) //basicLit.Value[1 : len(basicLit.Value)-1]
-	_, ok := rp.ExtractedStrings[valueWithoutQuotes]
+	_, ok := rp.extractedString(valueWithoutQuotes)
 	if !ok && rp.ExtractedStrings != nil {
 		return basicLit
 	}
 
+	if typeName, ok := rp.stringLitType(basicLit); !ok {
+		rp.skipStringLit(basicLit, i18n.T("its type {{.Arg0}} is not string", map[string]interface{}{"Arg0": typeName}))
+		return basicLit
+	}
+
 	rp.TotalStrings++
+	rp.setStringID(basicLit, valueWithoutQuotes)
 	tIdent := &ast.Ident{Name: "T"}
-	return &ast.CallExpr{Fun: tIdent, Args: []ast.Expr{basicLit}}
+	tCallExpr := &ast.CallExpr{Fun: tIdent, Args: []ast.Expr{basicLit}}
+
+	rp.edits = append(rp.edits, common.SourceEdit{Pos: basicLit.Pos(), End: basicLit.End(), Node: tCallExpr})
+	return tCallExpr
+}
+
+// stringLitType returns the type of the string literal and false if it is not a string, e.g., a named string type,
+// so that T() which returns a string cannot be used, or true if its type is unknown
+func (rp *rewritePackage) stringLitType(basicLit *ast.BasicLit) (string, bool) {
+	if rp.typesInfo == nil {
+		return "", true
+	}
+
+	typeAndValue, ok := rp.typesInfo.Types[basicLit]
+	if !ok || typeAndValue.Type == nil {
+		return "", true
+	}
+
+	if basic, ok := typeAndValue.Type.(*types.Basic); ok && (basic.Kind() == types.String || basic.Kind() == types.UntypedString) {
+		return basic.String(), true
+	}
+
+	return typeAndValue.Type.String(), false
+}
+
+// typeCheckRewrittenFile returns an error if the rewritten file has type errors which the original file did not
+// have, the T func is declared for the type checking if the package does not declare it yet
+func (rp *rewritePackage) typeCheckRewrittenFile(fileName string, content []byte, pkg *types.Package, typeErrors []common.TypeError) error {
+	rewrittenFileSet := token.NewFileSet()
+	rewrittenFile, err := parser.ParseFile(rewrittenFileSet, fileName, content, parser.ParseComments)
+	if err != nil {
+		return common.NewParseError(err)
+	}
+
+	astFiles, err := common.ParsePackageFilesFS(rp.options.FileSystem(), rewrittenFileSet, fileName)
+	if err != nil {
+		return err
+	}
+	astFiles = append(astFiles, rewrittenFile)
+
+	if pkg == nil || pkg.Scope().Lookup("T") == nil {
+		tFuncFile, err := parser.ParseFile(rewrittenFileSet, "", "package "+rewrittenFile.Name.Name+"\n\nvar T func(string, ...interface{}) string\n", 0)
+		if err != nil {
+			return common.NewParseError(err)
+		}
+		astFiles = append(astFiles, tFuncFile)
+	}
+
+	_, _, rewrittenTypeErrors := rp.typeChecker.TypeCheckFiles(rewrittenFileSet, astFiles)
+
+	var newTypeErrors []string
+	for _, typeError := range common.NewTypeErrors(withoutTypeErrorLits(typeErrors), withoutTypeErrorLits(rewrittenTypeErrors)) {
+		// the i18n package imported for i18n.Errorf may not be found, e.g., outside of a module requiring it
+		if !strings.HasPrefix(typeError.Msg, "could not import "+I18N_PACKAGE_PATH) {
+			newTypeErrors = append(newTypeErrors, typeError.Msg)
+		}
+	}
+
+	if len(newTypeErrors) > 0 {
+		return common.NewParseError(errors.New(i18n.T("refusing to write {{.Arg0}} which no longer type checks: {{.Arg1}}", map[string]interface{}{"Arg0": fileName, "Arg1": strings.Join(newTypeErrors, "; ")})))
+	}
+
+	return nil
 }
 
 func (rp *rewritePackage) addInitFuncToPackage(packageName, outputDir, importPath string) error {
-	rp.Println(i18n.T("i18n4go: adding init func to package:"), packageName, i18n.T(" to output dir:"), outputDir)
+	initFileName := filepath.Join(outputDir, "i18n_init.go")
+	if !rp.options.RegenerateInitFlag && rp.fileExists(initFileName) {
+		rp.Println(i18n.T("i18n4go: keeping the existing init func file:"), initFileName)
+		return nil
+	}
 
-	common.CreateOutputDirsIfNeeded(outputDir)
+	rp.Println(i18n.T("i18n4go: adding init func to package:"), packageName, i18n.T(" to output dir:"), outputDir)
 
 	pieces := strings.Split(importPath, "/")
 	for index, str := range pieces {
@@ -652,15 +1097,15 @@ func (rp *rewritePackage) addInitFuncToPackage(packageName, outputDir, importPat
 	joinedImportPath := "filepath.Join(" + strings.Join(pieces, ", ") + ")"
 	content := rp.getInitFuncCodeSnippetContent(packageName, joinedImportPath)
 
-	return ioutil.WriteFile(filepath.Join(outputDir, "i18n_init.go"), []byte(content), 0666)
+	return rp.writeFile(initFileName, []byte(content), 0666)
 }
 
 func (rp *rewritePackage) getInitFuncCodeSnippetContent(packageName, importPath string) string {
 	snippetContent := INIT_CODE_SNIPPET
 	if rp.InitCodeSnippetFilename != "" {
-		bytes, err := ioutil.ReadFile(rp.InitCodeSnippetFilename)
+		bytes, err := rp.options.FileSystem().ReadFile(rp.InitCodeSnippetFilename)
 		if err != nil {
-			rp.Printf(i18n.T("i18n4go: error reading content of init code snippet file: {{.Arg0}}\n, using default", map[string]interface{}{"Arg0": rp.InitCodeSnippetFilename}))
+			rp.logger.Warnf(i18n.T("i18n4go: error reading content of init code snippet file: {{.Arg0}}\n, using default", map[string]interface{}{"Arg0": rp.InitCodeSnippetFilename}))
 		} else {
 			snippetContent = string(bytes)
 		}
@@ -671,43 +1116,337 @@ func (rp *rewritePackage) getInitFuncCodeSnippetContent(packageName, importPath
 	return content
 }
 
-func (rp *rewritePackage) saveASTFile(relativeFilePath, fileName string, astFile *ast.File, fileSet *token.FileSet) error {
-	var buffer bytes.Buffer
-	if err := format.Node(&buffer, fileSet, astFile); err != nil {
+func (rp *rewritePackage) saveRewrittenFile(relativeFilePath, fileName string, content []byte) error {
+	pathToFile := filepath.Join(rp.OutputDirname, relativeFilePath)
+	fileInfo, err := rp.options.FileSystem().Stat(fileName)
+	if err != nil {
 		return err
 	}
 
-	pathToFile := filepath.Join(rp.OutputDirname, relativeFilePath)
-	fileInfo, err := os.Stat(fileName)
+	rp.Println(i18n.T("saving file to path"), pathToFile)
+	rp.writeFile(pathToFile, content, fileInfo.Mode())
+
+	return nil
+}
+
+// saveExtractedStrings saves the updated i18n strings in the order of the i18n strings file, so that only the
+// converted strings are changed
+func (rp *rewritePackage) saveExtractedStrings() error {
+	var i18nStringInfos []common.I18nStringInfo
+	for _, id := range rp.extractedStringIDs {
+		if i18nStringInfo, ok := rp.UpdatedExtractedStrings[id]; ok {
+			i18nStringInfos = append(i18nStringInfos, i18nStringInfo)
+		}
+	}
+
+	if rp.options.DryRunFlag || len(i18nStringInfos) == 0 {
+		return nil
+	}
+
+	jsonData, err := common.MarshalI18nStringInfos(i18nStringInfos)
 	if err != nil {
 		return err
 	}
 
-	common.CreateOutputDirsIfNeeded(filepath.Dir(pathToFile))
+	return rp.writeFile(rp.I18nStringsFilename, jsonData, 0644)
+}
 
-	rp.Println(i18n.T("saving file to path"), pathToFile)
-	ioutil.WriteFile(pathToFile, buffer.Bytes(), fileInfo.Mode())
+// writeFile writes the content of a file, or keeps it to print the unified diff with its current content with the
+// diff option
+func (rp *rewritePackage) writeFile(fileName string, content []byte, perm os.FileMode) error {
+	if !rp.options.DiffFlag {
+		if !slices.Contains(rp.WrittenFiles, fileName) {
+			rp.WrittenFiles = append(rp.WrittenFiles, fileName)
+		}
+		common.CreateOutputDirsIfNeededFS(rp.options.FileSystem(), filepath.Dir(fileName))
+		return common.NewIOError(rp.options.FileSystem().WriteFile(fileName, content, perm))
+	}
+
+	fileName = filepath.Clean(fileName)
+	if _, ok := rp.diffFiles[fileName]; !ok {
+		rp.WrittenFiles = append(rp.WrittenFiles, fileName)
+		oldContent, err := rp.options.FileSystem().ReadFile(fileName)
+		if err != nil && !os.IsNotExist(err) {
+			return common.NewIOError(err)
+		}
+
+		rp.diffFiles[fileName] = &diffFile{oldContent: oldContent, exists: err == nil}
+		rp.diffFileNames = append(rp.diffFileNames, fileName)
+	}
+	rp.diffFiles[fileName].content = content
 
 	return nil
 }
 
+// fileExists returns true if the file exists, or is written with the diff option
+func (rp *rewritePackage) fileExists(fileName string) bool {
+	if _, ok := rp.diffFiles[filepath.Clean(fileName)]; ok {
+		return true
+	}
+
+	_, err := rp.options.FileSystem().Stat(fileName)
+	return err == nil
+}
+
+// loadI18nStringInfos loads the i18n strings of a file, including the updates kept with the diff option
+func (rp *rewritePackage) loadI18nStringInfos(fileName string) ([]common.I18nStringInfo, error) {
+	diffFile, ok := rp.diffFiles[filepath.Clean(fileName)]
+	if !ok {
+		return common.LoadI18nStringInfosFS(rp.options.FileSystem(), fileName)
+	}
+
+	var i18nStringInfos []common.I18nStringInfo
+	err := json.Unmarshal(diffFile.content, &i18nStringInfos)
+	if err != nil {
+		return nil, common.NewParseError(err)
+	}
+
+	return i18nStringInfos, nil
+}
+
+func (rp *rewritePackage) printDiff() {
+	fmt.Fprint(rp.Output, rp.Diff())
+}
+
+// Diff returns the unified diff of the files written with the diff option
+func (rp *rewritePackage) Diff() string {
+	var diff strings.Builder
+	for _, fileName := range rp.diffFileNames {
+		diffFile := rp.diffFiles[fileName]
+
+		oldName := rp.diffFileName(fileName)
+		if !diffFile.exists {
+			oldName = ""
+		}
+
+		diff.WriteString(common.UnifiedDiff(oldName, rp.diffFileName(fileName), diffFile.oldContent, diffFile.content))
+	}
+
+	return diff.String()
+}
+
+// diffFileName returns the path of a file relative to the root path, so that the diff can be applied from the root path
+func (rp *rewritePackage) diffFileName(fileName string) string {
+	absFilePath := common.AbsPath(rp.options.FileSystem(), fileName)
+	relativeFilePath, err := filepath.Rel(rp.RootPath, absFilePath)
+	if err != nil {
+		return filepath.ToSlash(fileName)
+	}
+
+	return filepath.ToSlash(relativeFilePath)
+}
+
 func (rp *rewritePackage) relativePathForFile(fileName string) string {
 	if rp.Dirname != "" {
+		if relativeFilePath, err := filepath.Rel(rp.Dirname, fileName); err == nil {
+			return relativeFilePath
+		}
 		return strings.Replace(fileName, rp.Dirname, "", -1)
 	} else {
 		return filepath.Base(fileName)
 	}
 }
 
-func (rp *rewritePackage) updateExtractedStrings(i18nStringInfo common.I18nStringInfo, templatedString string) {
+func (rp *rewritePackage) updateExtractedStrings(i18nStringInfo common.I18nStringInfo, id, translation string) {
 	oldID := i18nStringInfo.ID
 
-	i18nStringInfo.ID = templatedString
-	i18nStringInfo.Translation = templatedString
+	i18nStringInfo.ID = id
+	i18nStringInfo.Translation = translation
 
-	rp.ExtractedStrings[templatedString] = i18nStringInfo
-	rp.UpdatedExtractedStrings[templatedString] = i18nStringInfo
-	delete(rp.UpdatedExtractedStrings, oldID)
+	rp.ExtractedStrings[id] = i18nStringInfo
+	if id != translation {
+		rp.extractedTextIDs[translation] = id
+	}
+
+	if _, ok := rp.UpdatedExtractedStrings[id]; !ok {
+		if index := slices.Index(rp.extractedStringIDs, oldID); index >= 0 {
+			rp.extractedStringIDs[index] = id
+		} else {
+			rp.extractedStringIDs = append(rp.extractedStringIDs, id)
+		}
+	}
+	rp.UpdatedExtractedStrings[id] = i18nStringInfo
+	if oldID != id {
+		delete(rp.UpdatedExtractedStrings, oldID)
+	}
 
 	rp.SaveExtractedStrings = true
 }
+
+// extractedString returns the i18n string of a source text, whose ID is either the text itself or a key
+func (rp *rewritePackage) extractedString(text string) (common.I18nStringInfo, bool) {
+	if i18nStringInfo, ok := rp.ExtractedStrings[text]; ok {
+		return i18nStringInfo, true
+	}
+
+	if id, ok := rp.extractedTextIDs[text]; ok {
+		i18nStringInfo, ok := rp.ExtractedStrings[id]
+		return i18nStringInfo, ok
+	}
+
+	return common.I18nStringInfo{}, false
+}
+
+// setStringID replaces the source text of the literal of a T(...) call by its key, i.e., the ID of its key based
+// i18n string or a key generated with the key format, the i18n string of the text is then updated with the key
+func (rp *rewritePackage) setStringID(basicLit *ast.BasicLit, text string) {
+	id, ok := rp.extractedTextIDs[text]
+	if !ok {
+		id = rp.keyGenerator.Key(rp.packageName, text)
+	}
+
+	if id == text {
+		return
+	}
+	basicLit.Value = strconv.Quote(id)
+
+	if i18nStringInfo, ok := rp.ExtractedStrings[text]; ok && common.IsTextKey(i18nStringInfo) {
+		rp.updateExtractedStrings(i18nStringInfo, id, text)
+	}
+}
+
+// Private
+
+// withoutTypeErrorLits returns the type errors with the string literals of their messages elided, e.g.,
+// fmt.Printf(T("my_world")) as fmt.Printf(""), so that the errors of a file match once its literals are rewritten
+func withoutTypeErrorLits(typeErrors []common.TypeError) []common.TypeError {
+	var elidedErrors []common.TypeError
+	for _, typeError := range typeErrors {
+		typeError.Msg = typeErrorLitRegexp.ReplaceAllString(typeError.Msg, `""`)
+		elidedErrors = append(elidedErrors, typeError)
+	}
+
+	return elidedErrors
+}
+
+// isPrintfCallExpr returns true if the called function takes a fmt format, e.g., fmt.Sprintf, fmt.Errorf or log.Fatalf
+func isPrintfCallExpr(callExpr *ast.CallExpr) bool {
+	return slices.Contains(strings.Split(PRINTF_FUNC_NAMES, ","), callExprFuncName(callExpr))
+}
+
+// callExprFuncName returns the name of the called function or method without its qualifier, e.g., Printf
+func callExprFuncName(callExpr *ast.CallExpr) string {
+	switch fun := callExpr.Fun.(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		return fun.Sel.Name
+	}
+
+	return ""
+}
+
+// isErrorfCallExpr returns true for the fmt.Errorf(...) calls, fmt being imported under its name or an alias
+func (rp *rewritePackage) isErrorfCallExpr(callExpr *ast.CallExpr) bool {
+	selectorExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+	if !ok || rp.fmtQualifier == "" {
+		return false
+	}
+
+	ident, ok := selectorExpr.X.(*ast.Ident)
+	return ok && ident.Name == rp.fmtQualifier && selectorExpr.Sel.Name == "Errorf"
+}
+
+// i18nImportName returns the name of the i18n package and true if it is imported by the file, otherwise
+// the name to import it with, i.e., i18n or i18n4go if another imported package is named i18n
+func i18nImportName(astFile *ast.File) (string, bool) {
+	importNames := make(map[string]bool)
+	for _, importSpec := range astFile.Imports {
+		importPath, _ := strconv.Unquote(importSpec.Path.Value)
+
+		importName := path.Base(importPath)
+		if importSpec.Name != nil {
+			importName = importSpec.Name.Name
+		}
+
+		if importPath == I18N_PACKAGE_PATH && importName != "_" && importName != "." {
+			return importName, true
+		}
+		importNames[importName] = true
+	}
+
+	if importNames[path.Base(I18N_PACKAGE_PATH)] {
+		return "i18n4go", false
+	}
+
+	return path.Base(I18N_PACKAGE_PATH), false
+}
+
+// importDeclsForASTFile returns the import declarations of the file with their original positions
+func importDeclsForASTFile(astFile *ast.File) map[*ast.GenDecl][2]token.Pos {
+	importDecls := make(map[*ast.GenDecl][2]token.Pos)
+	for _, decl := range astFile.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+			importDecls[genDecl] = [2]token.Pos{genDecl.Pos(), genDecl.End()}
+		}
+	}
+
+	return importDecls
+}
+
+// importDeclsEdits returns the edits of the import declarations of the file once imports are added or deleted,
+// importDecls are the declarations before the changes, those added to a file without imports are inserted
+// after the package clause
+func importDeclsEdits(astFile *ast.File, importDecls map[*ast.GenDecl][2]token.Pos) []common.SourceEdit {
+	var edits []common.SourceEdit
+	for importDecl, positions := range importDecls {
+		edit := common.SourceEdit{Pos: positions[0], End: positions[1]}
+		if containsDecl(astFile.Decls, importDecl) {
+			edit.Node = importDecl
+		}
+		edits = append(edits, edit)
+	}
+
+	for _, decl := range astFile.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if _, exists := importDecls[genDecl]; ok && genDecl.Tok == token.IMPORT && !exists {
+			edits = append(edits, common.SourceEdit{Pos: astFile.Name.End(), End: astFile.Name.End(), Text: "\n\n" + importDeclText(genDecl)})
+		}
+	}
+
+	return edits
+}
+
+// importDeclText returns the source of an import declaration created by astutil.AddImport, whose positions
+// are not in the source
+func importDeclText(importDecl *ast.GenDecl) string {
+	var lines []string
+	for _, spec := range importDecl.Specs {
+		importSpec := spec.(*ast.ImportSpec)
+		if importSpec.Name != nil {
+			lines = append(lines, importSpec.Name.Name+" "+importSpec.Path.Value)
+		} else {
+			lines = append(lines, importSpec.Path.Value)
+		}
+	}
+
+	if len(lines) == 1 {
+		return "import " + lines[0]
+	}
+
+	return "import (\n\t" + strings.Join(lines, "\n\t") + "\n)"
+}
+
+func containsDecl(decls []ast.Decl, decl ast.Decl) bool {
+	for _, aDecl := range decls {
+		if aDecl == decl {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newTemplatedTCallExpr(basicLit *ast.BasicLit, keyValueExprs []ast.Expr) *ast.CallExpr {
+	if len(keyValueExprs) == 0 {
+		return &ast.CallExpr{Fun: &ast.Ident{Name: "T"}, Args: []ast.Expr{basicLit}}
+	}
+
+	// the map type is positioned at the string so that it is printed on the same line
+	pos := basicLit.Pos()
+	mapInterfaceType := &ast.InterfaceType{Interface: pos, Methods: &ast.FieldList{Opening: pos, Closing: pos}}
+	mapType := &ast.MapType{Map: pos, Key: &ast.Ident{Name: "string"}, Value: mapInterfaceType}
+	compositeLit := &ast.CompositeLit{Type: mapType, Elts: keyValueExprs}
+
+	return &ast.CallExpr{Fun: &ast.Ident{Name: "T"}, Args: []ast.Expr{basicLit, compositeLit}}
+}
@@ -17,8 +17,6 @@ package cmds
 import (
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -33,6 +31,8 @@ import (
 )
 
 type showMissingStrings struct {
+	commandLogger
+
 	options common.Options
 
 	I18nStringInfos     []common.I18nStringInfo
@@ -43,6 +43,7 @@ type showMissingStrings struct {
 
 func NewShowMissingStrings(options *common.Options) *showMissingStrings {
 	return &showMissingStrings{
+		commandLogger:       newCommandLogger(*options),
 		options:             *options,
 		Directory:           options.DirnameFlag,
 		I18nStringsFilename: options.I18nStringsFilenameFlag,
@@ -62,6 +63,8 @@ func NewShowMissingStringsCommand(options *common.Options) *cobra.Command {
 
 	showMissingStringsCmd.Flags().StringVarP(&options.DirnameFlag, "directory", "d", "", i18n.T("the directory containing the go files to validate"))
 	showMissingStringsCmd.Flags().StringVar(&options.I18nStringsFilenameFlag, "i18n-strings-filename", "", i18n.T("a JSON file with the strings that should be i18n enabled, typically the output of -extract-strings command"))
+	showMissingStringsCmd.Flags().IntVar(&options.JobsFlag, "jobs", 0, i18n.T("[optional] the number of files inspected concurrently, defaults to the number of CPUs"))
+	addSourceWalkerFlags(showMissingStringsCmd, options)
 
 	// TODO: setup options and params for Cobra command here using common.Options
 
@@ -72,22 +75,6 @@ func (sms *showMissingStrings) Options() common.Options {
 	return sms.options
 }
 
-func (sms *showMissingStrings) Println(a ...interface{}) (int, error) {
-	if sms.options.VerboseFlag {
-		return fmt.Println(a...)
-	}
-
-	return 0, nil
-}
-
-func (sms *showMissingStrings) Printf(msg string, a ...interface{}) (int, error) {
-	if sms.options.VerboseFlag {
-		return fmt.Printf(msg, a...)
-	}
-
-	return 0, nil
-}
-
 func (sms *showMissingStrings) Run() error {
 	return sms.showMissingStrings()
 }
@@ -96,7 +83,7 @@ func (sms *showMissingStrings) showMissingStrings() error {
 
 	//Load en_US.all.json
 
-	stringInfos, err := common.LoadI18nStringInfos(sms.I18nStringsFilename)
+	stringInfos, err := common.LoadI18nStringInfosFS(sms.options.FileSystem(), sms.I18nStringsFilename)
 	if err != nil {
 		return err
 	}
@@ -119,44 +106,58 @@ func (sms *showMissingStrings) showMissingStrings() error {
 }
 
 func (sms *showMissingStrings) parseFiles() error {
-	sourceFiles, _ := getFilesAndDir(sms.Directory)
-	for _, sourceFile := range sourceFiles {
-		err := sms.inspectFile(sourceFile)
-		if err != nil {
-			return err
-		}
+	walker, err := common.NewSourceWalker(sms.options)
+	if err != nil {
+		return err
+	}
+
+	sourceFiles := walker.GoFiles(sms.Directory, false)
+	filesTranslatedStrings, err := common.MapConcurrently(sourceFiles, common.Jobs(sms.options), sms.inspectFile)
+	if err != nil {
+		return err
+	}
+
+	for _, translatedStrings := range filesTranslatedStrings {
+		sms.TranslatedStrings = append(sms.TranslatedStrings, translatedStrings...)
 	}
 
 	return nil
 }
-func (sms *showMissingStrings) inspectFile(filename string) error {
+
+// inspectFile returns the strings translated by the T() calls of a file, prefixed by the file name
+func (sms *showMissingStrings) inspectFile(filename string) ([]string, error) {
 	fset := token.NewFileSet()
 
-	var absFilePath = filename
-	if !filepath.IsAbs(absFilePath) {
-		absFilePath = filepath.Join(os.Getenv("PWD"), absFilePath)
-	}
+	absFilePath := common.AbsPath(sms.options.FileSystem(), filename)
 
-	fileInfo, err := common.GetAbsFileInfo(absFilePath)
+	fileInfo, err := common.GetAbsFileInfoFS(sms.options.FileSystem(), absFilePath)
 	if err != nil {
 		sms.Println(err)
+		return nil, common.NewIOError(err)
 	}
 
 	if strings.HasPrefix(fileInfo.Name(), ".") || !strings.HasSuffix(fileInfo.Name(), ".go") {
-		sms.Println(i18n.T("WARNING ignoring file:"), absFilePath)
-		return nil
+		sms.logger.Warn(i18n.T("WARNING ignoring file:"), absFilePath)
+		return nil, nil
 	}
 
-	astFile, err := parser.ParseFile(fset, absFilePath, nil, parser.ParseComments|parser.AllErrors)
+	content, err := sms.options.FileSystem().ReadFile(absFilePath)
 	if err != nil {
 		sms.Println(err)
-		return err
+		return nil, common.NewIOError(err)
+	}
+
+	astFile, err := parser.ParseFile(fset, absFilePath, content, parser.ParseComments|parser.AllErrors)
+	if err != nil {
+		sms.Println(err)
+		return nil, common.NewParseError(err)
 	}
 
 	return sms.extractString(astFile, fset, filename)
 }
 
-func (sms *showMissingStrings) extractString(f *ast.File, fset *token.FileSet, filename string) error {
+func (sms *showMissingStrings) extractString(f *ast.File, fset *token.FileSet, filename string) ([]string, error) {
+	var translatedStrings []string
 	ast.Inspect(f, func(n ast.Node) bool {
 		switch x := n.(type) {
 		case *ast.CallExpr:
@@ -172,7 +173,7 @@ func (sms *showMissingStrings) extractString(f *ast.File, fset *token.FileSet, f
 						}
 
 						sms.Println(i18n.T("Adding to translated strings:"), translatedString)
-						sms.TranslatedStrings = append(sms.TranslatedStrings, filename+": "+translatedString)
+						translatedStrings = append(translatedStrings, filename+": "+translatedString)
 					}
 				}
 			default:
@@ -182,7 +183,7 @@ func (sms *showMissingStrings) extractString(f *ast.File, fset *token.FileSet, f
 		return true
 	})
 
-	return nil
+	return translatedStrings, nil
 }
 
 func (sms *showMissingStrings) showMissingTranslatedStrings() error {
@@ -195,7 +196,7 @@ func (sms *showMissingStrings) showMissingTranslatedStrings() error {
 	}
 
 	if missingStrings {
-		return errors.New(i18n.T("Missing Strings!"))
+		return common.NewValidationError(errors.New(i18n.T("Missing Strings!")))
 	}
 
 	return nil
@@ -228,7 +229,7 @@ func (sms *showMissingStrings) showExtraStrings() error {
 		}
 	}
 	if additionalStrings {
-		return errors.New(i18n.T("Additional Strings!"))
+		return common.NewValidationError(errors.New(i18n.T("Additional Strings!")))
 	}
 	return nil
 }
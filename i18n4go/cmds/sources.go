@@ -0,0 +1,30 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmds
+
+import (
+	"github.com/maximilien/i18n4go/i18n4go/common"
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+
+	"github.com/spf13/cobra"
+)
+
+// addSourceWalkerFlags adds the flags of the source walker to the commands which walk the go files of a source tree
+func addSourceWalkerFlags(cmd *cobra.Command, options *common.Options) {
+	cmd.Flags().StringVar(&options.GoosFlag, "goos", "", i18n.T("[optional] the GOOS of the build constraints of the go files, defaults to the host one"))
+	cmd.Flags().StringVar(&options.GoarchFlag, "goarch", "", i18n.T("[optional] the GOARCH of the build constraints of the go files, defaults to the host one"))
+	cmd.Flags().StringVar(&options.BuildTagsFlag, "tags", "", i18n.T("[optional] a comma separated list of the build tags of the build constraints of the go files"))
+	cmd.Flags().StringVar(&options.IncludeFlag, "include", "", i18n.T("[optional] a comma separated list of the sources skipped by default to include, one of: vendor, testdata, generated, gitignored"))
+}
@@ -0,0 +1,435 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmds
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/text/language"
+
+	"github.com/maximilien/i18n4go/i18n4go/common"
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+const (
+	TABLE_FORMAT = "table"
+	JSON_FORMAT  = "json"
+	HTML_FORMAT  = "html"
+)
+
+const STATS_HTML_TEMPLATE = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: right; }
+th:first-child, td:first-child, th:nth-child(2), td:nth-child(2) { text-align: left; }
+tr.total { font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+<tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr>
+{{range .Report.Packages}}<tr><td>{{.Package}}</td><td>{{.Locale}}</td><td>{{.Total}}</td><td>{{.Translated}}</td><td>{{.Untranslated}}</td><td>{{.Missing}}</td><td>{{.Extra}}</td><td>{{.Invalid}}</td><td>{{printf "%.1f%%" .Coverage}}</td></tr>
+{{end}}{{range .Report.Totals}}<tr class="total"><td>{{.Package}}</td><td>{{.Locale}}</td><td>{{.Total}}</td><td>{{.Translated}}</td><td>{{.Untranslated}}</td><td>{{.Missing}}</td><td>{{.Extra}}</td><td>{{.Invalid}}</td><td>{{printf "%.1f%%" .Coverage}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`
+
+// LocaleStats counts the keys of a locale in a package, i.e., a directory of translation files, compared to the source language
+type LocaleStats struct {
+	Package      string  `json:"package"`
+	Locale       string  `json:"locale"`
+	Total        int     `json:"total"`
+	Translated   int     `json:"translated"`
+	Untranslated int     `json:"untranslated"`
+	Missing      int     `json:"missing"`
+	Extra        int     `json:"extra"`
+	Invalid      int     `json:"invalid"`
+	Coverage     float64 `json:"coverage"`
+}
+
+type StatsReport struct {
+	Packages []LocaleStats `json:"packages"`
+	Totals   []LocaleStats `json:"totals"`
+}
+
+type stats struct {
+	commandLogger
+
+	options common.Options
+
+	Dirname        string
+	SourceLanguage string
+	Format         string
+	OutputDirname  string
+	IgnoreRegexp   *regexp.Regexp
+
+	validator *verifyStrings
+}
+
+func NewStats(options *common.Options) *stats {
+	commandLogger := newCommandLogger(*options)
+
+	format := options.FormatFlag
+	if format == "" {
+		format = TABLE_FORMAT
+	}
+
+	dirname := options.DirnameFlag
+	if dirname == "" {
+		dirname = "."
+	}
+
+	return &stats{options: *options,
+		commandLogger:  commandLogger,
+		Dirname:        dirname,
+		SourceLanguage: options.SourceLanguageFlag,
+		Format:         format,
+		OutputDirname:  options.OutputDirFlag,
+		IgnoreRegexp:   common.GetIgnoreRegexpWithLogger(commandLogger.logger, options.IgnoreRegexpFlag),
+		validator:      NewVerifyStrings(options),
+	}
+}
+
+// SetLogger sets the logger of the command and of its validator of the translations, nil to log nothing
+func (st *stats) SetLogger(logger *common.Logger) {
+	st.commandLogger.SetLogger(logger)
+	st.validator.SetLogger(logger)
+}
+
+// NewStatsCommand implements 'i18n4go stats' command
+func NewStatsCommand(options *common.Options) *cobra.Command {
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: i18n.T("Reports the translation coverage of each locale"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return NewStats(options).Run()
+		},
+	}
+
+	statsCmd.Flags().StringVarP(&options.DirnameFlag, "directory", "d", "", i18n.T("[optional] the directory containing the translation files, defaults to current directory"))
+	statsCmd.Flags().StringVarP(&options.SourceLanguageFlag, "source-language", "s", "en", i18n.T("the source language of the file, typically also part of the file name, e.g., \"en_US\""))
+	statsCmd.Flags().StringVar(&options.FormatFlag, "format", TABLE_FORMAT, i18n.T("[optional] the format of the report, one of: table, json, html"))
+	statsCmd.Flags().StringVarP(&options.OutputDirFlag, "output", "o", "", i18n.T("[optional] the output directory where the stats report file will be placed, defaults to printing the report"))
+	statsCmd.Flags().StringVar(&options.IgnoreRegexpFlag, "ignore-regexp", ".*test.*", i18n.T("a perl-style regular expression for files to ignore, e.g., \".*test.*\""))
+	statsCmd.Flags().StringVar(&options.SkipChecksFlag, "skip-checks", "", i18n.T("[optional] a comma separated list of translation checks to skip, one of: template-args, printf, template-syntax, markup, whitespace, newlines, punctuation, ansi, glossary"))
+	statsCmd.Flags().StringVar(&options.GlossaryFilenameFlag, "glossary", "", i18n.T("[optional] the glossary JSON file with the terms that must not be translated or must be translated with specific terms per locale"))
+	return statsCmd
+}
+
+func (st *stats) Options() common.Options {
+	return st.options
+}
+
+func (st *stats) Run() error {
+	if st.Format != TABLE_FORMAT && st.Format != JSON_FORMAT && st.Format != HTML_FORMAT {
+		return errors.New(i18n.T("i18n4go: unknown stats format: {{.Arg0}}", map[string]any{"Arg0": st.Format}))
+	}
+
+	err := st.validator.prepareChecks()
+	if err != nil {
+		return err
+	}
+
+	report, err := st.computeStats()
+	if err != nil {
+		return err
+	}
+
+	content, err := st.formatReport(report)
+	if err != nil {
+		return err
+	}
+
+	if st.OutputDirname == "" {
+		fmt.Print(string(content))
+		return nil
+	}
+
+	err = common.CreateOutputDirsIfNeededFS(st.options.FileSystem(), st.OutputDirname)
+	if err != nil {
+		st.Println(err)
+		return err
+	}
+
+	fileName := filepath.Join(st.OutputDirname, "stats."+st.fileExtension())
+	err = st.options.FileSystem().WriteFile(fileName, content, 0644)
+	if err != nil {
+		st.logger.Debug(i18n.T("i18n4go: Error writing the stats report file:"), fileName)
+		return common.NewIOError(err)
+	}
+
+	st.Println(i18n.T("i18n4go: generated stats report file: {{.Arg0}}", map[string]any{"Arg0": fileName}))
+	return nil
+}
+
+func (st *stats) computeStats() (StatsReport, error) {
+	report := StatsReport{Packages: []LocaleStats{}, Totals: []LocaleStats{}}
+
+	packages := make(map[string]map[string][]string)
+	for locale, fileNames := range findTranslationFiles(st.options.FileSystem(), st.Dirname, st.IgnoreRegexp) {
+		for _, fileName := range fileNames {
+			packageName, err := filepath.Rel(st.Dirname, filepath.Dir(fileName))
+			if err != nil {
+				packageName = filepath.Dir(fileName)
+			}
+
+			if packages[packageName] == nil {
+				packages[packageName] = make(map[string][]string)
+			}
+			packages[packageName][locale] = append(packages[packageName][locale], fileName)
+		}
+	}
+
+	if len(packages) == 0 {
+		return report, common.NewIOError(errors.New(i18n.T("i18n4go: could not find any translation files in: {{.Arg0}}", map[string]any{"Arg0": st.Dirname})))
+	}
+
+	totals := make(map[string]*LocaleStats)
+	for _, packageName := range sortedKeys(packages) {
+		locales := packages[packageName]
+
+		sourceStringInfos := make(map[string]common.I18nStringInfo)
+		source := sourceLocale(st.SourceLanguage, sortedKeys(locales))
+		if source != "" {
+			err := st.loadStringInfos(locales[source], sourceStringInfos)
+			if err != nil {
+				return report, err
+			}
+		}
+
+		for _, locale := range sortedKeys(locales) {
+			if locale == source {
+				continue
+			}
+
+			stringInfos := make(map[string]common.I18nStringInfo)
+			err := st.loadStringInfos(locales[locale], stringInfos)
+			if err != nil {
+				return report, err
+			}
+
+			localeStats := st.localeStats(packageName, locale, sourceStringInfos, stringInfos)
+			report.Packages = append(report.Packages, localeStats)
+
+			if totals[locale] == nil {
+				totals[locale] = &LocaleStats{Package: i18n.T("TOTAL"), Locale: locale}
+			}
+			totals[locale].Total += localeStats.Total
+			totals[locale].Translated += localeStats.Translated
+			totals[locale].Untranslated += localeStats.Untranslated
+			totals[locale].Missing += localeStats.Missing
+			totals[locale].Extra += localeStats.Extra
+			totals[locale].Invalid += localeStats.Invalid
+		}
+	}
+
+	for _, locale := range sortedKeys(totals) {
+		totals[locale].Coverage = coverage(totals[locale].Translated, totals[locale].Total)
+		report.Totals = append(report.Totals, *totals[locale])
+	}
+
+	return report, nil
+}
+
+func (st *stats) loadStringInfos(fileNames []string, stringInfos map[string]common.I18nStringInfo) error {
+	for _, fileName := range fileNames {
+		i18nStringInfos, err := common.LoadI18nStringInfosFS(st.options.FileSystem(), fileName)
+		if err != nil {
+			st.logger.Debug(i18n.T("i18n4go: Error loading the i18n strings from file:"), fileName)
+			return err
+		}
+
+		for _, stringInfo := range i18nStringInfos {
+			stringInfos[stringInfo.ID] = stringInfo
+		}
+	}
+
+	return nil
+}
+
+func (st *stats) localeStats(packageName string, locale string, sourceStringInfos map[string]common.I18nStringInfo, stringInfos map[string]common.I18nStringInfo) LocaleStats {
+	localeStats := LocaleStats{Package: packageName, Locale: locale, Total: len(sourceStringInfos)}
+
+	for id, sourceStringInfo := range sourceStringInfos {
+		stringInfo, ok := stringInfos[id]
+		if !ok {
+			localeStats.Missing++
+			continue
+		}
+
+		if stringInfo.Translation == "" || stringInfo.Translation == stringInfo.ID {
+			localeStats.Untranslated++
+			continue
+		}
+
+		localeStats.Translated++
+		if len(st.validator.validateTranslation(locale, sourceStringInfo, stringInfo)) > 0 {
+			localeStats.Invalid++
+		}
+	}
+
+	for id := range stringInfos {
+		if _, ok := sourceStringInfos[id]; !ok {
+			localeStats.Extra++
+		}
+	}
+
+	localeStats.Coverage = coverage(localeStats.Translated, localeStats.Total)
+	return localeStats
+}
+
+func (st *stats) formatReport(report StatsReport) ([]byte, error) {
+	switch st.Format {
+	case JSON_FORMAT:
+		content, err := json.MarshalIndent(report, "", "   ")
+		if err != nil {
+			return nil, err
+		}
+		return append(content, '\n'), nil
+	case HTML_FORMAT:
+		return st.formatHTMLReport(report)
+	default:
+		return st.formatTableReport(report), nil
+	}
+}
+
+func (st *stats) formatTableReport(report StatsReport) []byte {
+	var buffer bytes.Buffer
+	writer := tabwriter.NewWriter(&buffer, 0, 4, 2, ' ', 0)
+
+	for i, header := range statsHeaders() {
+		if i > 0 {
+			fmt.Fprint(writer, "\t")
+		}
+		fmt.Fprint(writer, header)
+	}
+	fmt.Fprintln(writer)
+
+	for _, localeStats := range append(report.Packages, report.Totals...) {
+		fmt.Fprintf(writer, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%.1f%%\n", localeStats.Package, localeStats.Locale,
+			localeStats.Total, localeStats.Translated, localeStats.Untranslated, localeStats.Missing, localeStats.Extra,
+			localeStats.Invalid, localeStats.Coverage)
+	}
+
+	writer.Flush()
+	return buffer.Bytes()
+}
+
+func (st *stats) formatHTMLReport(report StatsReport) ([]byte, error) {
+	htmlTemplate, err := template.New("stats").Parse(STATS_HTML_TEMPLATE)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	err = htmlTemplate.Execute(&buffer, map[string]any{
+		"Title":   i18n.T("Translation coverage"),
+		"Headers": statsHeaders(),
+		"Report":  report,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (st *stats) fileExtension() string {
+	if st.Format == TABLE_FORMAT {
+		return "txt"
+	}
+
+	return st.Format
+}
+
+// sourceLocale returns the locale of the source strings files among the sorted locales of a package, i.e., the
+// source language itself, or only when there is no such file a locale of its language, the one of its likely region
+// first, e.g., en_US for en, so that the other locales of the language, e.g., en_GB, are reported as translations
+func sourceLocale(sourceLanguage string, locales []string) string {
+	var languageLocales []string
+	for _, locale := range locales {
+		if strings.EqualFold(strings.Replace(locale, "-", "_", -1), strings.Replace(sourceLanguage, "-", "_", -1)) {
+			return locale
+		}
+
+		if common.MatchesLocale([]string{sourceLanguage}, locale) {
+			languageLocales = append(languageLocales, locale)
+		}
+	}
+
+	if len(languageLocales) == 0 {
+		return ""
+	}
+
+	tag := language.Make(sourceLanguage)
+	base, _ := tag.Base()
+	region, _ := tag.Region()
+	for _, locale := range languageLocales {
+		if strings.EqualFold(strings.Replace(locale, "-", "_", -1), base.String()+"_"+region.String()) {
+			return locale
+		}
+	}
+
+	return languageLocales[0]
+}
+
+func statsHeaders() []string {
+	return []string{
+		i18n.T("PACKAGE"),
+		i18n.T("LOCALE"),
+		i18n.T("TOTAL"),
+		i18n.T("TRANSLATED"),
+		i18n.T("UNTRANSLATED"),
+		i18n.T("MISSING"),
+		i18n.T("EXTRA"),
+		i18n.T("INVALID"),
+		i18n.T("COVERAGE"),
+	}
+}
+
+func coverage(translated int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(translated) * 100 / float64(total)
+}
+
+func sortedKeys[V any](aMap map[string]V) []string {
+	keys := make([]string, 0, len(aMap))
+	for key := range aMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
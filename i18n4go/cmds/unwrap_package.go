@@ -0,0 +1,817 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmds
+
+import (
+	"errors"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go/ast"
+	"go/parser"
+	"go/token"
+
+	"github.com/maximilien/i18n4go/i18n4go/common"
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+type unwrapPackage struct {
+	commandLogger
+
+	options common.Options
+
+	Filename        string
+	Dirname         string
+	Recurse         bool
+	OutputDirname   string
+	SourceLanguage  string
+	SourceDirname   string
+	ResourceDirname string
+	IgnoreRegexp    *regexp.Regexp
+
+	// SourceStrings are the source language translations by ID, the unwrapped T(...) calls are replaced by their
+	// translation, or by their ID if it is not translated
+	SourceStrings map[string]string
+
+	// TranslationFiles are the JSON files of each locale from which the unused IDs are removed
+	TranslationFiles map[string][]string
+
+	// UnwrappedIDs are the IDs of the unwrapped T(...) calls and KeptIDs those of the T(...) calls which
+	// cannot be unwrapped, the unwrapped IDs which are not used anymore are removed from the translation files
+	UnwrappedIDs map[string]bool
+	KeptIDs      map[string]bool
+
+	// InputFiles are the absolute paths of the unwrapped files
+	InputFiles map[string]bool
+
+	TotalStrings int
+	TotalFiles   int
+
+	tFuncNames []string
+
+	// walker skips the directories and files of the unwrapped source tree which are not built or ignored
+	walker *common.SourceWalker
+
+	// fileSet, src and astFile of the file being unwrapped
+	fileSet *token.FileSet
+	src     []byte
+	astFile *ast.File
+
+	// i18nQualifier is the name of the i18n package imported by the file being unwrapped, if any, to unwrap
+	// the i18n.Errorf(...) calls
+	i18nQualifier string
+
+	// edits of the source of the file being unwrapped, the fmt import is added when fmtImportNeeded
+	edits           []common.SourceEdit
+	fmtImportNeeded bool
+
+	// inlinedTCalls are the T(...) calls used as the format of a printf-like call, unwrapped with the call
+	inlinedTCalls map[*ast.CallExpr]bool
+}
+
+func NewUnwrapPackage(options *common.Options) *unwrapPackage {
+	commandLogger := newCommandLogger(*options)
+
+	tFuncNames := common.ParseStringList(options.TFuncNamesFlag, ",")
+	if len(tFuncNames) == 0 {
+		tFuncNames = common.ParseStringList(common.DEFAULT_T_FUNC_NAMES, ",")
+	}
+
+	sourceDirname := options.SourceDirFlag
+	if sourceDirname == "" {
+		sourceDirname = "."
+	}
+
+	resourceDirname := options.ResourceDirFlag
+	if resourceDirname == "" {
+		resourceDirname = "."
+	}
+
+	return &unwrapPackage{options: *options,
+		commandLogger:   commandLogger,
+		Filename:        options.FilenameFlag,
+		Dirname:         options.DirnameFlag,
+		Recurse:         options.RecurseFlag,
+		OutputDirname:   options.OutputDirFlag,
+		SourceLanguage:  options.SourceLanguageFlag,
+		SourceDirname:   sourceDirname,
+		ResourceDirname: resourceDirname,
+		IgnoreRegexp:    common.GetIgnoreRegexpWithLogger(commandLogger.logger, options.IgnoreRegexpFlag),
+
+		SourceStrings:    make(map[string]string),
+		TranslationFiles: make(map[string][]string),
+		UnwrappedIDs:     make(map[string]bool),
+		KeptIDs:          make(map[string]bool),
+		InputFiles:       make(map[string]bool),
+
+		tFuncNames: tFuncNames,
+	}
+}
+
+// NewUnwrapPackageCommand implements 'i18n4go unwrap-package' command
+func NewUnwrapPackageCommand(options *common.Options) *cobra.Command {
+	unwrapPackageCmd := &cobra.Command{
+		Use:   "unwrap-package",
+		Short: i18n.T("Unwrap the T(...) calls of go source files back to string literals"),
+		Long:  i18n.T("Replace the T(...) calls of go source files by their source language string or an equivalent fmt.Sprintf(...) call, the inverse of rewrite-package, and remove the IDs which are not used anymore from the translation files"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return NewUnwrapPackage(options).Run()
+		},
+	}
+
+	unwrapPackageCmd.Flags().BoolVarP(&options.RecurseFlag, "recursive", "r", false, i18n.T("recursively unwrap the go files of all the sub directories of dirName"))
+
+	unwrapPackageCmd.Flags().StringVarP(&options.FilenameFlag, "file", "f", "", i18n.T("the source go file to be unwrapped"))
+	unwrapPackageCmd.Flags().StringVarP(&options.DirnameFlag, "directory", "d", "", i18n.T("the dir name for which all .go files will be unwrapped"))
+	unwrapPackageCmd.Flags().StringVarP(&options.OutputDirFlag, "output", "o", "", i18n.T("[optional] output directory for the unwrapped files, the original files are overwritten if not specified"))
+	unwrapPackageCmd.Flags().StringVarP(&options.SourceLanguageFlag, "source-language", "s", "en", i18n.T("the source language of the file, typically also part of the file name, e.g., \"en_US\""))
+	unwrapPackageCmd.Flags().StringVar(&options.IgnoreRegexpFlag, "ignore-regexp", ".*test.*", i18n.T("a perl-style regular expression for files to ignore, e.g., \".*test.*\""))
+	unwrapPackageCmd.Flags().StringVarP(&options.QualifierFlag, "qualifier", "q", "", i18n.T("[optional] the qualifier string that is used when using the i18n.T(...) function, default to nothing but could be set to `i18n` so that all calls would be: i18n.T(...)"))
+	unwrapPackageCmd.Flags().StringVar(&options.TFuncNamesFlag, "t-func-names", common.DEFAULT_T_FUNC_NAMES, i18n.T("[optional] a comma separated list of the names of the functions translating strings, whose calls are unwrapped"))
+	unwrapPackageCmd.Flags().StringVar(&options.SourceDirFlag, "source", ".", i18n.T("[optional] the directory where the source go files are located, defaults to current directory"))
+	unwrapPackageCmd.Flags().StringVar(&options.ResourceDirFlag, "resource", ".", i18n.T("[optional] the directory where the translation files are located, defaults to current directory"))
+	addSourceWalkerFlags(unwrapPackageCmd, options)
+	return unwrapPackageCmd
+}
+
+func (up *unwrapPackage) Options() common.Options {
+	return up.options
+}
+
+func (up *unwrapPackage) Run() error {
+	if up.Filename == "" && up.Dirname == "" {
+		return errors.New(i18n.T("i18n4go: a go file or a directory to unwrap is required"))
+	}
+
+	walker, err := common.NewSourceWalker(up.options)
+	if err != nil {
+		return err
+	}
+	up.walker = walker
+
+	err = up.loadSourceStrings()
+	if err != nil {
+		return err
+	}
+
+	if up.Filename != "" {
+		err = up.processFilename(up.Filename)
+	} else {
+		err = up.processDir(up.Dirname, up.Recurse)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = up.removeUnusedIDs()
+	if err != nil {
+		return err
+	}
+
+	up.Println()
+	up.Println(i18n.T("Total files parsed:"), up.TotalFiles)
+	up.Println(i18n.T("Total unwrapped strings:"), up.TotalStrings)
+	return nil
+}
+
+func (up *unwrapPackage) loadSourceStrings() error {
+	up.TranslationFiles = findTranslationFiles(up.options.FileSystem(), up.ResourceDirname, up.IgnoreRegexp)
+
+	for _, locale := range sortedKeys(up.TranslationFiles) {
+		if !common.MatchesLocale([]string{up.SourceLanguage}, locale) {
+			continue
+		}
+
+		for _, fileName := range up.TranslationFiles[locale] {
+			up.Println(i18n.T("i18n4go: loading the source strings from file:"), fileName)
+			stringInfos, err := common.LoadI18nStringInfosFS(up.options.FileSystem(), fileName)
+			if err != nil {
+				up.logger.Debug(i18n.T("i18n4go: error loading the source strings:"), err.Error())
+				return err
+			}
+
+			for _, stringInfo := range stringInfos {
+				if _, ok := up.SourceStrings[stringInfo.ID]; !ok {
+					up.SourceStrings[stringInfo.ID] = stringInfo.Translation
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (up *unwrapPackage) processDir(dirName string, recursive bool) error {
+	up.Printf(i18n.T("i18n4go: unwrapping strings in dir {{.Arg0}}, recursive: {{.Arg1}}\n", map[string]any{"Arg0": dirName, "Arg1": recursive}))
+	up.Println()
+
+	fileInfos, err := up.options.FileSystem().ReadDir(dirName)
+	if err != nil {
+		return common.NewIOError(err)
+	}
+
+	for _, fileInfo := range fileInfos {
+		fileName := filepath.Join(dirName, fileInfo.Name())
+		if fileInfo.IsDir() {
+			if recursive && !up.walker.SkipDir(fileName) {
+				if err := up.processDir(fileName, recursive); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if up.skipFile(fileInfo.Name()) || up.walker.SkipFile(fileName) {
+			continue
+		}
+
+		if err := up.processFilename(fileName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// skipFile returns true for the files which are not unwrapped, i.e., the test files, the i18n init and resources
+// files generated by rewrite-package and the files matching the ignore regexp
+func (up *unwrapPackage) skipFile(fileName string) bool {
+	return fileName == "i18n_init.go" || fileName == "i18n_resources.go" ||
+		strings.HasPrefix(fileName, ".") ||
+		!strings.HasSuffix(fileName, ".go") || strings.HasSuffix(fileName, "_test.go") ||
+		(up.IgnoreRegexp != nil && up.IgnoreRegexp.MatchString(fileName))
+}
+
+func (up *unwrapPackage) processFilename(fileName string) error {
+	up.TotalFiles += 1
+	up.Println(i18n.T("i18n4go: unwrapping strings for source file:"), fileName)
+
+	up.InputFiles[common.AbsPath(up.options.FileSystem(), fileName)] = true
+
+	src, err := up.options.FileSystem().ReadFile(fileName)
+	if err != nil {
+		up.Println(err)
+		return common.NewIOError(err)
+	}
+
+	fileSet := token.NewFileSet()
+	astFile, err := parser.ParseFile(fileSet, fileName, src, parser.ParseComments|parser.AllErrors)
+	if err != nil {
+		up.Println(err)
+		return common.NewParseError(err)
+	}
+
+	up.fileSet, up.src, up.astFile = fileSet, src, astFile
+	up.i18nQualifier = ""
+	if importName, imported := i18nImportName(astFile); imported {
+		up.i18nQualifier = importName
+	}
+	up.edits = nil
+	up.fmtImportNeeded = false
+	up.inlinedTCalls = make(map[*ast.CallExpr]bool)
+
+	astutil.Apply(astFile, up.inspectCallExpr, up.unwrapCallExpr)
+
+	content := src
+	if len(up.edits) > 0 {
+		content, err = common.ApplySourceEdits(fileSet, src, astFile.Comments, up.edits)
+		if err != nil {
+			up.logger.Debug(i18n.T("i18n4go: error applying the edits to the source file:"), err.Error())
+			return err
+		}
+
+		content, err = up.fixImports(fileName, content)
+		if err != nil {
+			up.logger.Debug(i18n.T("i18n4go: error updating the imports of the source file:"), err.Error())
+			return err
+		}
+	} else if up.OutputDirname == "" {
+		return nil
+	}
+
+	outputFileName := up.outputFileName(fileName)
+	err = common.CreateOutputDirsIfNeededFS(up.options.FileSystem(), filepath.Dir(outputFileName))
+	if err != nil {
+		up.Println(err)
+		return err
+	}
+
+	err = up.options.FileSystem().WriteFile(outputFileName, content, 0644)
+	if err != nil {
+		up.logger.Debug(i18n.T("i18n4go: error saving the unwrapped file:"), err.Error())
+		return common.NewIOError(err)
+	}
+
+	up.Println(i18n.T("i18n4go: saved the unwrapped file:"), outputFileName)
+	return nil
+}
+
+// inspectCallExpr marks the T(...) call used as the format of a printf-like call, e.g., fmt.Printf(T(...)) or
+// i18n.Errorf(T(...), err), so that it is unwrapped with the call, e.g., fmt.Printf("Hello %v\n", name)
+// instead of fmt.Printf(fmt.Sprintf("Hello %v\n", name))
+func (up *unwrapPackage) inspectCallExpr(cursor *astutil.Cursor) bool {
+	callExpr, ok := cursor.Node().(*ast.CallExpr)
+	if !ok {
+		return true
+	}
+
+	tCallExpr, formatIndex, ok := up.formatTCallExpr(callExpr)
+	if !ok || callExpr.Ellipsis.IsValid() {
+		return true
+	}
+
+	// a T(...) call without args is unwrapped to a literal, which is already a format
+	if len(tCallExpr.Args) < 2 && !up.isI18nErrorfCallExpr(callExpr) {
+		return true
+	}
+
+	if _, _, err := up.printfFormat(callExpr, tCallExpr, formatIndex); err == nil {
+		up.inlinedTCalls[tCallExpr] = true
+	}
+
+	return true
+}
+
+func (up *unwrapPackage) unwrapCallExpr(cursor *astutil.Cursor) bool {
+	callExpr, ok := cursor.Node().(*ast.CallExpr)
+	if !ok {
+		return true
+	}
+
+	if tCallExpr, formatIndex, ok := up.formatTCallExpr(callExpr); ok && up.inlinedTCalls[tCallExpr] {
+		up.unwrapFormatCallExpr(callExpr, tCallExpr, formatIndex)
+	} else if common.IsTFuncCall(callExpr, up.options.QualifierFlag, up.tFuncNames) && !up.inlinedTCalls[callExpr] {
+		up.unwrapTCallExpr(callExpr)
+	}
+
+	return true
+}
+
+// unwrapTCallExpr replaces a T(...) call by its translation, or by a fmt.Sprintf(...) call formatting the args of
+// the translation, e.g., T("Hello {{.Arg0}}", map[string]interface{}{"Arg0": name}) by fmt.Sprintf("Hello %v", name)
+func (up *unwrapPackage) unwrapTCallExpr(tCallExpr *ast.CallExpr) {
+	id, translation, err := up.tCallTranslation(tCallExpr)
+	if err != nil {
+		up.warnKeptTCall(tCallExpr, err)
+		return
+	}
+
+	text, err := up.tCallExprText(tCallExpr, id, translation)
+	if err != nil {
+		up.KeptIDs[id] = true
+		up.warnKeptTCall(tCallExpr, err)
+		return
+	}
+
+	up.edits = append(up.edits, common.SourceEdit{Pos: tCallExpr.Pos(), End: tCallExpr.End(), Text: text})
+	up.UnwrappedIDs[id] = true
+	up.TotalStrings++
+}
+
+func (up *unwrapPackage) tCallExprText(tCallExpr *ast.CallExpr, id, translation string) (string, error) {
+	argExprs, err := tCallArgExprs(tCallExpr)
+	if err != nil {
+		return "", err
+	}
+
+	// the i18n.WrappedArg(...) placeholders are only replaced by the errors of an i18n.Errorf(...) call
+	if len(up.wrappedArgIndexes(argExprs)) > 0 {
+		return "", errors.New(i18n.T("the translation wraps more errors than the call"))
+	}
+
+	format, argNames, err := common.ConvertTemplatedToPrintfString(translation, true)
+	if err != nil {
+		return "", err
+	}
+
+	if len(argNames) == 0 {
+		// the ID literal is kept as is, e.g., a raw string, when it is the translation
+		if translation == id {
+			return tCallExpr.Args[0].(*ast.BasicLit).Value, nil
+		}
+		return strconv.Quote(translation), nil
+	}
+
+	argTexts := []string{strconv.Quote(format)}
+	for _, argName := range argNames {
+		argExpr, ok := argExprs[argName]
+		if !ok {
+			return "", errors.New(i18n.T("the arg {{.Arg0}} of the translation is missing", map[string]any{"Arg0": argName}))
+		}
+
+		argText, err := up.sourceText(argExpr)
+		if err != nil {
+			return "", err
+		}
+		argTexts = append(argTexts, argText)
+	}
+
+	up.fmtImportNeeded = true
+	return "fmt.Sprintf(" + strings.Join(argTexts, ", ") + ")", nil
+}
+
+// unwrapFormatCallExpr replaces the T(...) format of a printf-like call by the format of its translation and the
+// args of the translation, the i18n.Errorf(...) calls are replaced by fmt.Errorf(...) calls wrapping the errors
+func (up *unwrapPackage) unwrapFormatCallExpr(callExpr *ast.CallExpr, tCallExpr *ast.CallExpr, formatIndex int) {
+	id, _, _ := up.tCallTranslation(tCallExpr)
+
+	text, err := up.formatCallExprText(callExpr, tCallExpr, formatIndex)
+	if err != nil {
+		up.KeptIDs[id] = true
+		up.warnKeptTCall(tCallExpr, err)
+		return
+	}
+
+	up.edits = append(up.edits, common.SourceEdit{Pos: callExpr.Pos(), End: callExpr.End(), Text: text})
+	up.UnwrappedIDs[id] = true
+	up.TotalStrings++
+}
+
+func (up *unwrapPackage) formatCallExprText(callExpr *ast.CallExpr, tCallExpr *ast.CallExpr, formatIndex int) (string, error) {
+	format, formatArgs, err := up.printfFormat(callExpr, tCallExpr, formatIndex)
+	if err != nil {
+		return "", err
+	}
+
+	funText := "fmt.Errorf"
+	if up.isI18nErrorfCallExpr(callExpr) {
+		up.fmtImportNeeded = true
+	} else {
+		if funText, err = up.sourceText(callExpr.Fun); err != nil {
+			return "", err
+		}
+		formatArgs = append(formatArgs, callExpr.Args[formatIndex+1:]...)
+	}
+
+	var argTexts []string
+	for _, arg := range callExpr.Args[:formatIndex] {
+		argText, err := up.sourceText(arg)
+		if err != nil {
+			return "", err
+		}
+		argTexts = append(argTexts, argText)
+	}
+
+	argTexts = append(argTexts, strconv.Quote(format))
+	for _, arg := range formatArgs {
+		argText, err := up.sourceText(arg)
+		if err != nil {
+			return "", err
+		}
+		argTexts = append(argTexts, argText)
+	}
+
+	return funText + "(" + strings.Join(argTexts, ", ") + ")", nil
+}
+
+// printfFormat returns the format of the translation of the T(...) call used as the format of the printf-like
+// call and the args formatted by the format, i.e., the values of the args of the translation and the wrapped
+// errors of an i18n.Errorf(...) call
+func (up *unwrapPackage) printfFormat(callExpr *ast.CallExpr, tCallExpr *ast.CallExpr, formatIndex int) (string, []ast.Expr, error) {
+	_, translation, err := up.tCallTranslation(tCallExpr)
+	if err != nil {
+		return "", nil, err
+	}
+
+	argExprs, err := tCallArgExprs(tCallExpr)
+	if err != nil {
+		return "", nil, err
+	}
+
+	wrappedArgIndexes := up.wrappedArgIndexes(argExprs)
+	var wrappedArgNames []string
+	for argName := range wrappedArgIndexes {
+		wrappedArgNames = append(wrappedArgNames, argName)
+	}
+
+	// the message of an i18n.Errorf(...) call is not a format, unlike the T(...) format of the other calls
+	isErrorf := up.isI18nErrorfCallExpr(callExpr)
+	format, argNames, err := common.ConvertTemplatedToPrintfString(translation, isErrorf, wrappedArgNames...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var formatArgs []ast.Expr
+	for _, argName := range argNames {
+		wrappedIndex, ok := wrappedArgIndexes[argName]
+		if !ok {
+			argExpr, ok := argExprs[argName]
+			if !ok {
+				return "", nil, errors.New(i18n.T("the arg {{.Arg0}} of the translation is missing", map[string]any{"Arg0": argName}))
+			}
+			formatArgs = append(formatArgs, argExpr)
+			continue
+		}
+
+		if !isErrorf || formatIndex+1+wrappedIndex >= len(callExpr.Args) {
+			return "", nil, errors.New(i18n.T("the translation wraps more errors than the call"))
+		}
+		formatArgs = append(formatArgs, callExpr.Args[formatIndex+1+wrappedIndex])
+	}
+
+	return format, formatArgs, nil
+}
+
+// formatTCallExpr returns the T(...) call used as the format of a printf-like call and the index of the format
+func (up *unwrapPackage) formatTCallExpr(callExpr *ast.CallExpr) (*ast.CallExpr, int, bool) {
+	if !isPrintfCallExpr(callExpr) {
+		return nil, 0, false
+	}
+
+	formatIndex := 0
+	if slices.Contains(strings.Split(PRINTF_WRITER_FUNC_NAMES, ","), callExprFuncName(callExpr)) {
+		// the writer is the first arg, e.g., fmt.Fprintf(os.Stderr, T(...))
+		formatIndex = 1
+	}
+
+	if formatIndex >= len(callExpr.Args) {
+		return nil, 0, false
+	}
+
+	tCallExpr, ok := callExpr.Args[formatIndex].(*ast.CallExpr)
+	if !ok || !common.IsTFuncCall(tCallExpr, up.options.QualifierFlag, up.tFuncNames) {
+		return nil, 0, false
+	}
+
+	return tCallExpr, formatIndex, true
+}
+
+// wrappedArgIndexes returns the indexes of the errors wrapped by an i18n.Errorf(...) call by the name of their
+// i18n.WrappedArg(...) args, e.g., "Arg1": i18n.WrappedArg(0) for the first wrapped error
+func (up *unwrapPackage) wrappedArgIndexes(argExprs map[string]ast.Expr) map[string]int {
+	wrappedArgIndexes := make(map[string]int)
+	for argName, argExpr := range argExprs {
+		callExpr, ok := argExpr.(*ast.CallExpr)
+		if !ok || len(callExpr.Args) != 1 {
+			continue
+		}
+
+		selectorExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+		if !ok || selectorExpr.Sel.Name != "WrappedArg" {
+			continue
+		}
+
+		if ident, ok := selectorExpr.X.(*ast.Ident); !ok || up.i18nQualifier == "" || ident.Name != up.i18nQualifier {
+			continue
+		}
+
+		basicLit, ok := callExpr.Args[0].(*ast.BasicLit)
+		if !ok || basicLit.Kind != token.INT {
+			continue
+		}
+
+		if wrappedIndex, err := strconv.Atoi(basicLit.Value); err == nil {
+			wrappedArgIndexes[argName] = wrappedIndex
+		}
+	}
+
+	return wrappedArgIndexes
+}
+
+// isI18nErrorfCallExpr returns true for the i18n.Errorf(...) calls added by rewrite-package
+func (up *unwrapPackage) isI18nErrorfCallExpr(callExpr *ast.CallExpr) bool {
+	selectorExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+	if !ok || up.i18nQualifier == "" {
+		return false
+	}
+
+	ident, ok := selectorExpr.X.(*ast.Ident)
+	return ok && ident.Name == up.i18nQualifier && selectorExpr.Sel.Name == "Errorf"
+}
+
+// tCallTranslation returns the ID of a T(...) call and its source language translation, or the ID itself if the
+// ID is not translated
+func (up *unwrapPackage) tCallTranslation(tCallExpr *ast.CallExpr) (string, string, error) {
+	if len(tCallExpr.Args) == 0 {
+		return "", "", errors.New(i18n.T("the T(...) call has no translation ID"))
+	}
+
+	basicLit, ok := tCallExpr.Args[0].(*ast.BasicLit)
+	if !ok || basicLit.Kind != token.STRING {
+		return "", "", errors.New(i18n.T("the translation ID is not a string literal"))
+	}
+
+	id, err := strconv.Unquote(basicLit.Value)
+	if err != nil {
+		return "", "", err
+	}
+
+	translation, ok := up.SourceStrings[id]
+	if !ok || translation == "" {
+		translation = id
+	}
+
+	return id, translation, nil
+}
+
+func (up *unwrapPackage) sourceText(expr ast.Expr) (string, error) {
+	return common.SourceText(up.fileSet, up.src, up.astFile.Comments, up.edits, expr.Pos(), expr.End())
+}
+
+func (up *unwrapPackage) warnKeptTCall(tCallExpr *ast.CallExpr, err error) {
+	position := up.fileSet.Position(tCallExpr.Pos())
+	up.logger.Warn(i18n.T("i18n4go: WARNING keeping the T(...) call at {{.Arg0}}:", map[string]any{"Arg0": position.String()}), err.Error())
+}
+
+// fixImports adds the fmt import used by the unwrapped calls and deletes the i18n imports which are not used
+// anymore in the unwrapped source of a file
+func (up *unwrapPackage) fixImports(fileName string, content []byte) ([]byte, error) {
+	fileSet := token.NewFileSet()
+	astFile, err := parser.ParseFile(fileSet, fileName, content, parser.ParseComments)
+	if err != nil {
+		return nil, common.NewParseError(err)
+	}
+
+	importDecls := importDeclsForASTFile(astFile)
+
+	changed := false
+	for _, importSpec := range slices.Clone(astFile.Imports) {
+		importPath, _ := strconv.Unquote(importSpec.Path.Value)
+
+		importName := path.Base(importPath)
+		if importSpec.Name != nil {
+			importName = importSpec.Name.Name
+		}
+
+		if importName == "_" || importName == "." || astutil.UsesImport(astFile, importPath) {
+			continue
+		}
+
+		if importPath == I18N_PACKAGE_PATH || (up.options.QualifierFlag != "" && importName == up.options.QualifierFlag) {
+			if importSpec.Name != nil {
+				astutil.DeleteNamedImport(fileSet, astFile, importName, importPath)
+			} else {
+				astutil.DeleteImport(fileSet, astFile, importPath)
+			}
+			changed = true
+		}
+	}
+
+	// the unused imports are deleted first so that fmt replaces an i18n import declared alone
+	if up.fmtImportNeeded && astutil.AddImport(fileSet, astFile, "fmt") {
+		changed = true
+	}
+
+	if !changed {
+		return content, nil
+	}
+
+	return common.ApplySourceEdits(fileSet, content, astFile.Comments, importDeclsEdits(astFile, importDecls))
+}
+
+func (up *unwrapPackage) outputFileName(fileName string) string {
+	if up.OutputDirname == "" {
+		return fileName
+	}
+
+	relativeFilePath := filepath.Base(fileName)
+	if up.Dirname != "" {
+		if relativePath, err := filepath.Rel(up.Dirname, fileName); err == nil {
+			relativeFilePath = relativePath
+		}
+	}
+
+	return filepath.Join(up.OutputDirname, relativeFilePath)
+}
+
+// removeUnusedIDs removes the unwrapped IDs which are not used by the T(...) calls of the source files anymore
+// from the translation files of all locales
+func (up *unwrapPackage) removeUnusedIDs() error {
+	usedIDs, err := up.findUsedIDs()
+	if err != nil {
+		return err
+	}
+
+	unusedIDs := make(map[string]bool)
+	for id := range up.UnwrappedIDs {
+		if !usedIDs[id] && !up.KeptIDs[id] {
+			unusedIDs[id] = true
+		}
+	}
+
+	if len(unusedIDs) == 0 {
+		up.Println(i18n.T("i18n4go: no unused IDs to remove from the translation files"))
+		return nil
+	}
+
+	for _, locale := range sortedKeys(up.TranslationFiles) {
+		for _, fileName := range up.TranslationFiles[locale] {
+			stringInfos, err := common.LoadI18nStringInfosFS(up.options.FileSystem(), fileName)
+			if err != nil {
+				up.logger.Debug(i18n.T("i18n4go: error loading the translation file:"), fileName, err.Error())
+				return err
+			}
+
+			keptStringInfos := []common.I18nStringInfo{}
+			for _, stringInfo := range stringInfos {
+				if !unusedIDs[stringInfo.ID] {
+					keptStringInfos = append(keptStringInfos, stringInfo)
+				}
+			}
+
+			if len(keptStringInfos) == len(stringInfos) {
+				continue
+			}
+
+			content, err := common.MarshalI18nStringInfos(keptStringInfos)
+			if err != nil {
+				return err
+			}
+
+			err = up.options.FileSystem().WriteFile(fileName, content, 0644)
+			if err != nil {
+				up.logger.Debug(i18n.T("i18n4go: error saving the translation file:"), fileName, err.Error())
+				return common.NewIOError(err)
+			}
+
+			up.Printf(i18n.T("i18n4go: removed {{.Arg0}} unused IDs from the translation file {{.Arg1}}\n", map[string]any{"Arg0": len(stringInfos) - len(keptStringInfos), "Arg1": fileName}))
+		}
+	}
+
+	return nil
+}
+
+// findUsedIDs returns the IDs of the T(...) calls of the source files, the unwrapped files are not inspected
+// unless they are overwritten since their T(...) calls are replaced by the output files
+func (up *unwrapPackage) findUsedIDs() (map[string]bool, error) {
+	usedIDs := make(map[string]bool)
+
+	fileNames, err := getGoFiles(up.SourceDirname, up.options)
+	if err != nil {
+		return nil, err
+	}
+	for _, fileName := range fileNames {
+		if up.OutputDirname != "" && up.InputFiles[common.AbsPath(up.options.FileSystem(), fileName)] {
+			continue
+		}
+
+		ids, err := common.InspectFileWithLogger(up.logger, fileName, up.options)
+		if err != nil {
+			up.logger.Debug(i18n.T("Error when inspecting go file: "), fileName)
+			return nil, err
+		}
+
+		for _, id := range ids {
+			usedIDs[id] = true
+		}
+	}
+
+	return usedIDs, nil
+}
+
+// Private
+
+// tCallArgExprs returns the values of the args map of a T(...) call by name, e.g., name for
+// T("Hello {{.Name}}", map[string]interface{}{"Name": name})
+func tCallArgExprs(tCallExpr *ast.CallExpr) (map[string]ast.Expr, error) {
+	argExprs := make(map[string]ast.Expr)
+	if len(tCallExpr.Args) < 2 {
+		return argExprs, nil
+	}
+
+	notAMapErr := errors.New(i18n.T("the args of the T(...) call are not a map literal"))
+
+	compositeLit, ok := tCallExpr.Args[1].(*ast.CompositeLit)
+	if !ok || len(tCallExpr.Args) > 2 {
+		return nil, notAMapErr
+	}
+
+	for _, elt := range compositeLit.Elts {
+		keyValueExpr, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil, notAMapErr
+		}
+
+		key, ok := keyValueExpr.Key.(*ast.BasicLit)
+		if !ok || key.Kind != token.STRING {
+			return nil, notAMapErr
+		}
+
+		argName, err := strconv.Unquote(key.Value)
+		if err != nil {
+			return nil, err
+		}
+		argExprs[argName] = keyValueExpr.Value
+	}
+
+	return argExprs, nil
+}
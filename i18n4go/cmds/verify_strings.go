@@ -15,8 +15,11 @@
 package cmds
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/maximilien/i18n4go/i18n4go/common"
@@ -24,7 +27,48 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const (
+	TEMPLATE_ARGS_CHECK   = "template-args"
+	PRINTF_CHECK          = "printf"
+	TEMPLATE_SYNTAX_CHECK = "template-syntax"
+	MARKUP_CHECK          = "markup"
+	WHITESPACE_CHECK      = "whitespace"
+	NEWLINES_CHECK        = "newlines"
+	PUNCTUATION_CHECK     = "punctuation"
+	ANSI_CHECK            = "ansi"
+	GLOSSARY_CHECK        = "glossary"
+)
+
+type translationCheck struct {
+	Name  string
+	Check func(vs *verifyStrings, locale string, source string, translation string) []string
+}
+
+var translationChecks = []translationCheck{
+	{TEMPLATE_ARGS_CHECK, (*verifyStrings).templatedStringProblems},
+	{PRINTF_CHECK, (*verifyStrings).printfStringProblems},
+	{TEMPLATE_SYNTAX_CHECK, (*verifyStrings).templateSyntaxProblems},
+	{MARKUP_CHECK, (*verifyStrings).markupProblems},
+	{WHITESPACE_CHECK, (*verifyStrings).whitespaceProblems},
+	{NEWLINES_CHECK, (*verifyStrings).newlinesProblems},
+	{PUNCTUATION_CHECK, (*verifyStrings).punctuationProblems},
+	{ANSI_CHECK, (*verifyStrings).ansiProblems},
+	{GLOSSARY_CHECK, (*verifyStrings).glossaryProblems},
+}
+
+// VerifiedFile are the problems of a target translation file, i.e., the IDs of the source file missing from the target
+// file, the IDs of the target file not in the source file and the translations which fail the checks
+type VerifiedFile struct {
+	Filename           string
+	Locale             string
+	MissingIDs         []string
+	ExtraIDs           []string
+	InvalidStringInfos []common.InvalidI18nStringInfo
+}
+
 type verifyStrings struct {
+	commandLogger
+
 	options common.Options
 
 	InputFilename string
@@ -33,18 +77,29 @@ type verifyStrings struct {
 	SourceLanguage    string
 	LanguageFilenames []string
 	Languages         []string
+	SkipChecks        []string
+
+	GlossaryFilename string
+	Glossary         *common.Glossary
+
+	// VerifiedFiles are the verified target files, in the order they are verified
+	VerifiedFiles []VerifiedFile
 }
 
 func NewVerifyStrings(options *common.Options) *verifyStrings {
 	languageFilenames := common.ParseStringList(options.LanguageFilesFlag, ",")
 	languages := common.ParseStringList(options.LanguagesFlag, ",")
+	skipChecks := common.ParseStringList(options.SkipChecksFlag, ",")
 
 	return &verifyStrings{options: *options,
+		commandLogger:     newCommandLogger(*options),
 		InputFilename:     options.FilenameFlag,
 		OutputDirname:     options.OutputDirFlag,
 		LanguageFilenames: languageFilenames,
 		Languages:         languages,
 		SourceLanguage:    options.SourceLanguageFlag,
+		SkipChecks:        skipChecks,
+		GlossaryFilename:  options.GlossaryFilenameFlag,
 	}
 }
 
@@ -63,6 +118,8 @@ func NewVerifyStringsCommand(options *common.Options) *cobra.Command {
 	verifyStringsCmd.Flags().StringVar(&options.LanguageFilesFlag, "language-files", "", i18n.T(`a comma separated list of target files for different languages to compare,  e.g., \"en, en_US, fr_FR, es\"	                                                                  if not specified then the languages flag is used to find target files in same directory as source`))
 	verifyStringsCmd.Flags().StringVarP(&options.OutputDirFlag, "output", "o", "", i18n.T("the output directory where the missing translation keys will be placed"))
 	verifyStringsCmd.Flags().StringVarP(&options.FilenameFlag, "file", "f", "", i18n.T("the source translation file"))
+	verifyStringsCmd.Flags().StringVar(&options.SkipChecksFlag, "skip-checks", "", i18n.T("[optional] a comma separated list of translation checks to skip, one of: template-args, printf, template-syntax, markup, whitespace, newlines, punctuation, ansi, glossary"))
+	verifyStringsCmd.Flags().StringVar(&options.GlossaryFilenameFlag, "glossary", "", i18n.T("[optional] the glossary JSON file with the terms that must not be translated or must be translated with specific terms per locale"))
 	return verifyStringsCmd
 }
 
@@ -70,39 +127,36 @@ func (vs *verifyStrings) Options() common.Options {
 	return vs.options
 }
 
-func (vs *verifyStrings) Println(a ...interface{}) (int, error) {
-	if vs.options.VerboseFlag {
-		return fmt.Println(a...)
-	}
-
-	return 0, nil
-}
-
-func (vs *verifyStrings) Printf(msg string, a ...interface{}) (int, error) {
-	if vs.options.VerboseFlag {
-		return fmt.Printf(msg, a...)
+func (vs *verifyStrings) Run() error {
+	err := vs.prepareChecks()
+	if err != nil {
+		return err
 	}
 
-	return 0, nil
-}
-
-func (vs *verifyStrings) Run() error {
-	fileName, filePath, err := common.CheckFile(vs.InputFilename)
+	fileName, filePath, err := common.CheckFileFS(vs.options.FileSystem(), vs.InputFilename)
 	if err != nil {
-		vs.Println(i18n.T("i18n4go: Error checking input filename: "), vs.InputFilename)
+		vs.logger.Debug(i18n.T("i18n4go: Error checking input filename: "), vs.InputFilename)
 		return err
 	}
 
 	targetFilenames := vs.determineTargetFilenames(fileName, filePath)
 	vs.Println(i18n.T("targetFilenames:"), targetFilenames)
+	// the errors of all the target files are returned, with the category of the last one
+	var verifyErr error
+	var verifyErrMsgs []string
 	for _, targetFilename := range targetFilenames {
 		err = vs.verify(vs.InputFilename, targetFilename)
 		if err != nil {
-			vs.Println(i18n.T("i18n4go: Error verifying target filename: "), targetFilename)
+			verifyErr = err
+			verifyErrMsgs = append(verifyErrMsgs, i18n.T("i18n4go: Error verifying target filename {{.Arg0}}: {{.Arg1}}", map[string]any{"Arg0": targetFilename, "Arg1": err.Error()}))
 		}
 	}
 
-	return err
+	if verifyErr == nil {
+		return nil
+	}
+
+	return common.WithCategoryOf(verifyErr, errors.New(strings.Join(verifyErrMsgs, "\n")))
 }
 
 func (vs *verifyStrings) determineTargetFilenames(inputFilename string, inputFilePath string) []string {
@@ -121,108 +175,365 @@ func (vs *verifyStrings) determineTargetFilenames(inputFilename string, inputFil
 }
 
 func (vs *verifyStrings) verify(inputFilename string, targetFilename string) error {
-	common.CheckFile(targetFilename)
+	common.CheckFileFS(vs.options.FileSystem(), targetFilename)
 
-	inputI18nStringInfos, err := common.LoadI18nStringInfos(inputFilename)
+	inputI18nStringInfos, err := common.LoadI18nStringInfosFS(vs.options.FileSystem(), inputFilename)
 	if err != nil {
-		vs.Println(i18n.T("i18n4go: Error loading the i18n strings from input filename:"), inputFilename)
+		vs.logger.Debug(i18n.T("i18n4go: Error loading the i18n strings from input filename:"), inputFilename)
 		return err
 	}
 
 	if len(inputI18nStringInfos) == 0 {
-		return fmt.Errorf(i18n.T("i18n4go: Error input file: {{.Arg0}} is empty", map[string]interface{}{"Arg0": inputFilename}))
+		return common.NewValidationError(fmt.Errorf(i18n.T("i18n4go: Error input file: {{.Arg0}} is empty", map[string]interface{}{"Arg0": inputFilename})))
 	}
 
 	inputMap, err := common.CreateI18nStringInfoMap(inputI18nStringInfos)
 	if err != nil {
-		return fmt.Errorf(i18n.T("File has duplicated key: {{.Arg0}}\n{{.Arg1}}", map[string]interface{}{"Arg0": inputFilename, "Arg1": err}))
+		return common.NewValidationError(fmt.Errorf(i18n.T("File has duplicated key: {{.Arg0}}\n{{.Arg1}}", map[string]interface{}{"Arg0": inputFilename, "Arg1": err})))
 	}
 
-	targetI18nStringInfos, err := common.LoadI18nStringInfos(targetFilename)
+	targetI18nStringInfos, err := common.LoadI18nStringInfosFS(vs.options.FileSystem(), targetFilename)
 	if err != nil {
-		vs.Println(i18n.T("i18n4go: Error loading the i18n strings from target filename:"), targetFilename)
+		vs.logger.Debug(i18n.T("i18n4go: Error loading the i18n strings from target filename:"), targetFilename)
 		return err
 	}
 
-	var targetExtraStringInfos, targetInvalidStringInfos []common.I18nStringInfo
+	targetLocale := common.GetLocaleFromFilename(targetFilename)
+
+	var targetExtraStringInfos []common.I18nStringInfo
+	var targetInvalidStringInfos []common.InvalidI18nStringInfo
 	for _, stringInfo := range targetI18nStringInfos {
-		if _, ok := inputMap[stringInfo.ID]; ok {
-			if common.IsTemplatedString(stringInfo.ID) && vs.isTemplatedStringTranslationInvalid(stringInfo) {
-				vs.Println(i18n.T("i18n4go: WARNING target file has invalid templated translations with key ID: "), stringInfo.ID)
-				targetInvalidStringInfos = append(targetInvalidStringInfos, stringInfo)
+		if inputStringInfo, ok := inputMap[stringInfo.ID]; ok {
+			problems := vs.validateTranslation(targetLocale, inputStringInfo, stringInfo)
+			if len(problems) > 0 {
+				vs.logger.Warn(i18n.T("i18n4go: WARNING target file has invalid translations with key ID: "), stringInfo.ID)
+				targetInvalidStringInfos = append(targetInvalidStringInfos, common.InvalidI18nStringInfo{
+					ID:          stringInfo.ID,
+					Translation: stringInfo.Translation,
+					Problems:    problems,
+				})
 			}
 			delete(inputMap, stringInfo.ID)
 		} else {
-			vs.Println(i18n.T("i18n4go: WARNING target file has extra key with ID: "), stringInfo.ID)
+			vs.logger.Warn(i18n.T("i18n4go: WARNING target file has extra key with ID: "), stringInfo.ID)
 			targetExtraStringInfos = append(targetExtraStringInfos, stringInfo)
 		}
 	}
 
+	vs.VerifiedFiles = append(vs.VerifiedFiles, VerifiedFile{
+		Filename:           targetFilename,
+		Locale:             targetLocale,
+		MissingIDs:         sortedKeys(inputMap),
+		ExtraIDs:           keysForI18nStringInfos(targetExtraStringInfos),
+		InvalidStringInfos: targetInvalidStringInfos,
+	})
+
 	var verficationError error
 	if len(targetExtraStringInfos) > 0 {
-		vs.Println(i18n.T("i18n4go: WARNING target file contains total of extra keys:"), len(targetExtraStringInfos))
+		vs.logger.Warn(i18n.T("i18n4go: WARNING target file contains total of extra keys:"), len(targetExtraStringInfos))
 
 		diffFilename, err := vs.generateExtraKeysDiffFile(targetExtraStringInfos, targetFilename)
 		if err != nil {
-			vs.Println(i18n.T("i18n4go: ERROR could not create the diff file:"), err)
+			vs.logger.Debug(i18n.T("i18n4go: ERROR could not create the diff file:"), err)
 			return err
 		}
 		vs.Println(i18n.T("i18n4go: generated diff file:"), diffFilename)
-		verficationError = fmt.Errorf(i18n.T("i18n4go: target file has extra i18n strings with IDs: {{.Arg0}}", map[string]interface{}{"Arg0": strings.Join(keysForI18nStringInfos(targetExtraStringInfos), ",")}))
+		verficationError = common.NewValidationError(errors.New(i18n.T("i18n4go: target file has extra i18n strings with IDs: {{.Arg0}}", map[string]interface{}{"Arg0": strings.Join(keysForI18nStringInfos(targetExtraStringInfos), ",")})))
 	}
 
 	if len(targetInvalidStringInfos) > 0 {
-		vs.Println(i18n.T("i18n4go: WARNING target file contains total of invalid translations:"), len(targetInvalidStringInfos))
+		vs.logger.Warn(i18n.T("i18n4go: WARNING target file contains total of invalid translations:"), len(targetInvalidStringInfos))
 
 		diffFilename, err := vs.generateInvalidTranslationDiffFile(targetInvalidStringInfos, targetFilename)
 		if err != nil {
-			vs.Println(i18n.T("i18n4go: ERROR could not create the diff file:"), err)
+			vs.logger.Debug(i18n.T("i18n4go: ERROR could not create the diff file:"), err)
 			return err
 		}
 		vs.Println(i18n.T("i18n4go: generated diff file:"), diffFilename)
-		verficationError = fmt.Errorf(i18n.T("i18n4go: target file has invalid i18n strings with IDs: {{.Arg0}}", map[string]interface{}{"Arg0": strings.Join(keysForI18nStringInfos(targetInvalidStringInfos), ",")}))
+		verficationError = common.NewValidationError(errors.New(i18n.T("i18n4go: target file has invalid i18n strings with IDs: {{.Arg0}}", map[string]interface{}{"Arg0": strings.Join(keysForInvalidI18nStringInfos(targetInvalidStringInfos), ",")})))
 	}
 
 	if len(inputMap) > 0 {
-		vs.Println(i18n.T("i18n4go: ERROR input file does not match target file:"), targetFilename)
+		vs.logger.Debug(i18n.T("i18n4go: ERROR input file does not match target file:"), targetFilename)
 
 		diffFilename, err := vs.generateMissingKeysDiffFile(valuesForI18nStringInfoMap(inputMap), targetFilename)
 		if err != nil {
-			vs.Println(i18n.T("i18n4go: ERROR could not create the diff file:"), err)
+			vs.logger.Debug(i18n.T("i18n4go: ERROR could not create the diff file:"), err)
 			return err
 		}
 		vs.Println(i18n.T("i18n4go: generated diff file:"), diffFilename)
-		verficationError = fmt.Errorf(i18n.T("i18n4go: target file is missing i18n strings with IDs: {{.Arg0}}", map[string]interface{}{"Arg0": strings.Join(keysForI18nStringInfoMap(inputMap), ",")}))
+		verficationError = common.NewValidationError(errors.New(i18n.T("i18n4go: target file is missing i18n strings with IDs: {{.Arg0}}", map[string]interface{}{"Arg0": strings.Join(keysForI18nStringInfoMap(inputMap), ",")})))
 	}
 
 	return verficationError
 }
 
-func (vs *verifyStrings) isTemplatedStringTranslationInvalid(stringInfo common.I18nStringInfo) bool {
-	if !common.IsTemplatedString(stringInfo.ID) || !common.IsTemplatedString(stringInfo.Translation) {
-		return false
+func (vs *verifyStrings) validateTranslation(locale string, inputStringInfo common.I18nStringInfo, stringInfo common.I18nStringInfo) []string {
+	source := inputStringInfo.Translation
+	if source == "" {
+		source = inputStringInfo.ID
+	}
+
+	var problems []string
+	for _, translationCheck := range translationChecks {
+		if !vs.isCheckSkipped(translationCheck.Name) {
+			problems = append(problems, translationCheck.Check(vs, locale, source, stringInfo.Translation)...)
+		}
+	}
+
+	return problems
+}
+
+func (vs *verifyStrings) prepareChecks() error {
+	err := vs.checkSkipChecks()
+	if err != nil {
+		return err
+	}
+
+	if vs.GlossaryFilename != "" {
+		vs.Glossary, err = common.LoadGlossaryFS(vs.options.FileSystem(), vs.GlossaryFilename)
+		if err != nil {
+			vs.logger.Debug(i18n.T("i18n4go: Error loading the glossary file:"), vs.GlossaryFilename)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (vs *verifyStrings) checkSkipChecks() error {
+	for _, skipCheck := range vs.SkipChecks {
+		found := false
+		for _, translationCheck := range translationChecks {
+			if translationCheck.Name == skipCheck {
+				found = true
+			}
+		}
+
+		if !found {
+			return errors.New(i18n.T("i18n4go: unknown translation check: {{.Arg0}}", map[string]interface{}{"Arg0": skipCheck}))
+		}
+	}
+
+	return nil
+}
+
+func (vs *verifyStrings) isCheckSkipped(name string) bool {
+	for _, skipCheck := range vs.SkipChecks {
+		if skipCheck == name {
+			return true
+		}
 	}
 
-	translationArgs := common.GetTemplatedStringArgs(stringInfo.Translation)
-	argsMap := make(map[string]string)
+	return false
+}
+
+func (vs *verifyStrings) templatedStringProblems(locale string, source string, translation string) []string {
+	if !common.IsTemplatedString(source) || !common.IsTemplatedString(translation) {
+		return nil
+	}
+
+	translationArgs := common.GetTemplatedStringArgs(translation)
+	translationArgsMap := make(map[string]string)
 	for _, translationArg := range translationArgs {
-		argsMap[translationArg] = translationArg
+		translationArgsMap[translationArg] = translationArg
+	}
+
+	sourceArgs := common.GetTemplatedStringArgs(source)
+	sourceArgsMap := make(map[string]string)
+	for _, sourceArg := range sourceArgs {
+		sourceArgsMap[sourceArg] = sourceArg
 	}
 
-	var missingArgs []string
-	idArgs := common.GetTemplatedStringArgs(stringInfo.ID)
-	for _, idArg := range idArgs {
-		if _, ok := argsMap[idArg]; !ok {
-			missingArgs = append(missingArgs, idArg)
+	var missingArgs, extraArgs []string
+	for _, sourceArg := range sourceArgs {
+		if _, ok := translationArgsMap[sourceArg]; !ok {
+			missingArgs = append(missingArgs, sourceArg)
 		}
 	}
 
+	for _, translationArg := range translationArgs {
+		if _, ok := sourceArgsMap[translationArg]; !ok {
+			extraArgs = append(extraArgs, translationArg)
+		}
+	}
+
+	var problems []string
 	if len(missingArgs) > 0 {
 		vs.Println(i18n.T("i18n4go: templated string is invalid, missing args in translation:"), strings.Join(missingArgs, ","))
-		return true
+		problems = append(problems, i18n.T("missing template args: {{.Arg0}}", map[string]interface{}{"Arg0": strings.Join(missingArgs, ",")}))
 	}
 
-	return false
+	if len(extraArgs) > 0 {
+		vs.Println(i18n.T("i18n4go: templated string is invalid, extra args in translation:"), strings.Join(extraArgs, ","))
+		problems = append(problems, i18n.T("extra template args: {{.Arg0}}", map[string]interface{}{"Arg0": strings.Join(extraArgs, ",")}))
+	}
+
+	return problems
+}
+
+func (vs *verifyStrings) printfStringProblems(locale string, source string, translation string) []string {
+	sourceVerbs := common.ParsePrintfVerbs(source)
+	translationVerbs := common.ParsePrintfVerbs(translation)
+	if len(sourceVerbs) == 0 && len(translationVerbs) == 0 {
+		return nil
+	}
+
+	var problems []string
+	if len(sourceVerbs) != len(translationVerbs) {
+		vs.Println(i18n.T("i18n4go: interpolated string is invalid, verb count does not match:"), translation)
+		problems = append(problems, i18n.T("printf verb count mismatch: expected {{.Arg0}}, found {{.Arg1}}", map[string]interface{}{"Arg0": len(sourceVerbs), "Arg1": len(translationVerbs)}))
+	}
+
+	sourceArgsCount := common.PrintfArgsCount(source)
+	var extraVerbs []string
+	for _, translationVerb := range translationVerbs {
+		if translationVerb.ArgIndex >= sourceArgsCount {
+			extraVerbs = append(extraVerbs, translationVerb.Text)
+		}
+	}
+
+	if len(extraVerbs) > 0 {
+		vs.Println(i18n.T("i18n4go: interpolated string is invalid, extra args in translation:"), strings.Join(extraVerbs, ","))
+		problems = append(problems, i18n.T("extra printf args: {{.Arg0}}", map[string]interface{}{"Arg0": strings.Join(extraVerbs, ",")}))
+	}
+
+	sourceArgVerbs := printfArgVerbs(sourceVerbs)
+	translationArgVerbs := printfArgVerbs(translationVerbs)
+
+	var mismatchedVerbs []string
+	for argIndex, sourceVerb := range sourceArgVerbs {
+		translationVerb, ok := translationArgVerbs[argIndex]
+		if ok && translationVerb != sourceVerb {
+			mismatchedVerbs = append(mismatchedVerbs, fmt.Sprintf("%%[%d]%c/%%[%d]%c", argIndex+1, sourceVerb, argIndex+1, translationVerb))
+		}
+	}
+	sort.Strings(mismatchedVerbs)
+
+	if len(mismatchedVerbs) > 0 {
+		if printfVerbsSignature(sourceVerbs) == printfVerbsSignature(translationVerbs) {
+			vs.Println(i18n.T("i18n4go: interpolated string is invalid, verbs are out of order:"), translation)
+			problems = append(problems, i18n.T("printf verb order mismatch, use indexed verbs such as %[2]s to reorder args: {{.Arg0}}", map[string]interface{}{"Arg0": strings.Join(mismatchedVerbs, ",")}))
+		} else {
+			vs.Println(i18n.T("i18n4go: interpolated string is invalid, verb types do not match:"), translation)
+			problems = append(problems, i18n.T("printf verb type mismatch: {{.Arg0}}", map[string]interface{}{"Arg0": strings.Join(mismatchedVerbs, ",")}))
+		}
+	}
+
+	return problems
+}
+
+func (vs *verifyStrings) templateSyntaxProblems(locale string, source string, translation string) []string {
+	err := common.ValidateTemplate(translation)
+	if err == nil {
+		return nil
+	}
+
+	vs.Println(i18n.T("i18n4go: translation is not a valid template:"), err.Error())
+	return []string{i18n.T("invalid template: {{.Arg0}}", map[string]interface{}{"Arg0": err.Error()})}
+}
+
+func (vs *verifyStrings) markupProblems(locale string, source string, translation string) []string {
+	var problems []string
+
+	unbalancedTags := common.GetUnbalancedMarkupTags(translation)
+	if len(unbalancedTags) > 0 && len(common.GetUnbalancedMarkupTags(source)) == 0 {
+		vs.Println(i18n.T("i18n4go: translation has unbalanced markup tags:"), strings.Join(unbalancedTags, ","))
+		problems = append(problems, i18n.T("unbalanced markup tags: {{.Arg0}}", map[string]interface{}{"Arg0": strings.Join(unbalancedTags, ",")}))
+	}
+
+	sourceTags := common.GetMarkupTags(source)
+	translationTags := common.GetMarkupTags(translation)
+	if !common.SameStrings(sourceTags, translationTags) {
+		vs.Println(i18n.T("i18n4go: translation markup tags do not match source:"), translation)
+		problems = append(problems, i18n.T("markup tags mismatch: expected {{.Arg0}}, found {{.Arg1}}", map[string]interface{}{"Arg0": strings.Join(sourceTags, ""), "Arg1": strings.Join(translationTags, "")}))
+	}
+
+	return problems
+}
+
+func (vs *verifyStrings) whitespaceProblems(locale string, source string, translation string) []string {
+	var problems []string
+
+	sourceLeading, translationLeading := common.LeadingWhitespace(source), common.LeadingWhitespace(translation)
+	if sourceLeading != translationLeading {
+		vs.Println(i18n.T("i18n4go: translation leading whitespace does not match source:"), translation)
+		problems = append(problems, i18n.T("leading whitespace mismatch: expected {{.Arg0}}, found {{.Arg1}}", map[string]interface{}{"Arg0": strconv.Quote(sourceLeading), "Arg1": strconv.Quote(translationLeading)}))
+	}
+
+	sourceTrailing, translationTrailing := common.TrailingWhitespace(source), common.TrailingWhitespace(translation)
+	if sourceTrailing != translationTrailing {
+		vs.Println(i18n.T("i18n4go: translation trailing whitespace does not match source:"), translation)
+		problems = append(problems, i18n.T("trailing whitespace mismatch: expected {{.Arg0}}, found {{.Arg1}}", map[string]interface{}{"Arg0": strconv.Quote(sourceTrailing), "Arg1": strconv.Quote(translationTrailing)}))
+	}
+
+	return problems
+}
+
+func (vs *verifyStrings) newlinesProblems(locale string, source string, translation string) []string {
+	sourceNewlines, translationNewlines := strings.Count(source, "\n"), strings.Count(translation, "\n")
+	if sourceNewlines == translationNewlines {
+		return nil
+	}
+
+	vs.Println(i18n.T("i18n4go: translation newlines do not match source:"), translation)
+	return []string{i18n.T("newlines count mismatch: expected {{.Arg0}}, found {{.Arg1}}", map[string]interface{}{"Arg0": sourceNewlines, "Arg1": translationNewlines})}
+}
+
+func (vs *verifyStrings) punctuationProblems(locale string, source string, translation string) []string {
+	sourcePunctuation, translationPunctuation := common.TerminalPunctuation(source), common.TerminalPunctuation(translation)
+	if sourcePunctuation == translationPunctuation {
+		return nil
+	}
+
+	vs.Println(i18n.T("i18n4go: translation terminal punctuation does not match source:"), translation)
+	return []string{i18n.T("terminal punctuation mismatch: expected {{.Arg0}}, found {{.Arg1}}", map[string]interface{}{"Arg0": strconv.Quote(sourcePunctuation), "Arg1": strconv.Quote(translationPunctuation)})}
+}
+
+func (vs *verifyStrings) ansiProblems(locale string, source string, translation string) []string {
+	missingSequences := common.MissingStrings(common.GetANSISequences(source), common.GetANSISequences(translation))
+	if len(missingSequences) == 0 {
+		return nil
+	}
+
+	var quotedSequences []string
+	for _, sequence := range missingSequences {
+		quotedSequences = append(quotedSequences, strconv.Quote(sequence))
+	}
+
+	vs.Println(i18n.T("i18n4go: translation is missing ANSI escape sequences:"), strings.Join(quotedSequences, ","))
+	return []string{i18n.T("missing ANSI escape sequences: {{.Arg0}}", map[string]interface{}{"Arg0": strings.Join(quotedSequences, ",")})}
+}
+
+func (vs *verifyStrings) glossaryProblems(locale string, source string, translation string) []string {
+	if vs.Glossary == nil {
+		return nil
+	}
+
+	var problems []string
+	for _, violation := range vs.Glossary.Violations(locale, source, translation) {
+		vs.Println(i18n.T("i18n4go: translation violates the glossary:"), violation.Message)
+		problems = append(problems, violation.Message)
+	}
+
+	return problems
+}
+
+func printfArgVerbs(verbs []common.PrintfVerb) map[int]rune {
+	argVerbs := make(map[int]rune)
+	for _, verb := range verbs {
+		argVerbs[verb.ArgIndex] = verb.Verb
+	}
+	return argVerbs
+}
+
+func printfVerbsSignature(verbs []common.PrintfVerb) string {
+	var runes []string
+	for _, verb := range verbs {
+		runes = append(runes, string(verb.Verb))
+	}
+	sort.Strings(runes)
+	return strings.Join(runes, "")
 }
 
 func keysForI18nStringInfos(in18nStringInfos []common.I18nStringInfo) []string {
@@ -233,6 +544,14 @@ func keysForI18nStringInfos(in18nStringInfos []common.I18nStringInfo) []string {
 	return keys
 }
 
+func keysForInvalidI18nStringInfos(invalidStringInfos []common.InvalidI18nStringInfo) []string {
+	var keys []string
+	for _, stringInfo := range invalidStringInfos {
+		keys = append(keys, stringInfo.ID)
+	}
+	return keys
+}
+
 func keysForI18nStringInfoMap(inputMap map[string]common.I18nStringInfo) []string {
 	var keys []string
 	for k, _ := range inputMap {
@@ -250,14 +569,14 @@ func valuesForI18nStringInfoMap(inputMap map[string]common.I18nStringInfo) []com
 }
 
 func (vs *verifyStrings) generateMissingKeysDiffFile(missingStringInfos []common.I18nStringInfo, fileName string) (string, error) {
-	name, pathName, err := common.CheckFile(fileName)
+	name, pathName, err := common.CheckFileFS(vs.options.FileSystem(), fileName)
 	if err != nil {
 		return "", err
 	}
 
 	diffFilename := name + ".missing.diff.json"
 	if vs.OutputDirname != "" {
-		common.CreateOutputDirsIfNeeded(vs.OutputDirname)
+		common.CreateOutputDirsIfNeededFS(vs.options.FileSystem(), vs.OutputDirname)
 		diffFilename = filepath.Join(vs.OutputDirname, diffFilename)
 	} else {
 		diffFilename = filepath.Join(pathName, diffFilename)
@@ -267,14 +586,14 @@ func (vs *verifyStrings) generateMissingKeysDiffFile(missingStringInfos []common
 }
 
 func (vs *verifyStrings) generateExtraKeysDiffFile(extraStringInfos []common.I18nStringInfo, fileName string) (string, error) {
-	name, pathName, err := common.CheckFile(fileName)
+	name, pathName, err := common.CheckFileFS(vs.options.FileSystem(), fileName)
 	if err != nil {
 		return "", err
 	}
 
 	diffFilename := name + ".extra.diff.json"
 	if vs.OutputDirname != "" {
-		common.CreateOutputDirsIfNeeded(vs.OutputDirname)
+		common.CreateOutputDirsIfNeededFS(vs.options.FileSystem(), vs.OutputDirname)
 		diffFilename = filepath.Join(vs.OutputDirname, diffFilename)
 	} else {
 		diffFilename = filepath.Join(pathName, diffFilename)
@@ -283,19 +602,19 @@ func (vs *verifyStrings) generateExtraKeysDiffFile(extraStringInfos []common.I18
 	return diffFilename, common.SaveI18nStringInfos(vs, vs.Options(), extraStringInfos, diffFilename)
 }
 
-func (vs *verifyStrings) generateInvalidTranslationDiffFile(invalidStringInfos []common.I18nStringInfo, fileName string) (string, error) {
-	name, pathName, err := common.CheckFile(fileName)
+func (vs *verifyStrings) generateInvalidTranslationDiffFile(invalidStringInfos []common.InvalidI18nStringInfo, fileName string) (string, error) {
+	name, pathName, err := common.CheckFileFS(vs.options.FileSystem(), fileName)
 	if err != nil {
 		return "", err
 	}
 
 	diffFilename := name + ".invalid.diff.json"
 	if vs.OutputDirname != "" {
-		common.CreateOutputDirsIfNeeded(vs.OutputDirname)
+		common.CreateOutputDirsIfNeededFS(vs.options.FileSystem(), vs.OutputDirname)
 		diffFilename = filepath.Join(vs.OutputDirname, diffFilename)
 	} else {
 		diffFilename = filepath.Join(pathName, diffFilename)
 	}
 
-	return diffFilename, common.SaveI18nStringInfos(vs, vs.Options(), invalidStringInfos, diffFilename)
+	return diffFilename, common.SaveInvalidI18nStringInfos(vs, vs.Options(), invalidStringInfos, diffFilename)
 }
@@ -17,7 +17,9 @@ package common
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 
 	"go/ast"
 	"go/parser"
@@ -26,6 +28,42 @@ import (
 	"github.com/maximilien/i18n4go/i18n4go/i18n"
 )
 
+// DEFAULT_T_FUNC_NAMES are the names of the functions translating strings, e.g., T(...) or i18n.T(...)
+const DEFAULT_T_FUNC_NAMES = "T,t"
+
+// IsTFuncCall returns true if the call is a call of one of the funcNames, either unqualified or qualified with the
+// qualifier or i18n, e.g., T(...), i18n.T(...) or <qualifier>.T(...)
+func IsTFuncCall(callExpr *ast.CallExpr, qualifier string, funcNames []string) bool {
+	switch fun := callExpr.Fun.(type) {
+	case *ast.Ident:
+		return slices.Contains(funcNames, fun.Name)
+	case *ast.SelectorExpr:
+		if ident, ok := fun.X.(*ast.Ident); ok {
+			return (ident.Name == qualifier || ident.Name == "i18n") && slices.Contains(funcNames, fun.Sel.Name)
+		}
+	}
+
+	return false
+}
+
+// ImportName returns the name of the package imported by the file, or an empty string if it is not imported
+func ImportName(astFile *ast.File, importPath string) string {
+	for _, importSpec := range astFile.Imports {
+		path, _ := strconv.Unquote(importSpec.Path.Value)
+		if path != importPath {
+			continue
+		}
+
+		if importSpec.Name != nil {
+			return importSpec.Name.Name
+		}
+
+		return importPath[strings.LastIndex(importPath, "/")+1:]
+	}
+
+	return ""
+}
+
 func ImportsForASTFile(astFile *ast.File) (*ast.GenDecl, error) {
 	for _, declaration := range astFile.Decls {
 		decl, ok := declaration.(*ast.GenDecl)
@@ -42,12 +80,63 @@ func ImportsForASTFile(astFile *ast.File) (*ast.GenDecl, error) {
 }
 
 func InspectFile(file string, options Options) (translatedStrings []string, err error) {
+	return InspectFileWithLogger(optionsLogger(options), file, options)
+}
+
+// InspectFileWithLogger is like InspectFile but logs with logger
+func InspectFileWithLogger(logger *Logger, file string, options Options) (translatedStrings []string, err error) {
+	content, err := options.FileSystem().ReadFile(file)
+	if err != nil {
+		logger.Debug(err)
+		return nil, NewIOError(err)
+	}
+
+	return inspectSource(logger, file, content, options)
+}
+
+// InspectCachedFile returns the strings translated by the T() calls of a file like InspectFile, loaded from the cache
+// if the file did not change since it was last inspected
+func InspectCachedFile(cache *Cache, file string, options Options) (translatedStrings []string, err error) {
+	return InspectCachedFileWithLogger(optionsLogger(options), cache, file, options)
+}
+
+// InspectCachedFileWithLogger is like InspectCachedFile but logs with logger
+func InspectCachedFileWithLogger(logger *Logger, cache *Cache, file string, options Options) (translatedStrings []string, err error) {
+	if cache == nil {
+		return InspectFileWithLogger(logger, file, options)
+	}
+
+	content, err := options.FileSystem().ReadFile(file)
+	if err != nil {
+		logger.Debug(err)
+		return nil, NewIOError(err)
+	}
+
+	key := cache.Key(file, content)
+	if cache.Load(key, &translatedStrings) {
+		logger.Debug(i18n.T("i18n4go: using the cached strings of file:"), file)
+		return
+	}
+
+	translatedStrings, err = inspectSource(logger, file, content, options)
+	if err != nil {
+		return
+	}
+
+	if saveErr := cache.Save(key, translatedStrings); saveErr != nil {
+		logger.Warn(saveErr)
+	}
+
+	return
+}
+
+func inspectSource(logger *Logger, file string, src any, options Options) (translatedStrings []string, err error) {
 	defineAssignStmtMap := make(map[string][]ast.AssignStmt)
 	fset := token.NewFileSet()
-	astFile, err := parser.ParseFile(fset, file, nil, parser.AllErrors)
+	astFile, err := parser.ParseFile(fset, file, src, parser.AllErrors)
 	if err != nil {
-		Println(options, err)
-		return
+		logger.Debug(err)
+		return nil, NewParseError(err)
 	}
 
 	ast.Inspect(astFile, func(n ast.Node) bool {
@@ -71,25 +160,9 @@ func InspectFile(file string, options Options) (translatedStrings []string, err
 	return
 }
 func inspectCallExpr(translatedStrings []string, stmtMap map[string][]ast.AssignStmt, node *ast.CallExpr, qualifier string) []string {
-	switch node.Fun.(type) {
-	case *ast.Ident:
-		funName := node.Fun.(*ast.Ident).Name
-		// inspect any T() or t() method calls
-		if funName == "T" || funName == "t" {
-			translatedStrings = inspectTFunc(translatedStrings, stmtMap, *node)
-		}
-
-	case *ast.SelectorExpr:
-		expr := node.Fun.(*ast.SelectorExpr)
-		if ident, ok := expr.X.(*ast.Ident); ok {
-			funName := expr.Sel.Name
-			// inspect any <MODULE>.T() or <MODULE>.t() method calls (eg. i18n.T())
-			if (ident.Name == qualifier || ident.Name == "i18n") && (funName == "T" || funName == "t") {
-				translatedStrings = inspectTFunc(translatedStrings, stmtMap, *node)
-			}
-		}
-	default:
-		//Skip!
+	// inspect any T()/t() or <MODULE>.T()/<MODULE>.t() (eg. i18n.T()) method calls
+	if IsTFuncCall(node, qualifier, ParseStringList(DEFAULT_T_FUNC_NAMES, ",")) {
+		translatedStrings = inspectTFunc(translatedStrings, stmtMap, *node)
 	}
 
 	return translatedStrings
@@ -0,0 +1,123 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CACHE_VERSION is part of the hash of the configuration, so that the cached files are not reused when what is cached
+// changes
+const CACHE_VERSION = "1"
+
+// Cache saves what is extracted from the go files in a directory, keyed by the hash of their content and of the
+// configuration, so that only the files which changed are parsed again, a nil Cache is disabled. The cache is kept
+// on the OS file system whatever the file system of the commands, since it only depends on the file contents
+type Cache struct {
+	Dirname    string
+	ConfigHash string
+}
+
+// NewCache returns the cache of the directory for the configuration, e.g., the command and the contents of its
+// excluded strings file, or nil if the directory is empty
+func NewCache(dirname string, config ...string) *Cache {
+	if dirname == "" {
+		return nil
+	}
+
+	return &Cache{
+		Dirname:    dirname,
+		ConfigHash: hash([]byte(strings.Join(append([]string{CACHE_VERSION}, config...), "\x00"))),
+	}
+}
+
+// NewTCallsCache returns the cache of the strings translated by the T() calls of the go files, see InspectCachedFile
+func NewTCallsCache(options Options) *Cache {
+	return NewCache(options.CacheDirFlag, "t-calls", options.QualifierFlag)
+}
+
+// Key returns the key of a file with its content
+func (cache *Cache) Key(filename string, content []byte) string {
+	if cache == nil {
+		return ""
+	}
+
+	return hash([]byte(cache.ConfigHash + "\x00" + filename + "\x00" + hash(content)))
+}
+
+// Load loads the value cached with the key and returns true, or false if the key is not cached
+func (cache *Cache) Load(key string, value any) bool {
+	if cache == nil {
+		return false
+	}
+
+	content, err := ioutil.ReadFile(cache.filename(key))
+	if err != nil {
+		return false
+	}
+
+	return json.Unmarshal(content, value) == nil
+}
+
+// Save caches the value with the key, the file is renamed once written so that the files can be saved concurrently
+func (cache *Cache) Save(key string, value any) error {
+	if cache == nil {
+		return nil
+	}
+
+	content, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	filename := cache.filename(key)
+	err = os.MkdirAll(filepath.Dir(filename), 0755)
+	if err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(filename), key)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	return os.Rename(file.Name(), filename)
+}
+
+// Private
+
+func (cache *Cache) filename(key string) string {
+	return filepath.Join(cache.Dirname, key[:2], key+".json")
+}
+
+func hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -14,20 +14,20 @@
 
 package common
 
-import (
-	"fmt"
-)
-
 type Options struct {
 	CommandFlag string
 
 	HelpFlag     bool
 	LongHelpFlag bool
 
-	VerboseFlag bool
-	DryRunFlag  bool
-	PoFlag      bool
-	MetaFlag    bool
+	VerboseFlag   bool
+	QuietFlag     bool
+	LogLevelFlag  string
+	LogFormatFlag string
+
+	DryRunFlag bool
+	PoFlag     bool
+	MetaFlag   bool
 
 	SourceLanguageFlag        string
 	LanguagesFlag             string
@@ -47,19 +47,47 @@ type Options struct {
 
 	IgnoreRegexpFlag string
 
-	LanguageFilesFlag string
+	LanguageFilesFlag    string
+	SkipChecksFlag       string
+	GlossaryFilenameFlag string
+
+	FormatFlag string
 
 	I18nStringsFilenameFlag string
 	I18nStringsDirnameFlag  string
 
+	KeyFormatFlag   string
+	MigrateKeysFlag bool
+
 	RootPathFlag string
 
 	InitCodeSnippetFilenameFlag string
+	DiffFlag                    bool
+	RegenerateInitFlag          bool
 
-	QualifierFlag string
+	QualifierFlag  string
+	TFuncNamesFlag string
+
+	TemplateExtensionsFlag string
 
 	SourceDirFlag   string
 	ResourceDirFlag string
+
+	PackageNameFlag string
+
+	JobsFlag     int
+	CacheDirFlag string
+
+	GoosFlag      string
+	GoarchFlag    string
+	BuildTagsFlag string
+	IncludeFlag   string
+
+	// FS is the file system the commands read and write, the OS file system when nil
+	FS WritableFS
+
+	// Logger logs the messages of the commands, the logger of the log flags when nil
+	Logger *Logger
 }
 
 type I18nStringInfo struct {
@@ -67,7 +95,14 @@ type I18nStringInfo struct {
 	Translation string `json:"translation"`
 }
 
+type InvalidI18nStringInfo struct {
+	ID          string   `json:"id"`
+	Translation string   `json:"translation"`
+	Problems    []string `json:"problems"`
+}
+
 type StringInfo struct {
+	ID       string `json:"id,omitempty"`
 	Filename string `json:"filename"`
 	Value    string `json:"value"`
 	Offset   int    `json:"offset"`
@@ -75,6 +110,13 @@ type StringInfo struct {
 	Column   int    `json:"column"`
 }
 
+// FileStringInfos are the strings extracted from a source file by value, with the name of its package
+type FileStringInfos struct {
+	Filename    string
+	PackageName string
+	StringInfos map[string]StringInfo
+}
+
 type ExcludedStrings struct {
 	ExcludedStrings []string `json:"excludedStrings"`
 	ExcludedRegexps []string `json:"excludedRegexps"`
@@ -87,18 +129,12 @@ type PrinterInterface interface {
 
 var BLANKS = []string{", ", "\t", "\n", "\n\t", "\t\n"}
 
+// Println logs the message at the debug level with the logger of the options, or of their log flags
 func Println(options Options, a ...any) (int, error) {
-	if options.VerboseFlag {
-		return fmt.Println(a...)
-	}
-
-	return 0, nil
+	return optionsLogger(options).Println(a...)
 }
 
+// Printf logs the message at the debug level with the logger of the options, or of their log flags
 func Printf(options Options, msg string, a ...any) (int, error) {
-	if options.VerboseFlag {
-		return fmt.Printf(msg, a...)
-	}
-
-	return 0, nil
+	return optionsLogger(options).Printf(msg, a...)
 }
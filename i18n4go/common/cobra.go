@@ -0,0 +1,276 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	COBRA_PACKAGE_PATH = "github.com/spf13/cobra"
+	PFLAG_PACKAGE_PATH = "github.com/spf13/pflag"
+	FLAG_PACKAGE_PATH  = "flag"
+)
+
+// COBRA_NAME_FIELDS are the fields of the cobra types which are names or values of the command line, e.g., the Use
+// of a cobra.Command, and not human readable texts like its Short, Long, Example and Deprecated fields
+var COBRA_NAME_FIELDS = map[string]map[string]bool{
+	"Command": {
+		"Use":                    true,
+		"Aliases":                true,
+		"SuggestFor":             true,
+		"ValidArgs":              true,
+		"ArgAliases":             true,
+		"BashCompletionFunction": true,
+		"Annotations":            true,
+		"Version":                true,
+		"GroupID":                true,
+	},
+	"Group": {
+		"ID": true,
+	},
+}
+
+const FLAG_TYPES_REGEXP = `(?:Bool|BoolSlice|BytesBase64|BytesHex|Count|Duration|DurationSlice|Float32|Float32Slice|Float64|Float64Slice|` +
+	`Int|Int8|Int16|Int32|Int32Slice|Int64|Int64Slice|IntSlice|IP|IPMask|IPNet|IPSlice|String|StringArray|StringSlice|` +
+	`StringToInt|StringToInt64|StringToString|Text|Uint|Uint8|Uint16|Uint32|Uint64|UintSlice)`
+
+var (
+	// flagDefinitionRegexp matches the flag and pflag methods defining flags whose usage is their last arg, e.g.,
+	// StringVarP(&value, "name", "n", "default", "usage")
+	flagDefinitionRegexp = regexp.MustCompile(`^(?:` + FLAG_TYPES_REGEXP + `(Var)?P?|VarP?|VarPF)$`)
+
+	// flagFuncDefinitionRegexp matches the methods defining flags with a func, e.g., Func("name", "usage", fn)
+	flagFuncDefinitionRegexp = regexp.MustCompile(`^(?:Bool)?Func$`)
+
+	// flagNameRegexp matches the flag and pflag funcs and methods whose string args are flag or flag set names or
+	// values, e.g., NewFlagSet("name", flag.ExitOnError), Lookup("name") or Set("name", "value")
+	flagNameRegexp = regexp.MustCompile(`^(?:NewFlagSet|Lookup|ShorthandLookup|Changed|Set|SetAnnotation|Get` + FLAG_TYPES_REGEXP + `)$`)
+
+	// cobraFlagNameRegexp matches the cobra and pflag methods whose string args are flag names, except for the usage
+	// message of the *Deprecated methods, e.g., MarkDeprecated("name", "usage message"), their names are specific
+	// enough to be matched whatever their receiver in the files importing cobra, e.g., cmd.MarkFlagRequired("name")
+	cobraFlagNameRegexp = regexp.MustCompile(`^(?:Mark\w*|RegisterFlagCompletionFunc)$`)
+)
+
+// FLAG_SET_METHODS are the cobra.Command methods returning its flag sets, e.g., cmd.Flags()
+var FLAG_SET_METHODS = map[string]bool{
+	"Flags":             true,
+	"PersistentFlags":   true,
+	"LocalFlags":        true,
+	"InheritedFlags":    true,
+	"NonInheritedFlags": true,
+}
+
+// UntranslatableBasicLits returns the string literals of a file which must not be translated since they are part
+// of the command line of a cobra, pflag or flag based CLI, i.e., the Use and the other name fields of the
+// cobra.Command literals, and the names, shorthands and default values of the flags, but not their usage
+func UntranslatableBasicLits(astFile *ast.File) map[*ast.BasicLit]bool {
+	basicLits := make(map[*ast.BasicLit]bool)
+
+	cobraName := ImportName(astFile, COBRA_PACKAGE_PATH)
+	var flagNames []string
+	for _, importPath := range []string{PFLAG_PACKAGE_PATH, FLAG_PACKAGE_PATH} {
+		if flagName := ImportName(astFile, importPath); flagName != "" {
+			flagNames = append(flagNames, flagName)
+		}
+	}
+
+	if cobraName == "" && len(flagNames) == 0 {
+		return basicLits
+	}
+
+	flagSetNames := flagSetNames(astFile, flagNames)
+
+	ast.Inspect(astFile, func(node ast.Node) bool {
+		switch x := node.(type) {
+		case *ast.CompositeLit:
+			nameFields := cobraNameFields(x, cobraName)
+			for _, elt := range x.Elts {
+				keyValueExpr, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+
+				if key, ok := keyValueExpr.Key.(*ast.Ident); ok && nameFields[key.Name] {
+					addStringBasicLits(basicLits, keyValueExpr.Value)
+				}
+			}
+		case *ast.CallExpr:
+			selectorExpr, ok := x.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+
+			// the flag methods have common names, e.g., http.Header.Set, so their receiver must be a flag set
+			methodName := selectorExpr.Sel.Name
+			flagSet := isFlagSetExpr(selectorExpr.X, flagNames, flagSetNames)
+			switch {
+			case flagSet && flagFuncDefinitionRegexp.MatchString(methodName) && len(x.Args) == 3:
+				addStringBasicLits(basicLits, x.Args[0])
+			case flagSet && flagDefinitionRegexp.MatchString(methodName) && len(x.Args) >= 2:
+				for _, arg := range x.Args[:len(x.Args)-1] {
+					addStringBasicLits(basicLits, arg)
+				}
+			case flagSet && flagNameRegexp.MatchString(methodName),
+				(flagSet || cobraName != "") && cobraFlagNameRegexp.MatchString(methodName):
+				args := x.Args
+				if strings.HasSuffix(methodName, "Deprecated") && len(args) > 0 {
+					args = args[:len(args)-1]
+				}
+
+				for _, arg := range args {
+					addStringBasicLits(basicLits, arg)
+				}
+			}
+		}
+
+		return true
+	})
+
+	return basicLits
+}
+
+// Private
+
+func cobraNameFields(compositeLit *ast.CompositeLit, cobraName string) map[string]bool {
+	selectorExpr, ok := compositeLit.Type.(*ast.SelectorExpr)
+	if !ok || cobraName == "" {
+		return nil
+	}
+
+	if ident, ok := selectorExpr.X.(*ast.Ident); !ok || ident.Name != cobraName {
+		return nil
+	}
+
+	return COBRA_NAME_FIELDS[selectorExpr.Sel.Name]
+}
+
+// flagSetNames returns the names of the variables, parameters and fields of a file which are flag sets, i.e., declared
+// with the FlagSet type of the flag or pflag package, or assigned a new flag set or the flag set of a cobra.Command,
+// e.g., flagSet for flagSet := cmd.Flags()
+func flagSetNames(astFile *ast.File, flagNames []string) map[string]bool {
+	names := make(map[string]bool)
+	addNames := func(idents []*ast.Ident) {
+		for _, ident := range idents {
+			names[ident.Name] = true
+		}
+	}
+
+	ast.Inspect(astFile, func(node ast.Node) bool {
+		switch x := node.(type) {
+		case *ast.Field:
+			if isFlagSetType(x.Type, flagNames) {
+				addNames(x.Names)
+			}
+		case *ast.ValueSpec:
+			if isFlagSetType(x.Type, flagNames) {
+				addNames(x.Names)
+			}
+
+			for i, value := range x.Values {
+				if i < len(x.Names) && isFlagSetExpr(value, flagNames, names) {
+					names[x.Names[i].Name] = true
+				}
+			}
+		case *ast.AssignStmt:
+			if len(x.Lhs) != len(x.Rhs) {
+				return true
+			}
+
+			for i, rhs := range x.Rhs {
+				if !isFlagSetExpr(rhs, flagNames, names) {
+					continue
+				}
+
+				switch lhs := x.Lhs[i].(type) {
+				case *ast.Ident:
+					names[lhs.Name] = true
+				case *ast.SelectorExpr:
+					names[lhs.Sel.Name] = true
+				}
+			}
+		}
+
+		return true
+	})
+
+	return names
+}
+
+// isFlagSetType returns true if the type is a flag.FlagSet or a pflag.FlagSet, or a pointer to one
+func isFlagSetType(expr ast.Expr, flagNames []string) bool {
+	if starExpr, ok := expr.(*ast.StarExpr); ok {
+		expr = starExpr.X
+	}
+
+	selectorExpr, ok := expr.(*ast.SelectorExpr)
+	if !ok || selectorExpr.Sel.Name != "FlagSet" {
+		return false
+	}
+
+	ident, ok := selectorExpr.X.(*ast.Ident)
+	return ok && slices.Contains(flagNames, ident.Name)
+}
+
+// isFlagSetExpr returns true if the receiver of a method is a flag set, i.e., the flag or pflag package, their
+// CommandLine flag set, a new flag set, the flag set of a cobra.Command, e.g., cmd.Flags(), or a flag set variable,
+// parameter or field
+func isFlagSetExpr(expr ast.Expr, flagNames []string, flagSetNames map[string]bool) bool {
+	switch x := expr.(type) {
+	case *ast.ParenExpr:
+		return isFlagSetExpr(x.X, flagNames, flagSetNames)
+	case *ast.Ident:
+		return slices.Contains(flagNames, x.Name) || flagSetNames[x.Name]
+	case *ast.SelectorExpr:
+		if ident, ok := x.X.(*ast.Ident); ok && slices.Contains(flagNames, ident.Name) {
+			return x.Sel.Name == "CommandLine"
+		}
+		return flagSetNames[x.Sel.Name]
+	case *ast.CallExpr:
+		selectorExpr, ok := x.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+
+		if ident, ok := selectorExpr.X.(*ast.Ident); ok && slices.Contains(flagNames, ident.Name) {
+			return selectorExpr.Sel.Name == "NewFlagSet"
+		}
+		return FLAG_SET_METHODS[selectorExpr.Sel.Name]
+	}
+
+	return false
+}
+
+// addStringBasicLits adds the string literals of an expression, e.g., of a []string{...} or a map[string]string{...}
+// composite literal, but not of the func literals it may contain
+func addStringBasicLits(basicLits map[*ast.BasicLit]bool, expr ast.Expr) {
+	ast.Inspect(expr, func(node ast.Node) bool {
+		switch x := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BasicLit:
+			if x.Kind == token.STRING {
+				basicLits[x] = true
+			}
+		}
+
+		return true
+	})
+}
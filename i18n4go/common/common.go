@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"slices"
 
 	"io/ioutil"
 	"strconv"
@@ -41,6 +42,8 @@ const DEFAULT_IMPORT_QUALIFIER = "i18n"
 
 var templatedStringRegexp, interpolatedStringRegexp *regexp.Regexp
 
+var invalidLocaleRegexp = regexp.MustCompile("excluded|json|all|glossary")
+
 func ParseStringList(stringList string, delimiter string) []string {
 	stringArray := strings.Split(stringList, delimiter)
 	var parsedStrings []string
@@ -64,62 +67,72 @@ func CreateTmpFile(content string) (*os.File, error) {
 }
 
 func CheckFile(fileName string) (string, string, error) {
-	fileInfo, err := os.Stat(fileName)
+	return CheckFileFS(OsFS{}, fileName)
+}
+
+func CheckFileFS(fsys FS, fileName string) (string, string, error) {
+	fileInfo, err := fsys.Stat(fileName)
 	if err != nil {
-		return "", "", err
+		return "", "", NewIOError(err)
 	}
 
 	if !fileInfo.Mode().IsRegular() {
-		return "", "", fmt.Errorf(i18n.T("i18n4go: Non-regular source file {{.Arg0}} ({{.Arg1}})\n", map[string]interface{}{"Arg0": fileInfo.Name(), "Arg1": fileInfo.Mode().String()}))
+		return "", "", NewIOError(fmt.Errorf(i18n.T("i18n4go: Non-regular source file {{.Arg0}} ({{.Arg1}})\n", map[string]interface{}{"Arg0": fileInfo.Name(), "Arg1": fileInfo.Mode().String()})))
 	}
 
 	return filepath.Base(fileName), filepath.Dir(fileName), nil
 }
 
 func CopyFileContents(src, dst string) error {
-	err := CreateOutputDirsIfNeeded(filepath.Dir(dst))
+	return CopyFileContentsFS(OsFS{}, src, dst)
+}
+
+func CopyFileContentsFS(fsys WritableFS, src, dst string) error {
+	err := CreateOutputDirsIfNeededFS(fsys, filepath.Dir(dst))
 	if err != nil {
 		return err
 	}
 
-	byteArray, err := ioutil.ReadFile(src)
+	byteArray, err := fsys.ReadFile(src)
 	if err != nil {
-		return err
+		return NewIOError(err)
 	}
 
-	return ioutil.WriteFile(dst, byteArray, 0644)
+	return NewIOError(fsys.WriteFile(dst, byteArray, 0644))
 }
 
 func GetAbsFileInfo(fileNamePath string) (os.FileInfo, error) {
-	var absFilePath = fileNamePath
-	if !filepath.IsAbs(absFilePath) {
-		absFilePath = filepath.Join(os.Getenv("PWD"), absFilePath)
-	}
-
-	file, err := os.OpenFile(absFilePath, os.O_RDONLY, 0)
-	defer file.Close()
-	if err != nil {
-		return nil, err
-	}
+	return GetAbsFileInfoFS(OsFS{}, fileNamePath)
+}
 
-	return file.Stat()
+func GetAbsFileInfoFS(fsys FS, fileNamePath string) (os.FileInfo, error) {
+	fileInfo, err := fsys.Stat(AbsPath(fsys, fileNamePath))
+	return fileInfo, NewIOError(err)
 }
 
 func FindFilePath(filename string) (string, error) {
-	fileInfo, err := os.Stat(filename)
+	return FindFilePathFS(OsFS{}, filename)
+}
+
+func FindFilePathFS(fsys FS, filename string) (string, error) {
+	fileInfo, err := fsys.Stat(filename)
 	if err != nil {
-		return "", err
+		return "", NewIOError(err)
 	}
 	path := filename[0 : len(filename)-len(fileInfo.Name())]
 	return path, nil
 }
 
 func CreateOutputDirsIfNeeded(outputDirname string) error {
-	_, err := os.Stat(outputDirname)
+	return CreateOutputDirsIfNeededFS(OsFS{}, outputDirname)
+}
+
+func CreateOutputDirsIfNeededFS(fsys WritableFS, outputDirname string) error {
+	_, err := fsys.Stat(outputDirname)
 	if os.IsNotExist(err) {
-		err = os.MkdirAll(outputDirname, 0755)
+		err = fsys.MkdirAll(outputDirname, 0755)
 		if err != nil {
-			return err
+			return NewIOError(err)
 		}
 	}
 	return nil
@@ -132,20 +145,39 @@ func UnescapeHTML(byteArray []byte) []byte {
 	return byteArray
 }
 
+// SortedStringInfos returns the extracted strings in the order of their file and offset, and of their ID for the
+// strings at the same offset, so that the saved files do not depend on the order of the extraction
+func SortedStringInfos(stringInfos map[string]StringInfo) []StringInfo {
+	sortedStringInfos := make([]StringInfo, 0, len(stringInfos))
+	for _, stringInfo := range stringInfos {
+		sortedStringInfos = append(sortedStringInfos, stringInfo)
+	}
+
+	slices.SortFunc(sortedStringInfos, func(a, b StringInfo) int {
+		if a.Filename != b.Filename {
+			return strings.Compare(a.Filename, b.Filename)
+		}
+		if a.Offset != b.Offset {
+			return a.Offset - b.Offset
+		}
+		return strings.Compare(a.StringID(), b.StringID())
+	})
+
+	return sortedStringInfos
+}
+
 func SaveStrings(printer PrinterInterface, options Options, stringInfos map[string]StringInfo, outputDirname string, fileName string) error {
 	if !options.DryRunFlag {
-		err := CreateOutputDirsIfNeeded(outputDirname)
+		err := CreateOutputDirsIfNeededFS(options.FileSystem(), outputDirname)
 		if err != nil {
 			printer.Println(err)
 			return err
 		}
 	}
 
-	i18nStringInfos := make([]I18nStringInfo, len(stringInfos))
-	i := 0
-	for _, stringInfo := range stringInfos {
-		i18nStringInfos[i] = I18nStringInfo{ID: stringInfo.Value, Translation: stringInfo.Value}
-		i++
+	i18nStringInfos := make([]I18nStringInfo, 0, len(stringInfos))
+	for _, stringInfo := range SortedStringInfos(stringInfos) {
+		i18nStringInfos = append(i18nStringInfos, I18nStringInfo{ID: stringInfo.StringID(), Translation: stringInfo.Value})
 	}
 
 	jsonData, err := json.MarshalIndent(i18nStringInfos, "", "   ")
@@ -161,14 +193,11 @@ func SaveStrings(printer PrinterInterface, options Options, stringInfos map[stri
 	}
 
 	if !options.DryRunFlag && len(i18nStringInfos) != 0 {
-		file, err := os.Create(outputFilename)
-		defer file.Close()
+		err := options.FileSystem().WriteFile(outputFilename, jsonData, 0644)
 		if err != nil {
 			printer.Println(err)
-			return err
+			return NewIOError(err)
 		}
-
-		file.Write(jsonData)
 	}
 
 	return nil
@@ -180,27 +209,27 @@ func SaveStringsInPo(printer PrinterInterface, options Options, stringInfos map[
 	}
 
 	if !options.DryRunFlag && len(stringInfos) != 0 {
-		err := CreateOutputDirsIfNeeded(outputDirname)
-		if err != nil {
-			printer.Println(err)
-			return err
-		}
-
-		file, err := os.Create(filepath.Join(outputDirname, fileName[strings.LastIndex(fileName, string(os.PathSeparator))+1:len(fileName)]))
-		defer file.Close()
+		err := CreateOutputDirsIfNeededFS(options.FileSystem(), outputDirname)
 		if err != nil {
 			printer.Println(err)
 			return err
 		}
 
-		for _, stringInfo := range stringInfos {
-			file.Write([]byte("# filename: " + strings.Split(fileName, ".en.po")[0] +
+		var content bytes.Buffer
+		for _, stringInfo := range SortedStringInfos(stringInfos) {
+			content.WriteString("# filename: " + strings.Split(fileName, ".en.po")[0] +
 				", offset: " + strconv.Itoa(stringInfo.Offset) +
 				", line: " + strconv.Itoa(stringInfo.Line) +
-				", column: " + strconv.Itoa(stringInfo.Column) + "\n"))
-			file.Write([]byte("msgid " + strconv.Quote(stringInfo.Value) + "\n"))
-			file.Write([]byte("msgstr " + strconv.Quote(stringInfo.Value) + "\n"))
-			file.Write([]byte("\n"))
+				", column: " + strconv.Itoa(stringInfo.Column) + "\n")
+			content.WriteString("msgid " + strconv.Quote(stringInfo.StringID()) + "\n")
+			content.WriteString("msgstr " + strconv.Quote(stringInfo.Value) + "\n")
+			content.WriteString("\n")
+		}
+
+		err = options.FileSystem().WriteFile(filepath.Join(outputDirname, fileName[strings.LastIndex(fileName, string(os.PathSeparator))+1:len(fileName)]), content.Bytes(), 0644)
+		if err != nil {
+			printer.Println(err)
+			return NewIOError(err)
 		}
 	}
 	return nil
@@ -210,35 +239,63 @@ func SaveI18nStringsInPo(printer PrinterInterface, options Options, i18nStrings
 	printer.Println(i18n.T("i18n4go: creating and saving i18n strings to .po file:"), fileName)
 
 	if !options.DryRunFlag && len(i18nStrings) != 0 {
-		file, err := os.Create(fileName)
-		defer file.Close()
+		var content bytes.Buffer
+		for _, stringInfo := range i18nStrings {
+			content.WriteString("msgid " + strconv.Quote(stringInfo.ID) + "\n")
+			content.WriteString("msgstr " + strconv.Quote(stringInfo.Translation) + "\n")
+			content.WriteString("\n")
+		}
+
+		err := options.FileSystem().WriteFile(fileName, content.Bytes(), 0644)
 		if err != nil {
 			printer.Println(err)
-			return err
+			return NewIOError(err)
 		}
+	}
+	return nil
+}
 
-		for _, stringInfo := range i18nStrings {
-			file.Write([]byte("msgid " + strconv.Quote(stringInfo.ID) + "\n"))
-			file.Write([]byte("msgstr " + strconv.Quote(stringInfo.Translation) + "\n"))
-			file.Write([]byte("\n"))
+func SaveI18nStringInfos(printer PrinterInterface, options Options, i18nStringInfos []I18nStringInfo, fileName string) error {
+	jsonData, err := MarshalI18nStringInfos(i18nStringInfos)
+	if err != nil {
+		printer.Println(err)
+		return err
+	}
+
+	if !options.DryRunFlag && len(i18nStringInfos) != 0 {
+		err := options.FileSystem().WriteFile(fileName, jsonData, 0644)
+		if err != nil {
+			printer.Println(err)
+			return NewIOError(err)
 		}
 	}
+
 	return nil
 }
 
-func SaveI18nStringInfos(printer PrinterInterface, options Options, i18nStringInfos []I18nStringInfo, fileName string) error {
+// MarshalI18nStringInfos returns the JSON of the i18n strings as saved in the i18n strings files
+func MarshalI18nStringInfos(i18nStringInfos []I18nStringInfo) ([]byte, error) {
 	jsonData, err := json.MarshalIndent(i18nStringInfos, "", "   ")
+	if err != nil {
+		return nil, err
+	}
+
+	return UnescapeHTML(jsonData), nil
+}
+
+func SaveInvalidI18nStringInfos(printer PrinterInterface, options Options, invalidStringInfos []InvalidI18nStringInfo, fileName string) error {
+	jsonData, err := json.MarshalIndent(invalidStringInfos, "", "   ")
 	if err != nil {
 		printer.Println(err)
 		return err
 	}
 	jsonData = UnescapeHTML(jsonData)
 
-	if !options.DryRunFlag && len(i18nStringInfos) != 0 {
-		err := ioutil.WriteFile(fileName, jsonData, 0644)
+	if !options.DryRunFlag && len(invalidStringInfos) != 0 {
+		err := options.FileSystem().WriteFile(fileName, jsonData, 0644)
 		if err != nil {
 			printer.Println(err)
-			return err
+			return NewIOError(err)
 		}
 	}
 
@@ -246,20 +303,24 @@ func SaveI18nStringInfos(printer PrinterInterface, options Options, i18nStringIn
 }
 
 func LoadI18nStringInfos(fileName string) ([]I18nStringInfo, error) {
-	_, err := os.Stat(fileName)
+	return LoadI18nStringInfosFS(OsFS{}, fileName)
+}
+
+func LoadI18nStringInfosFS(fsys FS, fileName string) ([]I18nStringInfo, error) {
+	_, err := fsys.Stat(fileName)
 	if os.IsNotExist(err) {
-		return nil, err
+		return nil, NewIOError(err)
 	}
 
-	content, err := ioutil.ReadFile(fileName)
+	content, err := fsys.ReadFile(fileName)
 	if err != nil {
-		return nil, err
+		return nil, NewIOError(err)
 	}
 
 	var i18nStringInfos []I18nStringInfo
 	err = json.Unmarshal(content, &i18nStringInfos)
 	if err != nil {
-		return nil, err
+		return nil, NewParseError(err)
 	}
 
 	return i18nStringInfos, nil
@@ -330,24 +391,21 @@ func IsInterpolatedString(aString string) bool {
 }
 
 func ConvertToTemplatedString(aString string) string {
-	if !IsInterpolatedString(aString) {
-		return aString
-	}
-
-	re, err := getInterpolatedStringRegexp()
-	if err != nil {
-		fmt.Printf(i18n.T("i18n4go: Error compiling interpolated string Regexp: {{.Arg0}}\n", map[string]interface{}{"Arg0": err.Error()}))
-		return ""
-	}
+	templatedString, _ := ConvertPrintfToTemplatedString(aString)
+	return templatedString
+}
 
-	matches := re.FindAllStringSubmatch(aString, -1)
-	templatedString := aString
-	for i, match := range matches {
-		argName := "{{.Arg" + strconv.Itoa(i) + "}}"
-		templatedString = strings.Replace(templatedString, match[0], argName, 1)
+// GetLocaleFromFilename returns the locale part of a translation file name, e.g., fr_FR for
+// quota.go.fr_FR.json or all.fr_FR.json, or an empty string if no locale is found
+func GetLocaleFromFilename(fileName string) string {
+	var locale string
+	for _, part := range strings.Split(filepath.Base(fileName), ".") {
+		if !invalidLocaleRegexp.MatchString(part) {
+			locale = part
+		}
 	}
 
-	return templatedString
+	return locale
 }
 
 func I18nStringInfoMapValues2Array(i18nStringInfosMap map[string]I18nStringInfo) []I18nStringInfo {
@@ -380,10 +438,15 @@ func getInterpolatedStringRegexp() (*regexp.Regexp, error) {
 }
 
 func GetIgnoreRegexp(ignoreRegexp string) (compiledRegexp *regexp.Regexp) {
+	return GetIgnoreRegexpWithLogger(optionsLogger(Options{}), ignoreRegexp)
+}
+
+// GetIgnoreRegexpWithLogger is like GetIgnoreRegexp but logs with logger
+func GetIgnoreRegexpWithLogger(logger *Logger, ignoreRegexp string) (compiledRegexp *regexp.Regexp) {
 	if ignoreRegexp != "" {
 		reg, err := regexp.Compile(ignoreRegexp)
 		if err != nil {
-			fmt.Println(i18n.T("WARNING: fail to compile ignore-regexp:"), err)
+			logger.Warn(i18n.T("WARNING: fail to compile ignore-regexp:"), err)
 		}
 		compiledRegexp = reg
 	}
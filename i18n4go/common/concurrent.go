@@ -0,0 +1,63 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Jobs returns the number of files inspected concurrently, the jobs option or the number of CPUs by default
+func Jobs(options Options) int {
+	if options.JobsFlag > 0 {
+		return options.JobsFlag
+	}
+
+	return runtime.NumCPU()
+}
+
+// MapConcurrently calls f on each item with a pool of at most jobs goroutines and returns the results in the order
+// of the items, so that they are merged deterministically whatever the order they are computed in, and the error
+// of the first item that failed
+func MapConcurrently[T, R any](items []T, jobs int, f func(T) (R, error)) ([]R, error) {
+	results := make([]R, len(items))
+	errs := make([]error, len(items))
+
+	indexes := make(chan int)
+	var waitGroup sync.WaitGroup
+	for range max(1, min(jobs, len(items))) {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for i := range indexes {
+				results[i], errs[i] = f(items[i])
+			}
+		}()
+	}
+
+	for i := range items {
+		indexes <- i
+	}
+	close(indexes)
+	waitGroup.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
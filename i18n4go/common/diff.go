@@ -0,0 +1,210 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// DIFF_CONTEXT_LINES is the number of unchanged lines shown around the changed lines of a unified diff
+const DIFF_CONTEXT_LINES = 3
+
+// DEV_NULL is the name of the missing side of the unified diff of a created or deleted file
+const DEV_NULL = "/dev/null"
+
+// UnifiedDiff returns the unified diff between the old and new contents of a file, as read by patch and git apply,
+// or an empty string if the contents are the same. An empty old or new name stands for a created or deleted file.
+func UnifiedDiff(oldName, newName string, oldContent, newContent []byte) string {
+	if bytes.Equal(oldContent, newContent) {
+		return ""
+	}
+
+	oldLines, newLines := splitLines(oldContent), splitLines(newContent)
+	ops := diffLines(oldLines, newLines)
+
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "--- %s\n", diffFileName("a/", oldName))
+	fmt.Fprintf(&buffer, "+++ %s\n", diffFileName("b/", newName))
+
+	for _, hunk := range diffHunks(ops) {
+		hunkOps := ops[hunk[0]:hunk[1]]
+
+		oldCount, newCount := 0, 0
+		for _, op := range hunkOps {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+
+		fmt.Fprintf(&buffer, "@@ -%s +%s @@\n", hunkRange(hunkOps[0].oldIndex, oldCount), hunkRange(hunkOps[0].newIndex, newCount))
+		for _, op := range hunkOps {
+			var line string
+			if op.kind == '-' {
+				line = oldLines[op.oldIndex]
+			} else {
+				line = newLines[op.newIndex]
+			}
+
+			buffer.WriteByte(op.kind)
+			buffer.WriteString(line)
+			if !strings.HasSuffix(line, "\n") {
+				buffer.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+	}
+
+	return buffer.String()
+}
+
+// Private
+
+// diffOp is a line of a diff, kept (' '), removed ('-') or added ('+'), with the indexes of the old and new
+// lines before which it applies
+type diffOp struct {
+	kind     byte
+	oldIndex int
+	newIndex int
+}
+
+func splitLines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffLines returns the shortest edit script between the old and new lines using the Myers algorithm
+func diffLines(oldLines, newLines []string) []diffOp {
+	n, m := len(oldLines), len(newLines)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+
+	var trace [][]int
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && oldLines[x] == newLines[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrackDiffLines(trace, offset, n, m)
+			}
+		}
+	}
+
+	return nil
+}
+
+func backtrackDiffLines(trace [][]int, offset, x, y int) []diffOp {
+	var ops []diffOp
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		if d == 0 {
+			prevX, prevY = 0, 0
+		}
+
+		for x > prevX && y > prevY {
+			x, y = x-1, y-1
+			ops = append(ops, diffOp{kind: ' ', oldIndex: x, newIndex: y})
+		}
+
+		if d > 0 {
+			if x == prevX {
+				y--
+				ops = append(ops, diffOp{kind: '+', oldIndex: x, newIndex: y})
+			} else {
+				x--
+				ops = append(ops, diffOp{kind: '-', oldIndex: x, newIndex: y})
+			}
+		}
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+
+	return ops
+}
+
+// diffHunks returns the ranges of the ops of each hunk, i.e., the changed lines with DIFF_CONTEXT_LINES lines of
+// context, merging the hunks whose contexts overlap
+func diffHunks(ops []diffOp) [][2]int {
+	var hunks [][2]int
+	for index, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+
+		start := max(index-DIFF_CONTEXT_LINES, 0)
+		end := min(index+1+DIFF_CONTEXT_LINES, len(ops))
+		if len(hunks) > 0 && start <= hunks[len(hunks)-1][1] {
+			hunks[len(hunks)-1][1] = end
+		} else {
+			hunks = append(hunks, [2]int{start, end})
+		}
+	}
+
+	return hunks
+}
+
+// hunkRange returns the 1-based start line and count of a hunk, the start line of an empty range is the line
+// before the hunk
+func hunkRange(index, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", index)
+	case 1:
+		return fmt.Sprintf("%d", index+1)
+	default:
+		return fmt.Sprintf("%d,%d", index+1, count)
+	}
+}
+
+func diffFileName(prefix, fileName string) string {
+	if fileName == "" {
+		return DEV_NULL
+	}
+
+	return prefix + fileName
+}
@@ -0,0 +1,139 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/printer"
+	"go/token"
+	"sort"
+)
+
+// SourceEdit replaces the original source between Pos and End by the printed Node, or by Text if Node is nil,
+// so that the source outside of the edited expressions, e.g., comments and blank lines, is kept as is
+type SourceEdit struct {
+	Pos  token.Pos
+	End  token.Pos
+	Node ast.Node
+	Text string
+}
+
+// NodeText returns the source of an AST node, or printer.CommentedNode, which may mix original and new nodes
+func NodeText(fileSet *token.FileSet, node any) (string, error) {
+	var buffer bytes.Buffer
+	if err := format.Node(&buffer, fileSet, node); err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+// ApplySourceEdits applies the edits to the source of a file of the file set and returns the gofmt-ed result.
+// The nodes are printed with the comments of the file in the edited source. The edits nested in another edit
+// are ignored since the outer node is printed with its rewritten children, and the last edit added wins over
+// an edit of the same source.
+func ApplySourceEdits(fileSet *token.FileSet, src []byte, comments []*ast.CommentGroup, edits []SourceEdit) ([]byte, error) {
+	content, err := applySourceEdits(fileSet, src, comments, edits, 0, len(src))
+	if err != nil {
+		return nil, err
+	}
+
+	return format.Source(content)
+}
+
+// SourceText returns the source of a file of the file set between pos and end with the edits in this range
+// applied, e.g., to use the source of an expression whose sub-expressions are edited in a new edit
+func SourceText(fileSet *token.FileSet, src []byte, comments []*ast.CommentGroup, edits []SourceEdit, pos, end token.Pos) (string, error) {
+	content, err := applySourceEdits(fileSet, src, comments, edits, fileSet.Position(pos).Offset, fileSet.Position(end).Offset)
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+// Private
+
+func applySourceEdits(fileSet *token.FileSet, src []byte, comments []*ast.CommentGroup, edits []SourceEdit, start, end int) ([]byte, error) {
+	type offsetEdit struct {
+		start, end, index int
+		text              string
+	}
+
+	var offsetEdits []offsetEdit
+	for index, edit := range edits {
+		if !edit.Pos.IsValid() || !edit.End.IsValid() {
+			continue
+		}
+
+		offsetEdit := offsetEdit{
+			start: fileSet.Position(edit.Pos).Offset,
+			end:   fileSet.Position(edit.End).Offset,
+			index: index,
+			text:  edit.Text,
+		}
+		if offsetEdit.start < start || offsetEdit.end > end {
+			continue
+		}
+
+		if edit.Node != nil {
+			var err error
+			commentedNode := &printer.CommentedNode{Node: edit.Node, Comments: commentsInRange(comments, edit.Pos, edit.End)}
+			if offsetEdit.text, err = NodeText(fileSet, commentedNode); err != nil {
+				return nil, err
+			}
+		}
+
+		offsetEdits = append(offsetEdits, offsetEdit)
+	}
+
+	sort.Slice(offsetEdits, func(i, j int) bool {
+		if offsetEdits[i].start != offsetEdits[j].start {
+			return offsetEdits[i].start < offsetEdits[j].start
+		}
+		if offsetEdits[i].end != offsetEdits[j].end {
+			return offsetEdits[i].end > offsetEdits[j].end
+		}
+		return offsetEdits[i].index > offsetEdits[j].index
+	})
+
+	var buffer bytes.Buffer
+	last := start
+	for _, edit := range offsetEdits {
+		if edit.start < last {
+			continue
+		}
+
+		buffer.Write(src[last:edit.start])
+		buffer.WriteString(edit.text)
+		last = edit.end
+	}
+	buffer.Write(src[last:end])
+
+	return buffer.Bytes(), nil
+}
+
+func commentsInRange(comments []*ast.CommentGroup, pos, end token.Pos) []*ast.CommentGroup {
+	var commentsInRange []*ast.CommentGroup
+	for _, comment := range comments {
+		if comment.Pos() >= pos && comment.End() <= end {
+			commentsInRange = append(commentsInRange, comment)
+		}
+	}
+
+	return commentsInRange
+}
@@ -0,0 +1,192 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+)
+
+const (
+	// EXIT_CODE_OK is the exit code of the commands which succeed
+	EXIT_CODE_OK = 0
+
+	// EXIT_CODE_ERROR is the exit code of the invalid usages, e.g., an unknown flag value, and of the other errors
+	EXIT_CODE_ERROR = 1
+
+	// EXIT_CODE_VALIDATION is the exit code of a ValidationError, e.g., translations out of date
+	EXIT_CODE_VALIDATION = 2
+
+	// EXIT_CODE_PARSE is the exit code of a ParseError, e.g., a go file which does not compile
+	EXIT_CODE_PARSE = 3
+
+	// EXIT_CODE_IO is the exit code of an IOError, e.g., a file which cannot be read
+	EXIT_CODE_IO = 4
+
+	// EXIT_CODE_PROVIDER is the exit code of a ProviderError, e.g., a network failure during translation
+	EXIT_CODE_PROVIDER = 5
+)
+
+// ValidationError is returned when the strings or translations checked by a command have problems, e.g., missing,
+// extra or invalid translations
+type ValidationError struct {
+	Err error
+}
+
+// ParseError is returned when a go, template or JSON file cannot be parsed
+type ParseError struct {
+	Err error
+}
+
+// IOError is returned when a file or directory cannot be read or written
+type IOError struct {
+	Err error
+}
+
+// ProviderError is returned when a translation provider fails, e.g., Google Translate
+type ProviderError struct {
+	Err error
+}
+
+// NewValidationError returns err as a ValidationError, unless it is nil or already has a category
+func NewValidationError(err error) error {
+	if err == nil || hasCategory(err) {
+		return err
+	}
+
+	return &ValidationError{Err: err}
+}
+
+// NewParseError returns err as a ParseError, unless it is nil or already has a category
+func NewParseError(err error) error {
+	if err == nil || hasCategory(err) {
+		return err
+	}
+
+	return &ParseError{Err: err}
+}
+
+// NewIOError returns err as an IOError, unless it is nil or already has a category
+func NewIOError(err error) error {
+	if err == nil || hasCategory(err) {
+		return err
+	}
+
+	return &IOError{Err: err}
+}
+
+// NewProviderError returns err as a ProviderError, unless it is nil or already has a category
+func NewProviderError(err error) error {
+	if err == nil || hasCategory(err) {
+		return err
+	}
+
+	return &ProviderError{Err: err}
+}
+
+func (err *ValidationError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *ValidationError) Unwrap() error {
+	return err.Err
+}
+
+func (err *ValidationError) ExitCode() int {
+	return EXIT_CODE_VALIDATION
+}
+
+func (err *ParseError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *ParseError) Unwrap() error {
+	return err.Err
+}
+
+func (err *ParseError) ExitCode() int {
+	return EXIT_CODE_PARSE
+}
+
+func (err *IOError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *IOError) Unwrap() error {
+	return err.Err
+}
+
+func (err *IOError) ExitCode() int {
+	return EXIT_CODE_IO
+}
+
+func (err *ProviderError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *ProviderError) Unwrap() error {
+	return err.Err
+}
+
+func (err *ProviderError) ExitCode() int {
+	return EXIT_CODE_PROVIDER
+}
+
+// WithCategoryOf returns newErr in the category of err, e.g., to report the IOError of a file with the context of the
+// command, or newErr itself when err has no category
+func WithCategoryOf(err error, newErr error) error {
+	var categorized exitCoder
+	if !errors.As(err, &categorized) {
+		return newErr
+	}
+
+	switch categorized.(type) {
+	case *ValidationError:
+		return NewValidationError(newErr)
+	case *ParseError:
+		return NewParseError(newErr)
+	case *IOError:
+		return NewIOError(newErr)
+	case *ProviderError:
+		return NewProviderError(newErr)
+	}
+
+	return newErr
+}
+
+// ExitCode returns the exit code of the category of err, EXIT_CODE_OK when nil and EXIT_CODE_ERROR when it has no
+// category
+func ExitCode(err error) int {
+	if err == nil {
+		return EXIT_CODE_OK
+	}
+
+	var categorized exitCoder
+	if errors.As(err, &categorized) {
+		return categorized.ExitCode()
+	}
+
+	return EXIT_CODE_ERROR
+}
+
+// Private
+
+type exitCoder interface {
+	ExitCode() int
+}
+
+func hasCategory(err error) bool {
+	var categorized exitCoder
+	return errors.As(err, &categorized)
+}
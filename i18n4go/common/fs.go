@@ -0,0 +1,646 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"errors"
+	"go/build"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FS is the file system the commands read their inputs from, i.e., the source trees, the translation files and
+// the configuration files. It follows io/fs except that the names are the file paths of the commands, absolute or
+// relative to the working directory of the file system, rather than slash separated paths relative to a root
+type FS interface {
+	fs.StatFS
+	fs.ReadFileFS
+	fs.ReadDirFS
+
+	// Getwd returns the working directory the relative names are resolved in
+	Getwd() (string, error)
+}
+
+// WritableFS is the file system the commands also write their outputs to, i.e., the extracted strings, the
+// rewritten go files and the translation files
+type WritableFS interface {
+	FS
+
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	MkdirAll(name string, perm fs.FileMode) error
+}
+
+// FileSystem returns the file system of the options, the OS file system by default
+func (options Options) FileSystem() WritableFS {
+	if options.FS == nil {
+		return OsFS{}
+	}
+
+	return options.FS
+}
+
+// OsFS is the file system of the OS, whose working directory is the PWD of the shell running the commands
+type OsFS struct{}
+
+func (OsFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+func (OsFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (OsFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func (OsFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+func (OsFS) Getwd() (string, error) {
+	if pwd := os.Getenv("PWD"); pwd != "" {
+		return pwd, nil
+	}
+
+	return os.Getwd()
+}
+
+func (OsFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+func (OsFS) MkdirAll(name string, perm fs.FileMode) error {
+	return os.MkdirAll(name, perm)
+}
+
+// MemFS is an in memory file system, e.g., to run the commands on source trees which are not on disk
+type MemFS struct {
+	dirName string
+
+	files map[string]*memFile
+	dirs  map[string]time.Time
+	mutex sync.RWMutex
+}
+
+// NewMemFS returns an in memory file system with the files, keyed by their paths, and whose working directory is
+// the root directory
+func NewMemFS(files map[string][]byte) *MemFS {
+	memFS := &MemFS{
+		dirName: string(filepath.Separator),
+		files:   make(map[string]*memFile),
+		dirs:    map[string]time.Time{string(filepath.Separator): time.Now()},
+	}
+
+	for fileName, content := range files {
+		memFS.MkdirAll(filepath.Dir(memFS.abs(fileName)), 0755)
+		memFS.WriteFile(fileName, content, 0644)
+	}
+
+	return memFS
+}
+
+// Chdir changes the working directory the relative names are resolved in
+func (memFS *MemFS) Chdir(dirName string) {
+	memFS.mutex.Lock()
+	defer memFS.mutex.Unlock()
+
+	memFS.dirName = memFS.abs(dirName)
+}
+
+// Filenames returns the sorted absolute paths of the files
+func (memFS *MemFS) Filenames() []string {
+	memFS.mutex.RLock()
+	defer memFS.mutex.RUnlock()
+
+	fileNames := make([]string, 0, len(memFS.files))
+	for fileName := range memFS.files {
+		fileNames = append(fileNames, fileName)
+	}
+
+	sort.Strings(fileNames)
+	return fileNames
+}
+
+func (memFS *MemFS) Open(name string) (fs.File, error) {
+	info, err := memFS.Stat(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	if info.IsDir() {
+		entries, err := memFS.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+
+		return &memDirHandle{info: info, entries: entries}, nil
+	}
+
+	content, err := memFS.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &memFileHandle{info: info, reader: bytes.NewReader(content)}, nil
+}
+
+func (memFS *MemFS) Stat(name string) (fs.FileInfo, error) {
+	memFS.mutex.RLock()
+	defer memFS.mutex.RUnlock()
+
+	absName := memFS.abs(name)
+	if file, ok := memFS.files[absName]; ok {
+		return file.info(), nil
+	}
+
+	if modTime, ok := memFS.dirs[absName]; ok {
+		return &memFileInfo{name: filepath.Base(absName), mode: fs.ModeDir | 0755, modTime: modTime}, nil
+	}
+
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+func (memFS *MemFS) ReadFile(name string) ([]byte, error) {
+	memFS.mutex.RLock()
+	defer memFS.mutex.RUnlock()
+
+	absName := memFS.abs(name)
+	file, ok := memFS.files[absName]
+	if !ok {
+		if _, ok := memFS.dirs[absName]; ok {
+			return nil, &fs.PathError{Op: "read", Path: name, Err: errors.New("is a directory")}
+		}
+
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return append([]byte(nil), file.content...), nil
+}
+
+func (memFS *MemFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	memFS.mutex.RLock()
+	defer memFS.mutex.RUnlock()
+
+	absName := memFS.abs(name)
+	if _, ok := memFS.dirs[absName]; !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	var entries []fs.DirEntry
+	for fileName, file := range memFS.files {
+		if filepath.Dir(fileName) == absName {
+			entries = append(entries, fs.FileInfoToDirEntry(file.info()))
+		}
+	}
+
+	for dirName, modTime := range memFS.dirs {
+		if dirName != absName && filepath.Dir(dirName) == absName {
+			entries = append(entries, fs.FileInfoToDirEntry(&memFileInfo{name: filepath.Base(dirName), mode: fs.ModeDir | 0755, modTime: modTime}))
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+func (memFS *MemFS) Getwd() (string, error) {
+	memFS.mutex.RLock()
+	defer memFS.mutex.RUnlock()
+
+	return memFS.dirName, nil
+}
+
+// WriteFile writes the file, whose directory must exist
+func (memFS *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	memFS.mutex.Lock()
+	defer memFS.mutex.Unlock()
+
+	absName := memFS.abs(name)
+	if _, ok := memFS.dirs[filepath.Dir(absName)]; !ok {
+		return &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	if _, ok := memFS.dirs[absName]; ok {
+		return &fs.PathError{Op: "open", Path: name, Err: errors.New("is a directory")}
+	}
+
+	memFS.files[absName] = &memFile{
+		name:    filepath.Base(absName),
+		content: append([]byte(nil), data...),
+		mode:    perm,
+		modTime: time.Now(),
+	}
+
+	return nil
+}
+
+func (memFS *MemFS) MkdirAll(name string, perm fs.FileMode) error {
+	memFS.mutex.Lock()
+	defer memFS.mutex.Unlock()
+
+	if name == "" {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	for dirName := memFS.abs(name); ; dirName = filepath.Dir(dirName) {
+		if _, ok := memFS.files[dirName]; ok {
+			return &fs.PathError{Op: "mkdir", Path: name, Err: errors.New("not a directory")}
+		}
+
+		if _, ok := memFS.dirs[dirName]; !ok {
+			memFS.dirs[dirName] = time.Now()
+		}
+
+		if filepath.Dir(dirName) == dirName {
+			return nil
+		}
+	}
+}
+
+// OverlayFS is a file system whose files override the files of a base file system, e.g., the unsaved buffers of an
+// editor. Its writes go to the base file system, unless it records them for a dry run
+type OverlayFS struct {
+	Base    WritableFS
+	Overlay *MemFS
+
+	// DryRun writes to the overlay, leaving the base file system as is
+	DryRun bool
+}
+
+// NewOverlayFS returns the file system of the base one with the files, keyed by their paths, overriding its files
+func NewOverlayFS(base WritableFS, files map[string][]byte) *OverlayFS {
+	overlay := &OverlayFS{Base: base, Overlay: NewMemFS(nil)}
+	if dirName, err := base.Getwd(); err == nil {
+		overlay.Overlay.Chdir(dirName)
+	}
+
+	for fileName, content := range files {
+		overlay.Overlay.MkdirAll(filepath.Dir(overlay.Overlay.abs(fileName)), 0755)
+		overlay.Overlay.WriteFile(fileName, content, 0644)
+	}
+
+	return overlay
+}
+
+// NewDryRunFS returns the file system of the base one recording its writes, i.e., the Written files, instead of
+// writing them
+func NewDryRunFS(base WritableFS) *OverlayFS {
+	overlay := NewOverlayFS(base, nil)
+	overlay.DryRun = true
+
+	return overlay
+}
+
+// Written returns the sorted absolute paths of the files written during a dry run
+func (overlay *OverlayFS) Written() []string {
+	if !overlay.DryRun {
+		return nil
+	}
+
+	return overlay.Overlay.Filenames()
+}
+
+func (overlay *OverlayFS) Open(name string) (fs.File, error) {
+	if info, err := overlay.Overlay.Stat(name); err == nil && !info.IsDir() {
+		return overlay.Overlay.Open(name)
+	}
+
+	info, err := overlay.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		entries, err := overlay.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+
+		return &memDirHandle{info: info, entries: entries}, nil
+	}
+
+	return overlay.Base.Open(name)
+}
+
+func (overlay *OverlayFS) Stat(name string) (fs.FileInfo, error) {
+	if info, err := overlay.Overlay.Stat(name); err == nil {
+		return info, nil
+	}
+
+	return overlay.Base.Stat(name)
+}
+
+func (overlay *OverlayFS) ReadFile(name string) ([]byte, error) {
+	if info, err := overlay.Overlay.Stat(name); err == nil && !info.IsDir() {
+		return overlay.Overlay.ReadFile(name)
+	}
+
+	return overlay.Base.ReadFile(name)
+}
+
+// ReadDir returns the entries of the directory in both file systems, those of the overlay overriding the others
+func (overlay *OverlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	baseEntries, baseErr := overlay.Base.ReadDir(name)
+	overlayEntries, overlayErr := overlay.Overlay.ReadDir(name)
+	if baseErr != nil && overlayErr != nil {
+		return nil, baseErr
+	}
+
+	entries := make(map[string]fs.DirEntry, len(baseEntries)+len(overlayEntries))
+	for _, entry := range baseEntries {
+		entries[entry.Name()] = entry
+	}
+	for _, entry := range overlayEntries {
+		entries[entry.Name()] = entry
+	}
+
+	mergedEntries := make([]fs.DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		mergedEntries = append(mergedEntries, entry)
+	}
+
+	sort.Slice(mergedEntries, func(i, j int) bool { return mergedEntries[i].Name() < mergedEntries[j].Name() })
+	return mergedEntries, nil
+}
+
+func (overlay *OverlayFS) Getwd() (string, error) {
+	return overlay.Base.Getwd()
+}
+
+func (overlay *OverlayFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !overlay.DryRun {
+		return overlay.Base.WriteFile(name, data, perm)
+	}
+
+	info, err := overlay.Stat(filepath.Dir(name))
+	if err != nil || !info.IsDir() {
+		return &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	err = overlay.Overlay.MkdirAll(filepath.Dir(overlay.Overlay.abs(name)), 0755)
+	if err != nil {
+		return err
+	}
+
+	return overlay.Overlay.WriteFile(name, data, perm)
+}
+
+func (overlay *OverlayFS) MkdirAll(name string, perm fs.FileMode) error {
+	if !overlay.DryRun {
+		return overlay.Base.MkdirAll(name, perm)
+	}
+
+	return overlay.Overlay.MkdirAll(name, perm)
+}
+
+// BuildContextFS returns the build context reading the directories and the files of the file system
+func BuildContextFS(fsys FS, buildContext build.Context) build.Context {
+	if _, ok := fsys.(OsFS); ok {
+		return buildContext
+	}
+
+	buildContext.IsDir = func(dirName string) bool {
+		info, err := fsys.Stat(dirName)
+		return err == nil && info.IsDir()
+	}
+	buildContext.ReadDir = func(dirName string) ([]fs.FileInfo, error) {
+		entries, err := fsys.ReadDir(dirName)
+		if err != nil {
+			return nil, err
+		}
+
+		infos := make([]fs.FileInfo, 0, len(entries))
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil {
+				return nil, err
+			}
+			infos = append(infos, info)
+		}
+
+		return infos, nil
+	}
+	buildContext.OpenFile = func(fileName string) (io.ReadCloser, error) {
+		content, err := fsys.ReadFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+
+		return io.NopCloser(bytes.NewReader(content)), nil
+	}
+
+	return buildContext
+}
+
+// Glob returns the files of the file system matching the pattern like filepath.Glob
+func Glob(fsys FS, pattern string) ([]string, error) {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	if !hasGlobMeta(pattern) {
+		if _, err := fsys.Stat(pattern); err != nil {
+			return nil, nil
+		}
+		return []string{pattern}, nil
+	}
+
+	dirName, fileName := filepath.Split(pattern)
+	dirName = filepath.Clean(dirName)
+	if dirName == "." && !strings.HasPrefix(pattern, ".") {
+		dirName = ""
+	}
+
+	dirNames := []string{dirName}
+	if hasGlobMeta(dirName) {
+		var err error
+		dirNames, err = Glob(fsys, dirName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var matches []string
+	for _, dirName := range dirNames {
+		readDirName := dirName
+		if readDirName == "" {
+			readDirName = "."
+		}
+
+		entries, err := fsys.ReadDir(readDirName)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if matched, _ := filepath.Match(fileName, entry.Name()); matched {
+				matches = append(matches, filepath.Join(dirName, entry.Name()))
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+// WalkDir walks the file tree of the file system rooted at root like filepath.WalkDir
+func WalkDir(fsys FS, root string, fn fs.WalkDirFunc) error {
+	info, err := fsys.Stat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walkDir(fsys, root, fs.FileInfoToDirEntry(info), fn)
+	}
+
+	if err == filepath.SkipDir || err == filepath.SkipAll {
+		return nil
+	}
+	return err
+}
+
+// AbsPath returns the absolute path of a file of the file system
+func AbsPath(fsys FS, fileName string) string {
+	if filepath.IsAbs(fileName) {
+		return filepath.Clean(fileName)
+	}
+
+	dirName, err := fsys.Getwd()
+	if err != nil {
+		return filepath.Clean(fileName)
+	}
+
+	return filepath.Join(dirName, fileName)
+}
+
+// Private
+
+type memFile struct {
+	name    string
+	content []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (file *memFile) info() fs.FileInfo {
+	return &memFileInfo{name: file.name, size: int64(len(file.content)), mode: file.mode, modTime: file.modTime}
+}
+
+type memFileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (info *memFileInfo) Name() string       { return info.name }
+func (info *memFileInfo) Size() int64        { return info.size }
+func (info *memFileInfo) Mode() fs.FileMode  { return info.mode }
+func (info *memFileInfo) ModTime() time.Time { return info.modTime }
+func (info *memFileInfo) IsDir() bool        { return info.mode.IsDir() }
+func (info *memFileInfo) Sys() any           { return nil }
+
+type memFileHandle struct {
+	info   fs.FileInfo
+	reader *bytes.Reader
+}
+
+func (file *memFileHandle) Stat() (fs.FileInfo, error) { return file.info, nil }
+func (file *memFileHandle) Read(p []byte) (int, error) { return file.reader.Read(p) }
+func (file *memFileHandle) Close() error               { return nil }
+
+type memDirHandle struct {
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (dir *memDirHandle) Stat() (fs.FileInfo, error) { return dir.info, nil }
+func (dir *memDirHandle) Close() error               { return nil }
+
+func (dir *memDirHandle) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: dir.info.Name(), Err: errors.New("is a directory")}
+}
+
+func (dir *memDirHandle) ReadDir(n int) ([]fs.DirEntry, error) {
+	entries := dir.entries[dir.offset:]
+	if n > 0 {
+		if len(entries) == 0 {
+			return nil, io.EOF
+		}
+		if len(entries) > n {
+			entries = entries[:n]
+		}
+	}
+
+	dir.offset += len(entries)
+	return entries, nil
+}
+
+func walkDir(fsys FS, path string, entry fs.DirEntry, fn fs.WalkDirFunc) error {
+	if err := fn(path, entry, nil); err != nil || !entry.IsDir() {
+		if err == filepath.SkipDir && entry.IsDir() {
+			err = nil
+		}
+		return err
+	}
+
+	entries, err := fsys.ReadDir(path)
+	if err != nil {
+		err = fn(path, entry, err)
+		if err != nil {
+			if err == filepath.SkipDir && entry.IsDir() {
+				err = nil
+			}
+			return err
+		}
+	}
+
+	for _, childEntry := range entries {
+		err := walkDir(fsys, filepath.Join(path, childEntry.Name()), childEntry, fn)
+		if err != nil {
+			if err == filepath.SkipDir {
+				break
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func hasGlobMeta(path string) bool {
+	return strings.ContainsAny(path, `*?[\`)
+}
+
+// abs returns the cleaned absolute path of a name, or an empty path which does not exist for an empty name like the
+// OS, the caller holding the mutex or not changing the working directory
+func (memFS *MemFS) abs(name string) string {
+	if name == "" {
+		return ""
+	}
+
+	if filepath.IsAbs(name) {
+		return filepath.Clean(name)
+	}
+
+	return filepath.Join(memFS.dirName, name)
+}
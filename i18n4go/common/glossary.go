@@ -0,0 +1,192 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+const ALL_LOCALES = "*"
+
+// Glossary lists source terms with their mandated translation per locale, and terms which must never
+// appear in the translations of a locale
+type Glossary struct {
+	Terms          []GlossaryTerm      `json:"terms"`
+	ForbiddenTerms map[string][]string `json:"forbiddenTerms"`
+}
+
+// GlossaryTerm is a source term which must be kept as is for the DoNotTranslate locales, or
+// translated with the given term for the Translations locales. Use "*" to match all locales
+type GlossaryTerm struct {
+	Term           string            `json:"term"`
+	DoNotTranslate []string          `json:"doNotTranslate"`
+	Translations   map[string]string `json:"translations"`
+}
+
+type GlossaryViolation struct {
+	Locale  string
+	Term    string
+	Message string
+}
+
+func LoadGlossary(fileName string) (*Glossary, error) {
+	return LoadGlossaryFS(OsFS{}, fileName)
+}
+
+func LoadGlossaryFS(fsys FS, fileName string) (*Glossary, error) {
+	content, err := fsys.ReadFile(fileName)
+	if err != nil {
+		return nil, NewIOError(err)
+	}
+
+	var glossary Glossary
+	err = json.Unmarshal(content, &glossary)
+	if err != nil {
+		return nil, NewParseError(err)
+	}
+
+	return &glossary, nil
+}
+
+// Violations returns the glossary rules broken by the translation of the source string for the locale
+func (glossary *Glossary) Violations(locale string, source string, translation string) []GlossaryViolation {
+	var violations []GlossaryViolation
+
+	for _, term := range glossary.Terms {
+		if term.Term == "" || !containsTerm(source, term.Term, false) {
+			continue
+		}
+
+		if MatchesLocale(term.DoNotTranslate, locale) {
+			if !containsTerm(translation, term.Term, false) {
+				violations = append(violations, GlossaryViolation{
+					Locale:  locale,
+					Term:    term.Term,
+					Message: i18n.T("glossary term \"{{.Arg0}}\" must not be translated for locale {{.Arg1}}", map[string]interface{}{"Arg0": term.Term, "Arg1": locale}),
+				})
+			}
+			continue
+		}
+
+		requiredTerm, ok := translationForLocale(term.Translations, locale)
+		if ok && !containsTerm(translation, requiredTerm, true) {
+			violations = append(violations, GlossaryViolation{
+				Locale:  locale,
+				Term:    term.Term,
+				Message: i18n.T("glossary term \"{{.Arg0}}\" must be translated as \"{{.Arg1}}\" for locale {{.Arg2}}", map[string]interface{}{"Arg0": term.Term, "Arg1": requiredTerm, "Arg2": locale}),
+			})
+		}
+	}
+
+	var forbiddenLocales []string
+	for forbiddenLocale := range glossary.ForbiddenTerms {
+		forbiddenLocales = append(forbiddenLocales, forbiddenLocale)
+	}
+	sort.Strings(forbiddenLocales)
+
+	for _, forbiddenLocale := range forbiddenLocales {
+		if !MatchesLocale([]string{forbiddenLocale}, locale) {
+			continue
+		}
+
+		for _, forbiddenTerm := range glossary.ForbiddenTerms[forbiddenLocale] {
+			if forbiddenTerm != "" && containsTerm(translation, forbiddenTerm, true) {
+				violations = append(violations, GlossaryViolation{
+					Locale:  locale,
+					Term:    forbiddenTerm,
+					Message: i18n.T("forbidden term \"{{.Arg0}}\" used for locale {{.Arg1}}", map[string]interface{}{"Arg0": forbiddenTerm, "Arg1": locale}),
+				})
+			}
+		}
+	}
+
+	return violations
+}
+
+// MatchesLocale matches a locale such as fr_FR with the entries fr_FR, fr, fr-FR or *
+func MatchesLocale(locales []string, locale string) bool {
+	for _, aLocale := range locales {
+		if aLocale == ALL_LOCALES || normalizeLocale(aLocale) == normalizeLocale(locale) ||
+			normalizeLocale(aLocale) == localeLanguage(locale) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Private
+
+func containsTerm(aString string, term string, ignoreCase bool) bool {
+	pattern := regexp.QuoteMeta(term)
+	if startsWithWordChar(term) {
+		pattern = `\b` + pattern
+	}
+	if endsWithWordChar(term) {
+		pattern = pattern + `\b`
+	}
+	if ignoreCase {
+		pattern = `(?i)` + pattern
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return strings.Contains(aString, term)
+	}
+
+	return re.MatchString(aString)
+}
+
+func startsWithWordChar(aString string) bool {
+	return len(aString) > 0 && isASCIIWordChar(aString[0])
+}
+
+func endsWithWordChar(aString string) bool {
+	return len(aString) > 0 && isASCIIWordChar(aString[len(aString)-1])
+}
+
+func isASCIIWordChar(c byte) bool {
+	return c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func translationForLocale(translations map[string]string, locale string) (string, bool) {
+	for aLocale, translation := range translations {
+		if normalizeLocale(aLocale) == normalizeLocale(locale) {
+			return translation, true
+		}
+	}
+
+	for aLocale, translation := range translations {
+		if normalizeLocale(aLocale) == localeLanguage(locale) {
+			return translation, true
+		}
+	}
+
+	translation, ok := translations[ALL_LOCALES]
+	return translation, ok
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.Replace(locale, "-", "_", -1))
+}
+
+func localeLanguage(locale string) string {
+	return strings.SplitN(normalizeLocale(locale), "_", 2)[0]
+}
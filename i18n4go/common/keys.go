@@ -0,0 +1,169 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+const (
+	// TEXT_KEY_FORMAT uses the source text as the ID of the strings, the default
+	TEXT_KEY_FORMAT = "text"
+
+	// SLUG_KEY_FORMAT generates IDs made of the package name and the first words of the source text,
+	// e.g., greetings.hello_world for "Hello world!" in package greetings
+	SLUG_KEY_FORMAT = "slug"
+
+	// HASH_KEY_FORMAT generates IDs made of the package name and a hash of the source text,
+	// e.g., greetings.7f83b1657f for "Hello world!" in package greetings
+	HASH_KEY_FORMAT = "hash"
+
+	MAX_SLUG_LENGTH = 40
+	HASH_KEY_LENGTH = 10
+)
+
+var templateActionRegexp = regexp.MustCompile(`{{[^}]*}}`)
+
+// KeyGenerator generates stable semantic IDs for the source texts of packages, so that fixing a source text does
+// not change its ID. The same text of a package always has the same key, and a slug is suffixed with the hash of
+// the text when another text of the package has the same slug.
+type KeyGenerator struct {
+	KeyFormat string
+
+	keys  map[string]string
+	texts map[string]string
+}
+
+// NewKeyGenerator returns a generator of the keys of the format, one of text, slug or hash, an empty format is text
+func NewKeyGenerator(keyFormat string) (*KeyGenerator, error) {
+	if keyFormat == "" {
+		keyFormat = TEXT_KEY_FORMAT
+	}
+
+	if keyFormat != TEXT_KEY_FORMAT && keyFormat != SLUG_KEY_FORMAT && keyFormat != HASH_KEY_FORMAT {
+		return nil, errors.New(i18n.T("i18n4go: unknown key format {{.Arg0}}, one of: text, slug, hash", map[string]any{"Arg0": keyFormat}))
+	}
+
+	return &KeyGenerator{
+		KeyFormat: keyFormat,
+		keys:      make(map[string]string),
+		texts:     make(map[string]string),
+	}, nil
+}
+
+// Key returns the ID of a text of a package
+func (kg *KeyGenerator) Key(packageName, text string) string {
+	if kg.KeyFormat == TEXT_KEY_FORMAT {
+		return text
+	}
+
+	scopedText := packageName + "\x00" + text
+	if key, ok := kg.keys[scopedText]; ok {
+		return key
+	}
+
+	key := scopedKey(packageName, textHash(text))
+	if kg.KeyFormat == SLUG_KEY_FORMAT {
+		if slug := TextSlug(text); slug != "" {
+			key = scopedKey(packageName, slug)
+			if _, ok := kg.texts[key]; ok {
+				key = scopedKey(packageName, slug+"_"+textHash(text))
+			}
+		}
+	}
+
+	kg.keys[scopedText] = key
+	kg.texts[key] = text
+	return key
+}
+
+// Reserve sets the key of a text, e.g., the key of a catalog which is already key based, so that the text keeps
+// its key in the package scope of the key and the key is not generated for another text
+func (kg *KeyGenerator) Reserve(key, text string) {
+	packageName, _, found := strings.Cut(key, ".")
+	if !found {
+		packageName = ""
+	}
+
+	kg.keys[packageName+"\x00"+text] = key
+	kg.texts[key] = text
+}
+
+// StringID returns the ID of an extracted string, its generated key if any, otherwise its value
+func (stringInfo StringInfo) StringID() string {
+	if stringInfo.ID != "" {
+		return stringInfo.ID
+	}
+
+	return stringInfo.Value
+}
+
+// IsTextKey returns true if the ID of an i18n string is its source text, i.e., the catalog is not key based
+func IsTextKey(i18nStringInfo I18nStringInfo) bool {
+	return i18nStringInfo.ID == i18nStringInfo.Translation
+}
+
+// TextSlug returns the lower case words of the text joined with underscores, without the template args and printf
+// verbs, and truncated after MAX_SLUG_LENGTH characters
+func TextSlug(text string) string {
+	text = templateActionRegexp.ReplaceAllString(text, " ")
+	for _, verb := range ParsePrintfVerbs(text) {
+		text = strings.Replace(text, verb.Text, " ", 1)
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var slug string
+	for _, word := range words {
+		if slug != "" && len(slug)+1+len(word) > MAX_SLUG_LENGTH {
+			break
+		}
+
+		if slug != "" {
+			slug += "_"
+		}
+		slug += word
+	}
+
+	if len(slug) > MAX_SLUG_LENGTH {
+		slug = strings.ToValidUTF8(slug[:MAX_SLUG_LENGTH], "")
+	}
+
+	return slug
+}
+
+// Private
+
+func scopedKey(packageName, key string) string {
+	if packageName == "" {
+		return key
+	}
+
+	return packageName + "." + key
+}
+
+func textHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])[:HASH_KEY_LENGTH]
+}
@@ -0,0 +1,201 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+const (
+	LOG_LEVEL_ERROR = "error"
+	LOG_LEVEL_WARN  = "warn"
+	LOG_LEVEL_INFO  = "info"
+	LOG_LEVEL_DEBUG = "debug"
+
+	LOG_FORMAT_TEXT = "text"
+	LOG_FORMAT_JSON = "json"
+)
+
+// LOG_LEVELS are the levels of the logged messages, from the least to the most verbose
+var LOG_LEVELS = []string{LOG_LEVEL_ERROR, LOG_LEVEL_WARN, LOG_LEVEL_INFO, LOG_LEVEL_DEBUG}
+
+// LogLevel is the index of a level of LOG_LEVELS, a logger logs the messages of its level and of the less verbose ones
+type LogLevel int
+
+const (
+	ERROR LogLevel = iota
+	WARN
+	INFO
+	DEBUG
+)
+
+func (level LogLevel) String() string {
+	return LOG_LEVELS[level]
+}
+
+// ParseLogLevel returns the level of one of the LOG_LEVELS
+func ParseLogLevel(name string) (LogLevel, error) {
+	for i, levelName := range LOG_LEVELS {
+		if strings.ToLower(name) == levelName {
+			return LogLevel(i), nil
+		}
+	}
+
+	return ERROR, errors.New(i18n.T("i18n4go: unknown log level {{.Arg0}}, one of: error, warn, info, debug", map[string]any{"Arg0": name}))
+}
+
+// Logger logs the messages of the commands with a level, as plain text like they are printed, or as JSON objects
+// with the time, the level and the message, one per line. A nil Logger logs nothing
+type Logger struct {
+	Level  LogLevel
+	Format string
+	Writer io.Writer
+
+	mutex sync.Mutex
+}
+
+// NewLogger returns the logger of the options writing to stderr, i.e., of the --log-level flag, or of the --quiet
+// flag logging only the errors, or of the --verbose flag logging everything, the warnings and the errors by
+// default, and of the --log-format flag
+func NewLogger(options Options) (*Logger, error) {
+	level := WARN
+	switch {
+	case options.LogLevelFlag != "":
+		var err error
+		level, err = ParseLogLevel(options.LogLevelFlag)
+		if err != nil {
+			return nil, err
+		}
+	case options.QuietFlag:
+		level = ERROR
+	case options.VerboseFlag:
+		level = DEBUG
+	}
+
+	format := LOG_FORMAT_TEXT
+	if options.LogFormatFlag != "" {
+		format = strings.ToLower(options.LogFormatFlag)
+		if format != LOG_FORMAT_TEXT && format != LOG_FORMAT_JSON {
+			return nil, errors.New(i18n.T("i18n4go: unknown log format {{.Arg0}}, one of: text, json", map[string]any{"Arg0": options.LogFormatFlag}))
+		}
+	}
+
+	return &Logger{Level: level, Format: format, Writer: os.Stderr}, nil
+}
+
+// Enabled returns true if the messages of the level are logged
+func (logger *Logger) Enabled(level LogLevel) bool {
+	return logger != nil && level <= logger.Level
+}
+
+func (logger *Logger) Error(a ...any) {
+	logger.log(ERROR, fmt.Sprintln(a...))
+}
+
+func (logger *Logger) Errorf(msg string, a ...any) {
+	logger.log(ERROR, fmt.Sprintf(msg, a...))
+}
+
+func (logger *Logger) Warn(a ...any) {
+	logger.log(WARN, fmt.Sprintln(a...))
+}
+
+func (logger *Logger) Warnf(msg string, a ...any) {
+	logger.log(WARN, fmt.Sprintf(msg, a...))
+}
+
+func (logger *Logger) Info(a ...any) {
+	logger.log(INFO, fmt.Sprintln(a...))
+}
+
+func (logger *Logger) Infof(msg string, a ...any) {
+	logger.log(INFO, fmt.Sprintf(msg, a...))
+}
+
+func (logger *Logger) Debug(a ...any) {
+	logger.log(DEBUG, fmt.Sprintln(a...))
+}
+
+func (logger *Logger) Debugf(msg string, a ...any) {
+	logger.log(DEBUG, fmt.Sprintf(msg, a...))
+}
+
+// Println logs the message at the debug level, so that the logger is the PrinterInterface of the verbose output
+func (logger *Logger) Println(a ...any) (int, error) {
+	return logger.log(DEBUG, fmt.Sprintln(a...))
+}
+
+// Printf logs the message at the debug level, so that the logger is the PrinterInterface of the verbose output
+func (logger *Logger) Printf(msg string, a ...any) (int, error) {
+	return logger.log(DEBUG, fmt.Sprintf(msg, a...))
+}
+
+// Private
+
+// optionsLogger returns the Logger of the options, or the logger of their log flags, a nil logger logging nothing if
+// the flags are invalid
+func optionsLogger(options Options) *Logger {
+	if options.Logger != nil {
+		return options.Logger
+	}
+
+	logger, err := NewLogger(options)
+	if err != nil {
+		return nil
+	}
+
+	return logger
+}
+
+type logEntry struct {
+	Time  string `json:"time"`
+	Level string `json:"level"`
+	Msg   string `json:"msg"`
+}
+
+func (logger *Logger) log(level LogLevel, msg string) (int, error) {
+	if !logger.Enabled(level) {
+		return 0, nil
+	}
+
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	if logger.Format != LOG_FORMAT_JSON {
+		return io.WriteString(logger.Writer, msg)
+	}
+
+	// the blank lines separating the text output are not logged
+	msg = strings.TrimSpace(msg)
+	if msg == "" {
+		return 0, nil
+	}
+
+	line, err := json.Marshal(logEntry{Time: time.Now().Format(time.RFC3339), Level: level.String(), Msg: msg})
+	if err != nil {
+		return 0, err
+	}
+
+	return logger.Writer.Write(append(line, '\n'))
+}
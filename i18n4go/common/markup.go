@@ -0,0 +1,168 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	MARKUP_TAG_REGEXP    = `<(/?)([a-zA-Z][a-zA-Z0-9-]*)(?:\s[^<>]*?)?(/?)>`
+	ANSI_SEQUENCE_REGEXP = `\x1b\[[0-9;?]*[ -/]*[@-~]`
+)
+
+var (
+	markupTagRegexp    = regexp.MustCompile(MARKUP_TAG_REGEXP)
+	ansiSequenceRegexp = regexp.MustCompile(ANSI_SEQUENCE_REGEXP)
+)
+
+var VOID_MARKUP_TAGS = map[string]bool{
+	"br":    true,
+	"hr":    true,
+	"img":   true,
+	"input": true,
+	"link":  true,
+	"meta":  true,
+	"wbr":   true,
+}
+
+// Full width and other locale specific punctuation mapped to their ASCII equivalent
+var TERMINAL_PUNCTUATION = map[rune]rune{
+	'.': '.',
+	'!': '!',
+	'?': '?',
+	':': ':',
+	';': ';',
+	'…': '…',
+	'。': '.',
+	'．': '.',
+	'！': '!',
+	'？': '?',
+	'：': ':',
+	'；': ';',
+	'؟': '?',
+	'।': '.',
+}
+
+// GetMarkupTags returns the tags of the string normalized to their names, e.g., <a href="..."> is returned as <a>
+func GetMarkupTags(aString string) []string {
+	var tags []string
+	for _, match := range markupTagRegexp.FindAllStringSubmatch(aString, -1) {
+		tags = append(tags, "<"+match[1]+strings.ToLower(match[2])+match[3]+">")
+	}
+	return tags
+}
+
+// GetUnbalancedMarkupTags returns the tags that are not properly opened or closed in the string
+func GetUnbalancedMarkupTags(aString string) []string {
+	var stack, unbalanced []string
+	for _, match := range markupTagRegexp.FindAllStringSubmatch(aString, -1) {
+		name := strings.ToLower(match[2])
+		if match[3] == "/" || VOID_MARKUP_TAGS[name] {
+			continue
+		}
+
+		if match[1] == "" {
+			stack = append(stack, name)
+			continue
+		}
+
+		if len(stack) > 0 && stack[len(stack)-1] == name {
+			stack = stack[:len(stack)-1]
+		} else {
+			unbalanced = append(unbalanced, "</"+name+">")
+		}
+	}
+
+	for _, name := range stack {
+		unbalanced = append(unbalanced, "<"+name+">")
+	}
+
+	return unbalanced
+}
+
+// GetANSISequences returns the ANSI terminal escape sequences, e.g., color codes, found in the string
+func GetANSISequences(aString string) []string {
+	return ansiSequenceRegexp.FindAllString(aString, -1)
+}
+
+// MissingStrings returns the values of expected which are not in actual, counting duplicates
+func MissingStrings(expected []string, actual []string) []string {
+	counts := make(map[string]int)
+	for _, value := range actual {
+		counts[value]++
+	}
+
+	var missing []string
+	for _, value := range expected {
+		if counts[value] > 0 {
+			counts[value]--
+		} else {
+			missing = append(missing, value)
+		}
+	}
+
+	return missing
+}
+
+// SameStrings returns true if both lists contain the same values regardless of order
+func SameStrings(one []string, two []string) bool {
+	if len(one) != len(two) {
+		return false
+	}
+
+	sortedOne := append([]string{}, one...)
+	sortedTwo := append([]string{}, two...)
+	sort.Strings(sortedOne)
+	sort.Strings(sortedTwo)
+
+	for i := range sortedOne {
+		if sortedOne[i] != sortedTwo[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// LeadingWhitespace returns the whitespace prefix of the string
+func LeadingWhitespace(aString string) string {
+	return aString[:len(aString)-len(strings.TrimLeftFunc(aString, unicode.IsSpace))]
+}
+
+// TrailingWhitespace returns the whitespace suffix of the string
+func TrailingWhitespace(aString string) string {
+	return aString[len(strings.TrimRightFunc(aString, unicode.IsSpace)):]
+}
+
+// TerminalPunctuation returns the normalized punctuation ending the string, ignoring trailing whitespace,
+// or an empty string if the string does not end with punctuation
+func TerminalPunctuation(aString string) string {
+	trimmed := strings.TrimRightFunc(aString, unicode.IsSpace)
+	if strings.HasSuffix(trimmed, "...") {
+		return "…"
+	}
+
+	r, _ := utf8.DecodeLastRuneInString(trimmed)
+	if punctuation, ok := TERMINAL_PUNCTUATION[r]; ok {
+		return string(punctuation)
+	}
+
+	return ""
+}
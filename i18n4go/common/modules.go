@@ -0,0 +1,102 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+	"go/build"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+const GO_MOD_FILENAME = "go.mod"
+
+// ImportPath returns the import path of the package of a directory, i.e., the path of the module of its go.mod file,
+// or of the go.mod file of its parents, followed by the path of the directory in the module, or else the path of the
+// directory in the src directory of a GOPATH, or an empty string if the directory is in neither
+func ImportPath(dirName string) (string, error) {
+	return ImportPathFS(OsFS{}, dirName)
+}
+
+// ImportPathFS is like ImportPath but uses the file system fsys
+func ImportPathFS(fsys FS, dirName string) (string, error) {
+	absDirName := AbsPath(fsys, dirName)
+
+	modulePath, moduleDirName, err := FindModuleFS(fsys, absDirName)
+	if err != nil {
+		return "", err
+	}
+
+	if moduleDirName == "" {
+		return gopathImportPath(absDirName), nil
+	}
+
+	relDirName, err := filepath.Rel(moduleDirName, absDirName)
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(modulePath, filepath.ToSlash(relDirName)), nil
+}
+
+// FindModule returns the path and the directory of the module of a directory, found with the go.mod file of the
+// directory or of its closest parent, or empty strings if the directory is not in a module, e.g., in a workspace each
+// module is found with its own go.mod file
+func FindModule(absDirName string) (string, string, error) {
+	return FindModuleFS(OsFS{}, absDirName)
+}
+
+// FindModuleFS is like FindModule but uses the file system fsys
+func FindModuleFS(fsys FS, absDirName string) (string, string, error) {
+	for dirName := absDirName; ; dirName = filepath.Dir(dirName) {
+		goModFilename := filepath.Join(dirName, GO_MOD_FILENAME)
+		content, err := fsys.ReadFile(goModFilename)
+		if err == nil {
+			modulePath := modfile.ModulePath(content)
+			if modulePath == "" {
+				return "", "", NewParseError(errors.New(i18n.T("i18n4go: the go.mod file {{.Arg0}} has no module directive", map[string]any{"Arg0": goModFilename})))
+			}
+
+			return modulePath, dirName, nil
+		}
+
+		if !os.IsNotExist(err) {
+			return "", "", NewIOError(err)
+		}
+
+		if filepath.Dir(dirName) == dirName {
+			return "", "", nil
+		}
+	}
+}
+
+// Private
+
+func gopathImportPath(absDirName string) string {
+	for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+		srcDirName := filepath.Join(gopath, "src")
+		if relDirName, err := filepath.Rel(srcDirName, absDirName); err == nil && relDirName != "." && !strings.HasPrefix(relDirName, "..") {
+			return filepath.ToSlash(relDirName)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,391 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"unicode/utf8"
+
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+const (
+	PRINTF_FLAGS = "+-# 0"
+
+	// PRINTF_SIMPLE_VERBS format their argument the same way as a {{.ArgN}} template action, %w
+	// included since the printf template function cannot wrap errors, unlike %d or %t which do not
+	// call the String or Error method of their argument, e.g., %d prints time.Second as 1000000000
+	PRINTF_SIMPLE_VERBS = "vsw"
+)
+
+// PrintfVerb is a single formatting directive of a fmt format string, e.g., %-10s or %[2]d
+type PrintfVerb struct {
+	Text      string
+	Flags     string
+	Width     string
+	Precision string
+	Verb      rune
+	ArgIndex  int
+	Indexed   bool
+	Offset    int
+
+	// StarArgIndexes are the arguments consumed by the star width and precision, e.g., %*d
+	StarArgIndexes []int
+}
+
+// ParsePrintfVerbs parses a fmt format string and returns its directives in order of appearance.
+// Arguments are numbered from zero and follow the fmt rules for explicit indexes, so the verb
+// following %[2]s consumes the third argument. Escaped percents (%%) are not returned and
+// star widths or precisions are accounted for in the argument numbering.
+func ParsePrintfVerbs(format string) []PrintfVerb {
+	var verbs []PrintfVerb
+
+	argNum := 0
+	for i := 0; i < len(format); {
+		if format[i] != '%' {
+			i++
+			continue
+		}
+
+		start := i
+		i++
+		if i >= len(format) {
+			break
+		}
+
+		if format[i] == '%' {
+			i++
+			continue
+		}
+
+		verb := PrintfVerb{Offset: start}
+
+		for i < len(format) && strings.IndexByte(PRINTF_FLAGS, format[i]) >= 0 {
+			verb.Flags += string(format[i])
+			i++
+		}
+
+		i, argNum, verb.Indexed = parsePrintfArgIndex(format, i, argNum, verb.Indexed)
+		i, argNum, verb.Width = parsePrintfNumber(format, i, argNum)
+		if verb.Width == "*" {
+			verb.StarArgIndexes = append(verb.StarArgIndexes, argNum-1)
+		}
+
+		if i < len(format) && format[i] == '.' {
+			i++
+			i, argNum, verb.Indexed = parsePrintfArgIndex(format, i, argNum, verb.Indexed)
+			var precision string
+			i, argNum, precision = parsePrintfNumber(format, i, argNum)
+			verb.Precision = "." + precision
+			if precision == "*" {
+				verb.StarArgIndexes = append(verb.StarArgIndexes, argNum-1)
+			}
+		}
+
+		i, argNum, verb.Indexed = parsePrintfArgIndex(format, i, argNum, verb.Indexed)
+		if i >= len(format) {
+			break
+		}
+
+		r, size := utf8.DecodeRuneInString(format[i:])
+		i += size
+
+		verb.Verb = r
+		verb.ArgIndex = argNum
+		verb.Text = format[start:i]
+		verbs = append(verbs, verb)
+
+		argNum++
+	}
+
+	return verbs
+}
+
+// IsPrintfString returns true if the string contains at least one fmt formatting directive
+func IsPrintfString(aString string) bool {
+	return len(ParsePrintfVerbs(aString)) > 0
+}
+
+// PrintfArgsCount returns the number of arguments consumed by a fmt format string
+func PrintfArgsCount(format string) int {
+	count := 0
+	for _, verb := range ParsePrintfVerbs(format) {
+		if verb.ArgIndex+1 > count {
+			count = verb.ArgIndex + 1
+		}
+	}
+
+	return count
+}
+
+// ConvertPrintfToTemplatedString converts a fmt format string to a templated string whose args are named
+// after the index of the formatted argument, e.g., "%[2]s: %5.2f%%" becomes "{{.Arg1}}: {{printf "%5.2f" .Arg2}}%%",
+// and returns the sorted indexes of the arguments used. Plain %s, %v and %w verbs become {{.ArgN}} while
+// the other verbs keep their flags, width and precision with the printf template function. Escaped percents
+// are kept since the templated string remains the format of the rewritten call.
+func ConvertPrintfToTemplatedString(format string) (string, []int) {
+	templatedString, argIndexes, _ := convertPrintfToTemplatedString(format, false)
+	return templatedString, argIndexes
+}
+
+// ConvertErrorfToTemplatedString converts a fmt.Errorf format string the same way as ConvertPrintfToTemplatedString
+// but unescapes its percents, since the templated string is the message of an i18n.Errorf call and not a format,
+// e.g., "open %s at 100%%: %w" becomes "open {{.Arg0}} at 100%: {{.Arg1}}", and also returns the indexes of the
+// arguments of the %w verbs in order of appearance, i.e., of the wrapped errors
+func ConvertErrorfToTemplatedString(format string) (string, []int, []int) {
+	return convertPrintfToTemplatedString(format, true)
+}
+
+// ConvertTemplatedToPrintfString converts a templated string back to a fmt format string, the inverse of
+// ConvertPrintfToTemplatedString, e.g., "{{.Arg1}}: {{printf "%5.2f" .Arg2}}" becomes "%v: %5.2f", and returns the
+// names of the templated args in the order of the format args, explicit indexes are used for the args used more
+// than once. With escapePercents, the percents of the text are escaped so that the format prints the text as is,
+// otherwise the text is already a format, e.g., the T(...) format of a fmt.Printf(...) call. The wrappedArgNames
+// are formatted with %w, e.g., the i18n.WrappedArg(...) args of an i18n.Errorf(...) message. An error is returned
+// for the templates which are not only made of args, e.g., with conditions or pipelines.
+func ConvertTemplatedToPrintfString(templatedString string, escapePercents bool, wrappedArgNames ...string) (string, []string, error) {
+	tmpl, err := template.New("").Parse(templatedString)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var (
+		buffer       bytes.Buffer
+		argNames     []string
+		nextArgIndex int
+	)
+
+	writeVerb := func(verb string, names []string) error {
+		var argIndexes []int
+		for _, name := range names {
+			argIndex := slices.Index(argNames, name)
+			if argIndex < 0 {
+				argNames = append(argNames, name)
+				argIndex = len(argNames) - 1
+			}
+			argIndexes = append(argIndexes, argIndex)
+		}
+
+		sequential := true
+		for index, argIndex := range argIndexes {
+			sequential = sequential && argIndex == nextArgIndex+index
+		}
+
+		switch {
+		case sequential:
+			buffer.WriteString(verb)
+		case len(argIndexes) == 1:
+			_, size := utf8.DecodeLastRuneInString(verb)
+			buffer.WriteString(verb[:len(verb)-size] + "[" + strconv.Itoa(argIndexes[0]+1) + "]" + verb[len(verb)-size:])
+		default:
+			return errors.New(i18n.T("the star args of {{.Arg0}} are not in order", map[string]interface{}{"Arg0": verb}))
+		}
+
+		nextArgIndex = argIndexes[len(argIndexes)-1] + 1
+		return nil
+	}
+
+	for _, node := range tmpl.Tree.Root.Nodes {
+		switch node := node.(type) {
+		case *parse.TextNode:
+			text := string(node.Text)
+			if escapePercents {
+				text = strings.Replace(text, "%", "%%", -1)
+			}
+			buffer.WriteString(text)
+		case *parse.ActionNode:
+			verb, names, err := templateActionVerb(node)
+			if err != nil {
+				return "", nil, err
+			}
+
+			if len(names) == 1 && slices.Contains(wrappedArgNames, names[0]) {
+				if verb != "%v" {
+					return "", nil, errors.New(i18n.T("the wrapped error {{.Arg0}} is formatted with {{.Arg1}}", map[string]interface{}{"Arg0": names[0], "Arg1": verb}))
+				}
+				verb = "%w"
+			}
+
+			if err := writeVerb(verb, names); err != nil {
+				return "", nil, err
+			}
+		default:
+			return "", nil, errors.New(i18n.T("the template {{.Arg0}} is not only made of args", map[string]interface{}{"Arg0": node.String()}))
+		}
+	}
+
+	return buffer.String(), argNames, nil
+}
+
+// ValidateTemplate returns an error if the string cannot be parsed as a Go text/template,
+// the same way go-i18n parses messages before executing them
+func ValidateTemplate(aString string) error {
+	_, err := template.New("").Parse(aString)
+	return err
+}
+
+// Private
+
+func convertPrintfToTemplatedString(format string, errorf bool) (string, []int, []int) {
+	verbs := ParsePrintfVerbs(format)
+	if len(verbs) == 0 {
+		return format, nil, nil
+	}
+
+	var buffer bytes.Buffer
+	usedArgs := make(map[int]bool)
+	var wrappedArgIndexes []int
+
+	writeText := func(text string) {
+		if errorf {
+			text = strings.Replace(text, "%%", "%", -1)
+		}
+		buffer.WriteString(text)
+	}
+
+	last := 0
+	for _, verb := range verbs {
+		writeText(format[last:verb.Offset])
+		last = verb.Offset + len(verb.Text)
+
+		if errorf && verb.Verb == 'w' {
+			buffer.WriteString("{{" + printfArgName(verb.ArgIndex) + "}}")
+			usedArgs[verb.ArgIndex] = true
+			wrappedArgIndexes = append(wrappedArgIndexes, verb.ArgIndex)
+			continue
+		}
+
+		if verb.Flags == "" && verb.Width == "" && verb.Precision == "" && strings.ContainsRune(PRINTF_SIMPLE_VERBS, verb.Verb) {
+			buffer.WriteString("{{" + printfArgName(verb.ArgIndex) + "}}")
+			usedArgs[verb.ArgIndex] = true
+			continue
+		}
+
+		directive := "%" + verb.Flags + verb.Width + verb.Precision + string(verb.Verb)
+		buffer.WriteString("{{printf " + strconv.Quote(directive))
+		for _, argIndex := range append(verb.StarArgIndexes, verb.ArgIndex) {
+			buffer.WriteString(" " + printfArgName(argIndex))
+			usedArgs[argIndex] = true
+		}
+		buffer.WriteString("}}")
+	}
+	writeText(format[last:])
+
+	var argIndexes []int
+	for argIndex := range usedArgs {
+		argIndexes = append(argIndexes, argIndex)
+	}
+	sort.Ints(argIndexes)
+
+	return buffer.String(), argIndexes, wrappedArgIndexes
+}
+
+// templateActionVerb returns the fmt verb formatting a {{.Name}} or {{printf "%5.2f" .Name}} action and the names
+// of the args it formats
+func templateActionVerb(actionNode *parse.ActionNode) (string, []string, error) {
+	notAnArgErr := errors.New(i18n.T("the template {{.Arg0}} is not only made of args", map[string]interface{}{"Arg0": actionNode.String()}))
+
+	pipe := actionNode.Pipe
+	if len(pipe.Decl) != 0 || len(pipe.Cmds) != 1 {
+		return "", nil, notAnArgErr
+	}
+
+	args := pipe.Cmds[0].Args
+	if len(args) == 1 {
+		name, ok := templateArgName(args[0])
+		if !ok {
+			return "", nil, notAnArgErr
+		}
+
+		return "%v", []string{name}, nil
+	}
+
+	identifier, ok := args[0].(*parse.IdentifierNode)
+	if !ok || identifier.Ident != "printf" {
+		return "", nil, notAnArgErr
+	}
+
+	directive, ok := args[1].(*parse.StringNode)
+	if !ok {
+		return "", nil, notAnArgErr
+	}
+
+	verbs := ParsePrintfVerbs(directive.Text)
+	if len(verbs) != 1 || verbs[0].Text != directive.Text || verbs[0].Indexed || len(verbs[0].StarArgIndexes)+1 != len(args)-2 {
+		return "", nil, notAnArgErr
+	}
+
+	var names []string
+	for _, arg := range args[2:] {
+		name, ok := templateArgName(arg)
+		if !ok {
+			return "", nil, notAnArgErr
+		}
+		names = append(names, name)
+	}
+
+	return directive.Text, names, nil
+}
+
+func templateArgName(node parse.Node) (string, bool) {
+	field, ok := node.(*parse.FieldNode)
+	if !ok || len(field.Ident) != 1 {
+		return "", false
+	}
+
+	return field.Ident[0], true
+}
+
+func printfArgName(argIndex int) string {
+	return ".Arg" + strconv.Itoa(argIndex)
+}
+
+func parsePrintfArgIndex(format string, i, argNum int, indexed bool) (int, int, bool) {
+	if i >= len(format) || format[i] != '[' {
+		return i, argNum, indexed
+	}
+
+	end := strings.IndexByte(format[i:], ']')
+	if end < 0 {
+		return i, argNum, indexed
+	}
+
+	index, err := strconv.Atoi(format[i+1 : i+end])
+	if err != nil || index < 1 {
+		return i + end + 1, argNum, indexed
+	}
+
+	return i + end + 1, index - 1, true
+}
+
+func parsePrintfNumber(format string, i, argNum int) (int, int, string) {
+	if i < len(format) && format[i] == '*' {
+		return i + 1, argNum + 1, "*"
+	}
+
+	start := i
+	for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+		i++
+	}
+
+	return i, argNum, format[start:i]
+}
@@ -0,0 +1,324 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bufio"
+	"errors"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+const (
+	GITIGNORE_FILENAME = ".gitignore"
+
+	INCLUDE_VENDOR     = "vendor"
+	INCLUDE_TESTDATA   = "testdata"
+	INCLUDE_GENERATED  = "generated"
+	INCLUDE_GITIGNORED = "gitignored"
+)
+
+// INCLUDES are the sources skipped by default which can be included by the source walker
+var INCLUDES = []string{INCLUDE_VENDOR, INCLUDE_TESTDATA, INCLUDE_GENERATED, INCLUDE_GITIGNORED}
+
+// SourceWalker walks the go files of a source tree like the go tool, i.e., it skips the vendor and testdata
+// directories, the generated files and the files excluded by the build constraints of its build context, and also
+// the files ignored by the .gitignore files
+type SourceWalker struct {
+	FS           FS
+	BuildContext build.Context
+	Includes     map[string]bool
+
+	// gitignores are the patterns of the .gitignore files of the directories, loaded once
+	gitignores      map[string][]gitignorePattern
+	gitignoresMutex sync.Mutex
+}
+
+// NewSourceWalker returns the source walker of the options, i.e., the GOOS, GOARCH and tags of the build context,
+// the host ones by default, and the sources to include, a comma separated list of vendor, testdata, generated and
+// gitignored
+func NewSourceWalker(options Options) (*SourceWalker, error) {
+	buildContext := build.Default
+	if options.GoosFlag != "" {
+		buildContext.GOOS = options.GoosFlag
+	}
+	if options.GoarchFlag != "" {
+		buildContext.GOARCH = options.GoarchFlag
+	}
+	buildContext.BuildTags = ParseStringList(options.BuildTagsFlag, ",")
+
+	includes := make(map[string]bool)
+	for _, include := range ParseStringList(options.IncludeFlag, ",") {
+		if !IsInclude(include) {
+			return nil, errors.New(i18n.T("i18n4go: unknown include {{.Arg0}}, one of: vendor, testdata, generated, gitignored", map[string]any{"Arg0": include}))
+		}
+		includes[include] = true
+	}
+
+	return &SourceWalker{
+		FS:           options.FileSystem(),
+		BuildContext: BuildContextFS(options.FileSystem(), buildContext),
+		Includes:     includes,
+		gitignores:   make(map[string][]gitignorePattern),
+	}, nil
+}
+
+// IsInclude returns true if the name is one of the INCLUDES
+func IsInclude(name string) bool {
+	for _, include := range INCLUDES {
+		if name == include {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SkipDir returns true if the subdirectories of a walked directory are skipped, i.e., the hidden, vendor, testdata
+// and ignored directories
+func (walker *SourceWalker) SkipDir(dirName string) bool {
+	name := filepath.Base(dirName)
+	if strings.HasPrefix(name, ".") {
+		return true
+	}
+
+	if (name == INCLUDE_VENDOR && !walker.Includes[INCLUDE_VENDOR]) || (name == INCLUDE_TESTDATA && !walker.Includes[INCLUDE_TESTDATA]) {
+		return true
+	}
+
+	return walker.isGitignored(dirName, true)
+}
+
+// SkipFile returns true if a file of a walked directory is skipped, i.e., an ignored file, and a go file which is
+// generated or excluded by the build constraints
+func (walker *SourceWalker) SkipFile(fileName string) bool {
+	if walker.isGitignored(fileName, false) {
+		return true
+	}
+
+	if !strings.HasSuffix(fileName, ".go") {
+		return false
+	}
+
+	dirName, name := filepath.Split(fileName)
+	if match, err := walker.BuildContext.MatchFile(filepath.Clean(dirName), name); err != nil || !match {
+		return true
+	}
+
+	return !walker.Includes[INCLUDE_GENERATED] && IsGeneratedFileFS(walker.FS, fileName)
+}
+
+// GoFiles returns the sorted go files of a directory, and of its subdirectories if recursive, which are not skipped
+func (walker *SourceWalker) GoFiles(dirName string, recursive bool) []string {
+	var fileNames []string
+	entries, _ := walker.FS.ReadDir(dirName)
+	for _, entry := range entries {
+		fileName := filepath.Join(dirName, entry.Name())
+		if entry.IsDir() {
+			if recursive && !walker.SkipDir(fileName) {
+				fileNames = append(fileNames, walker.GoFiles(fileName, recursive)...)
+			}
+			continue
+		}
+
+		if strings.HasSuffix(entry.Name(), ".go") && !walker.SkipFile(fileName) {
+			fileNames = append(fileNames, fileName)
+		}
+	}
+
+	sort.Strings(fileNames)
+	return fileNames
+}
+
+// IsGeneratedFile returns true if the go file has a `// Code generated ... DO NOT EDIT.` comment before its package
+// clause
+func IsGeneratedFile(fileName string) bool {
+	return IsGeneratedFileFS(OsFS{}, fileName)
+}
+
+// IsGeneratedFileFS is like IsGeneratedFile but uses the file system fsys
+func IsGeneratedFileFS(fsys FS, fileName string) bool {
+	content, err := fsys.ReadFile(fileName)
+	if err != nil {
+		return false
+	}
+
+	astFile, err := parser.ParseFile(token.NewFileSet(), fileName, content, parser.PackageClauseOnly|parser.ParseComments)
+	if err != nil {
+		return false
+	}
+
+	return ast.IsGenerated(astFile)
+}
+
+// Private
+
+// gitignorePattern is a pattern of a .gitignore file, matching the paths relative to the directory of the file
+type gitignorePattern struct {
+	dirName string
+	regexp  *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+func (walker *SourceWalker) isGitignored(fileName string, isDir bool) bool {
+	if walker.Includes[INCLUDE_GITIGNORED] {
+		return false
+	}
+
+	absFileName := AbsPath(walker.FS, fileName)
+
+	ignored := false
+	for _, pattern := range walker.gitignorePatterns(filepath.Dir(absFileName)) {
+		if pattern.dirOnly && !isDir {
+			continue
+		}
+
+		relFileName, err := filepath.Rel(pattern.dirName, absFileName)
+		if err != nil || strings.HasPrefix(relFileName, "..") {
+			continue
+		}
+
+		if pattern.regexp.MatchString(filepath.ToSlash(relFileName)) {
+			ignored = !pattern.negate
+		}
+	}
+
+	return ignored
+}
+
+// gitignorePatterns returns the patterns of the .gitignore files of a directory and of its parents up to the root
+// of its git repository, in the order they apply
+func (walker *SourceWalker) gitignorePatterns(absDirName string) []gitignorePattern {
+	walker.gitignoresMutex.Lock()
+	defer walker.gitignoresMutex.Unlock()
+
+	return walker.loadGitignorePatterns(absDirName)
+}
+
+func (walker *SourceWalker) loadGitignorePatterns(absDirName string) []gitignorePattern {
+	if patterns, ok := walker.gitignores[absDirName]; ok {
+		return patterns
+	}
+
+	var patterns []gitignorePattern
+	parentDirName := filepath.Dir(absDirName)
+	if _, err := walker.FS.Stat(filepath.Join(absDirName, ".git")); err != nil && parentDirName != absDirName {
+		patterns = append(patterns, walker.loadGitignorePatterns(parentDirName)...)
+	}
+	patterns = append(patterns, readGitignoreFile(walker.FS, absDirName)...)
+
+	walker.gitignores[absDirName] = patterns
+	return patterns
+}
+
+func readGitignoreFile(fsys FS, absDirName string) []gitignorePattern {
+	file, err := fsys.Open(filepath.Join(absDirName, GITIGNORE_FILENAME))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var patterns []gitignorePattern
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		pattern := gitignorePattern{dirName: absDirName}
+		if strings.HasPrefix(line, "!") {
+			pattern.negate = true
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, "\\")
+
+		if strings.HasSuffix(line, "/") {
+			pattern.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+
+		// a pattern with a separator is relative to the directory of the .gitignore file, others match at any level
+		prefix := "^(.*/)?"
+		if strings.Contains(line, "/") {
+			prefix = "^"
+			line = strings.TrimPrefix(line, "/")
+		}
+
+		compiledRegexp, err := regexp.Compile(prefix + gitignoreGlobRegexp(line) + "$")
+		if err != nil {
+			continue
+		}
+		pattern.regexp = compiledRegexp
+
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns
+}
+
+// gitignoreGlobRegexp converts a .gitignore glob to a regular expression, i.e., ** matches any directories, * and ?
+// match in a path element and [...] matches a character class
+func gitignoreGlobRegexp(glob string) string {
+	var builder strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if strings.HasPrefix(glob[i:], "**/") {
+				builder.WriteString("(.*/)?")
+				i += 2
+			} else if strings.HasPrefix(glob[i:], "**") {
+				builder.WriteString(".*")
+				i++
+			} else {
+				builder.WriteString("[^/]*")
+			}
+		case '?':
+			builder.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				builder.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			builder.WriteString("[" + class + "]")
+			i += end
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				builder.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,320 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"text/template/parse"
+	"unicode"
+)
+
+// DEFAULT_TEMPLATE_EXTENSIONS are the extensions of the text/template and html/template files
+const DEFAULT_TEMPLATE_EXTENSIONS = ".tmpl,.gotmpl,.gohtml"
+
+// htmlSeparatorRegexp matches the comments, declarations and markup tags of html templates separating their texts
+var htmlSeparatorRegexp = regexp.MustCompile(`(?s)<!--.*?-->|<![^>]*>|` + MARKUP_TAG_REGEXP)
+
+// TemplateString is a string extracted from a Go template, either a literal text or the string argument of a
+// call to a T function, with its position in the template text
+type TemplateString struct {
+	Value  string
+	Offset int
+	Line   int
+	Column int
+}
+
+// IsTemplateFilename returns true if the extension of the file is one of the template extensions
+func IsTemplateFilename(fileName string, extensions []string) bool {
+	for _, extension := range extensions {
+		if extension != "" && strings.HasSuffix(fileName, extension) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsHTMLTemplateFilename returns true for the html templates, e.g., mail.gohtml or mail.html.tmpl
+func IsHTMLTemplateFilename(fileName string) bool {
+	return strings.Contains(strings.ToLower(fileName), "html")
+}
+
+// HasTemplateActions returns true if the text parses as a Go template with at least one action, e.g., {{.Name}}
+func HasTemplateActions(name, text string) bool {
+	treeSet, err := parseTemplate(name, text)
+	if err != nil {
+		return false
+	}
+
+	for _, tree := range treeSet {
+		if tree.Root == nil {
+			continue
+		}
+
+		for _, node := range tree.Root.Nodes {
+			if node.Type() != parse.NodeText {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ExtractTemplateStrings parses the text of a text/template or html/template, including the templates it defines,
+// and returns its literal texts with at least one letter, trimmed and split around the markup tags of html templates,
+// and the string arguments of the calls to the T functions, e.g., {{T "Hello"}} or {{"Hello" | T}}. The adjacent
+// texts and field actions are extracted as one templated string, e.g., Hello {{.Name}}, welcome! at the position
+// of its first node.
+func ExtractTemplateStrings(name, text string, tFuncNames []string, html bool) ([]TemplateString, error) {
+	treeSet, err := parseTemplate(name, text)
+	if err != nil {
+		return nil, err
+	}
+
+	var templateStrings []TemplateString
+	addString := func(value string, offset int) {
+		templateStrings = append(templateStrings, newTemplateString(text, value, offset))
+	}
+
+	visit := func(node parse.Node) {
+		switch x := node.(type) {
+		case *parse.ListNode:
+			if x == nil {
+				return
+			}
+			for _, run := range templateTextRuns(text, x.Nodes) {
+				for _, segment := range templateTextSegments(run.value, html) {
+					addString(segment.Value, run.offset(segment.Offset))
+				}
+			}
+		case *parse.PipeNode:
+			for index, cmd := range x.Cmds {
+				if !isTFuncCommand(cmd, tFuncNames) {
+					continue
+				}
+
+				if len(cmd.Args) > 1 {
+					if stringNode, ok := cmd.Args[1].(*parse.StringNode); ok {
+						addString(stringNode.Text, int(stringNode.Pos))
+					}
+				} else if index > 0 && len(x.Cmds[index-1].Args) == 1 {
+					if stringNode, ok := x.Cmds[index-1].Args[0].(*parse.StringNode); ok {
+						addString(stringNode.Text, int(stringNode.Pos))
+					}
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(treeSet))
+	for treeName := range treeSet {
+		names = append(names, treeName)
+	}
+	slices.Sort(names)
+
+	for _, treeName := range names {
+		walkTemplateNode(treeSet[treeName].Root, visit)
+	}
+
+	slices.SortStableFunc(templateStrings, func(a, b TemplateString) int {
+		return a.Offset - b.Offset
+	})
+
+	return templateStrings, nil
+}
+
+// Private
+
+func parseTemplate(name, text string) (map[string]*parse.Tree, error) {
+	tree := parse.New(name)
+	tree.Mode = parse.SkipFuncCheck
+
+	treeSet := make(map[string]*parse.Tree)
+	_, err := tree.Parse(text, "", "", treeSet)
+	return treeSet, err
+}
+
+func walkTemplateNode(node parse.Node, visit func(parse.Node)) {
+	visit(node)
+
+	switch x := node.(type) {
+	case *parse.ListNode:
+		if x == nil {
+			return
+		}
+		for _, child := range x.Nodes {
+			walkTemplateNode(child, visit)
+		}
+	case *parse.ActionNode:
+		walkTemplateNode(x.Pipe, visit)
+	case *parse.IfNode:
+		walkTemplateBranchNode(&x.BranchNode, visit)
+	case *parse.RangeNode:
+		walkTemplateBranchNode(&x.BranchNode, visit)
+	case *parse.WithNode:
+		walkTemplateBranchNode(&x.BranchNode, visit)
+	case *parse.TemplateNode:
+		if x.Pipe != nil {
+			walkTemplateNode(x.Pipe, visit)
+		}
+	case *parse.PipeNode:
+		if x == nil {
+			return
+		}
+		for _, cmd := range x.Cmds {
+			walkTemplateNode(cmd, visit)
+		}
+	case *parse.CommandNode:
+		for _, arg := range x.Args {
+			walkTemplateNode(arg, visit)
+		}
+	case *parse.ChainNode:
+		walkTemplateNode(x.Node, visit)
+	}
+}
+
+func walkTemplateBranchNode(branchNode *parse.BranchNode, visit func(parse.Node)) {
+	walkTemplateNode(branchNode.Pipe, visit)
+	walkTemplateNode(branchNode.List, visit)
+	if branchNode.ElseList != nil {
+		walkTemplateNode(branchNode.ElseList, visit)
+	}
+}
+
+func isTFuncCommand(cmd *parse.CommandNode, tFuncNames []string) bool {
+	if len(cmd.Args) == 0 {
+		return false
+	}
+
+	identifierNode, ok := cmd.Args[0].(*parse.IdentifierNode)
+	return ok && slices.Contains(tFuncNames, identifierNode.Ident)
+}
+
+// templateTextRun is a sequence of adjacent text nodes and field actions of a template, e.g., Hello {{.Name}},
+// with the offsets in the template of the texts and actions making its value
+type templateTextRun struct {
+	value   string
+	starts  []int
+	offsets []int
+}
+
+// offset returns the offset in the template of an offset of the value of the run
+func (run templateTextRun) offset(valueOffset int) int {
+	index := 0
+	for index+1 < len(run.starts) && run.starts[index+1] <= valueOffset {
+		index++
+	}
+
+	return run.offsets[index] + valueOffset - run.starts[index]
+}
+
+// templateTextRuns returns the runs of adjacent text nodes and field actions of the nodes of a list, the actions
+// keep their template form, e.g., {{.User.Name}}, and the runs without text nodes are not returned
+func templateTextRuns(text string, nodes []parse.Node) []templateTextRun {
+	var runs []templateTextRun
+	var run templateTextRun
+	hasText := false
+
+	endRun := func() {
+		if hasText {
+			runs = append(runs, run)
+		}
+		run, hasText = templateTextRun{}, false
+	}
+
+	for _, node := range nodes {
+		switch x := node.(type) {
+		case *parse.TextNode:
+			run.starts = append(run.starts, len(run.value))
+			run.offsets = append(run.offsets, int(x.Pos))
+			run.value += string(x.Text)
+			hasText = true
+		case *parse.ActionNode:
+			if !isFieldAction(x) {
+				endRun()
+				continue
+			}
+
+			run.starts = append(run.starts, len(run.value))
+			run.offsets = append(run.offsets, strings.LastIndex(text[:x.Pos], "{{"))
+			run.value += x.String()
+		default:
+			endRun()
+		}
+	}
+	endRun()
+
+	return runs
+}
+
+// isFieldAction returns true for the actions only printing a field of the data, e.g., {{.Name}} or {{.User.Name}}
+func isFieldAction(actionNode *parse.ActionNode) bool {
+	pipe := actionNode.Pipe
+	if len(pipe.Decl) != 0 || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+
+	_, ok := pipe.Cmds[0].Args[0].(*parse.FieldNode)
+	return ok
+}
+
+// templateTextSegments returns the trimmed segments of a text with at least one letter out of its actions, split
+// around the markup tags of html templates and without the content of their script and style elements
+func templateTextSegments(text string, html bool) []TemplateString {
+	var segments []TemplateString
+	addSegment := func(start, end int) {
+		segment := text[start:end]
+		value := strings.TrimSpace(segment)
+		if strings.IndexFunc(templateActionRegexp.ReplaceAllString(value, ""), unicode.IsLetter) >= 0 {
+			segments = append(segments, TemplateString{Value: value, Offset: start + strings.Index(segment, value)})
+		}
+	}
+
+	if !html {
+		addSegment(0, len(text))
+		return segments
+	}
+
+	start := 0
+	skipped := false
+	for _, match := range htmlSeparatorRegexp.FindAllStringSubmatchIndex(text, -1) {
+		if !skipped {
+			addSegment(start, match[0])
+		}
+		start = match[1]
+
+		isClosingTag := match[2] >= 0 && match[3] > match[2]
+		tagName := ""
+		if match[4] >= 0 {
+			tagName = strings.ToLower(text[match[4]:match[5]])
+		}
+		skipped = !isClosingTag && (tagName == "script" || tagName == "style")
+	}
+
+	if !skipped {
+		addSegment(start, len(text))
+	}
+
+	return segments
+}
+
+func newTemplateString(text string, value string, offset int) TemplateString {
+	line := 1 + strings.Count(text[:offset], "\n")
+	column := offset - strings.LastIndex(text[:offset], "\n")
+	return TemplateString{Value: value, Offset: offset, Line: line, Column: column}
+}
@@ -0,0 +1,266 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+// TypeError is a type error of a package, located by its position in the package so that the errors of a file can
+// be matched once the file is rewritten, see typeErrorPosition
+type TypeError struct {
+	Msg      string
+	Position string
+}
+
+// TypeChecker type checks the packages of a run, the export data of their imports is listed once and each import is
+// imported once for all the packages
+type TypeChecker struct {
+	// exportFiles maps the import paths to the files of their export data, an empty file for the packages listed
+	// without export data
+	exportFiles map[string]string
+
+	// importer imports the packages from their export data
+	importer types.Importer
+}
+
+// NewTypeChecker returns a type checker with no export data listed yet
+func NewTypeChecker() *TypeChecker {
+	typeChecker := &TypeChecker{exportFiles: make(map[string]string)}
+	typeChecker.importer = importer.ForCompiler(token.NewFileSet(), "gc", typeChecker.lookupExportFile)
+
+	return typeChecker
+}
+
+// ParsePackageFiles parses the other Go files of the package of fileName, honoring the build constraints.
+// No files are returned if the directory of fileName does not contain a single package.
+func ParsePackageFiles(fileSet *token.FileSet, fileName string) ([]*ast.File, error) {
+	return ParsePackageFilesFS(OsFS{}, fileSet, fileName)
+}
+
+// ParsePackageFilesFS is like ParsePackageFiles but uses the file system fsys
+func ParsePackageFilesFS(fsys FS, fileSet *token.FileSet, fileName string) ([]*ast.File, error) {
+	dirName := filepath.Dir(fileName)
+
+	buildContext := BuildContextFS(fsys, build.Default)
+	pkg, err := buildContext.ImportDir(dirName, build.ImportMode(0))
+	if err != nil {
+		return nil, nil
+	}
+
+	var astFiles []*ast.File
+	for _, goFile := range pkg.GoFiles {
+		if goFile == filepath.Base(fileName) {
+			continue
+		}
+
+		content, err := fsys.ReadFile(filepath.Join(dirName, goFile))
+		if err != nil {
+			return nil, NewIOError(err)
+		}
+
+		astFile, err := parser.ParseFile(fileSet, filepath.Join(dirName, goFile), content, parser.ParseComments)
+		if err != nil {
+			return nil, NewParseError(err)
+		}
+		astFiles = append(astFiles, astFile)
+	}
+
+	return astFiles, nil
+}
+
+// TypeCheckFiles type checks the files of a package and returns its type info with the type errors found, the type
+// checking does not stop at the first error so that the info is as complete as possible. The export data of the
+// imports of the files is listed with a single go list run in the directory of the package.
+func (tc *TypeChecker) TypeCheckFiles(fileSet *token.FileSet, astFiles []*ast.File) (*types.Package, *types.Info, []TypeError) {
+	tc.loadExportFiles(fileSet, astFiles)
+
+	var typeErrors []TypeError
+	config := types.Config{
+		Importer: tc.importer,
+		Error: func(err error) {
+			if typeError, ok := err.(types.Error); ok {
+				typeErrors = append(typeErrors, TypeError{Msg: typeError.Msg, Position: typeErrorPosition(fileSet, astFiles, typeError.Pos)})
+			} else {
+				typeErrors = append(typeErrors, TypeError{Msg: err.Error()})
+			}
+		},
+	}
+
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+
+	pkg, _ := config.Check(astFiles[0].Name.Name, fileSet, astFiles, info)
+
+	return pkg, info, typeErrors
+}
+
+// NewTypeErrors returns the type errors whose message and position are not in baseErrors, e.g., introduced by
+// rewriting a file, an error found more often at the same position than in baseErrors is also returned
+func NewTypeErrors(baseErrors, typeErrors []TypeError) []TypeError {
+	counts := make(map[TypeError]int)
+	for _, baseError := range baseErrors {
+		counts[baseError]++
+	}
+
+	var newErrors []TypeError
+	for _, typeError := range typeErrors {
+		if counts[typeError] > 0 {
+			counts[typeError]--
+			continue
+		}
+		newErrors = append(newErrors, typeError)
+	}
+
+	return newErrors
+}
+
+// Private
+
+// typeErrorPosition returns the file of the error with the top-level declaration enclosing the error and the line of
+// the error in the declaration, e.g., main.go:func (*Cmd).Run#0:+3, rather than its line and column in the file, which
+// change when the literals of the file are wrapped or an import is added
+func typeErrorPosition(fileSet *token.FileSet, astFiles []*ast.File, pos token.Pos) string {
+	position := fileSet.Position(pos)
+	for _, astFile := range astFiles {
+		if pos < astFile.FileStart || pos > astFile.FileEnd {
+			continue
+		}
+
+		declCounts := make(map[string]int)
+		for _, decl := range astFile.Decls {
+			name := declName(decl)
+			index := declCounts[name]
+			declCounts[name]++
+
+			if pos < decl.Pos() || pos > decl.End() {
+				continue
+			}
+
+			// the imports are sorted once an import is added, the import errors name their import path
+			if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+				return position.Filename + ":" + name
+			}
+
+			line := position.Line - fileSet.Position(decl.Pos()).Line
+			return position.Filename + ":" + name + "#" + strconv.Itoa(index) + ":+" + strconv.Itoa(line)
+		}
+	}
+
+	return position.String()
+}
+
+// declName names a top-level declaration, e.g., func (*Cmd).Run or var usage, several declarations may have the same
+// name, e.g., the init funcs or the blank vars
+func declName(decl ast.Decl) string {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if decl.Recv != nil && len(decl.Recv.List) > 0 {
+			return "func (" + types.ExprString(decl.Recv.List[0].Type) + ")." + decl.Name.Name
+		}
+		return "func " + decl.Name.Name
+	case *ast.GenDecl:
+		if len(decl.Specs) == 0 {
+			return decl.Tok.String()
+		}
+
+		switch spec := decl.Specs[0].(type) {
+		case *ast.ImportSpec:
+			return decl.Tok.String()
+		case *ast.TypeSpec:
+			return decl.Tok.String() + " " + spec.Name.Name
+		case *ast.ValueSpec:
+			return decl.Tok.String() + " " + spec.Names[0].Name
+		}
+	}
+
+	return ""
+}
+
+// loadExportFiles lists the export data of the imports of the files not listed yet, and of their dependencies, with
+// go list in the directory of the files, the imports which cannot be listed are not imported, e.g., outside of a module.
+// The imports are only recorded once go list succeeds so that they are listed again for the next files otherwise.
+func (tc *TypeChecker) loadExportFiles(fileSet *token.FileSet, astFiles []*ast.File) {
+	var (
+		dirName     string
+		importPaths []string
+	)
+	for _, astFile := range astFiles {
+		if fileName := fileSet.Position(astFile.Package).Filename; fileName != "" {
+			dirName = filepath.Dir(fileName)
+		}
+
+		for _, importSpec := range astFile.Imports {
+			importPath, err := strconv.Unquote(importSpec.Path.Value)
+			if _, ok := tc.exportFiles[importPath]; ok || err != nil || importPath == "C" || importPath == "unsafe" || slices.Contains(importPaths, importPath) {
+				continue
+			}
+			importPaths = append(importPaths, importPath)
+		}
+	}
+
+	if len(importPaths) == 0 {
+		return
+	}
+
+	command := exec.Command("go", append([]string{"list", "-e", "-export", "-deps", "-f", "{{.ImportPath}}={{.Export}}"}, importPaths...)...)
+	if fileInfo, err := os.Stat(dirName); err == nil && fileInfo.IsDir() {
+		command.Dir = dirName
+	}
+
+	output, err := command.Output()
+	if err != nil {
+		return
+	}
+
+	for _, importPath := range importPaths {
+		tc.exportFiles[importPath] = ""
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		importPath, exportFile, ok := strings.Cut(line, "=")
+		if ok && exportFile != "" {
+			tc.exportFiles[importPath] = exportFile
+		}
+	}
+}
+
+// lookupExportFile opens the export data of a package listed by loadExportFiles
+func (tc *TypeChecker) lookupExportFile(importPath string) (io.ReadCloser, error) {
+	exportFile := tc.exportFiles[importPath]
+	if exportFile == "" {
+		return nil, errors.New(i18n.T("i18n4go: no export data found for {{.Arg0}}", map[string]any{"Arg0": importPath}))
+	}
+
+	return os.Open(exportFile)
+}
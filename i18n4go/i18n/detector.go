@@ -0,0 +1,133 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/pivotal-cf-experimental/jibber_jabber"
+)
+
+// ErrLocaleNotDetected is returned by the detectors when no locale can be found
+var ErrLocaleNotDetected = errors.New("i18n4go: could not detect the locale")
+
+// DEFAULT_LOCALE_ENV_VARS are the environment variables read by the EnvDetector, in order of precedence
+var DEFAULT_LOCALE_ENV_VARS = []string{"LC_ALL", "LC_MESSAGES", "LANG"}
+
+// Detector detects the user locale, e.g., en-US, and language, e.g., en, passed to Init
+type Detector interface {
+	DetectIETF() (string, error)
+	DetectLanguage() (string, error)
+}
+
+// JibberJabberDetector detects the locale of the operating system, it is the default detector of Init
+type JibberJabberDetector struct{}
+
+func NewJibberJabberDetector() *JibberJabberDetector {
+	return &JibberJabberDetector{}
+}
+
+func (detector *JibberJabberDetector) DetectIETF() (string, error) {
+	return jibber_jabber.DetectIETF()
+}
+
+func (detector *JibberJabberDetector) DetectLanguage() (string, error) {
+	return jibber_jabber.DetectLanguage()
+}
+
+// EnvDetector detects the locale from the first set environment variable, e.g., LANG=fr_FR.UTF-8
+type EnvDetector struct {
+	EnvVars []string
+}
+
+// NewEnvDetector returns a detector reading the envVars, or LC_ALL, LC_MESSAGES and LANG if none are given
+func NewEnvDetector(envVars ...string) *EnvDetector {
+	if len(envVars) == 0 {
+		envVars = DEFAULT_LOCALE_ENV_VARS
+	}
+
+	return &EnvDetector{EnvVars: envVars}
+}
+
+func (detector *EnvDetector) DetectIETF() (string, error) {
+	for _, envVar := range detector.EnvVars {
+		locale := parsePosixLocale(os.Getenv(envVar))
+		if locale != "" {
+			return toIETF(locale), nil
+		}
+	}
+
+	return "", ErrLocaleNotDetected
+}
+
+func (detector *EnvDetector) DetectLanguage() (string, error) {
+	locale, err := detector.DetectIETF()
+	if err != nil {
+		return "", err
+	}
+
+	return toLanguage(locale), nil
+}
+
+// FixedDetector always detects the same locale, e.g., in tests or when the locale is configured by the application
+type FixedDetector struct {
+	Locale string
+}
+
+func NewFixedDetector(locale string) *FixedDetector {
+	return &FixedDetector{Locale: locale}
+}
+
+func (detector *FixedDetector) DetectIETF() (string, error) {
+	if detector.Locale == "" {
+		return "", ErrLocaleNotDetected
+	}
+
+	return toIETF(detector.Locale), nil
+}
+
+func (detector *FixedDetector) DetectLanguage() (string, error) {
+	locale, err := detector.DetectIETF()
+	if err != nil {
+		return "", err
+	}
+
+	return toLanguage(locale), nil
+}
+
+// Private
+
+// parsePosixLocale returns the locale of a POSIX locale name, e.g., fr_FR for fr_FR.UTF-8@euro, or an empty
+// string for the C and POSIX locales which do not specify a language
+func parsePosixLocale(posixLocale string) string {
+	locale := strings.SplitN(posixLocale, ".", 2)[0]
+	locale = strings.SplitN(locale, "@", 2)[0]
+
+	if locale == "C" || locale == "POSIX" {
+		return ""
+	}
+
+	return locale
+}
+
+func toIETF(locale string) string {
+	return strings.Replace(locale, "_", "-", -1)
+}
+
+func toLanguage(locale string) string {
+	return strings.SplitN(locale, "-", 2)[0]
+}
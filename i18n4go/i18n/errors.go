@@ -0,0 +1,107 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Error is an error with a translated message which wraps errors, so that they can still be inspected
+// with errors.Is and errors.As once the message is translated
+type Error struct {
+	Message string
+	Wrapped []error
+}
+
+// NewError returns an Error with the translated message wrapping the errors
+func NewError(message string, wrapped ...error) error {
+	var errs []error
+	for _, err := range wrapped {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return &Error{Message: message, Wrapped: errs}
+}
+
+// Errorf is the translatable version of fmt.Errorf, e.g., Errorf(T("cannot open {{.Arg0}}: {{.Arg1}}",
+// map[string]interface{}{"Arg0": name, "Arg1": WrappedArg(0)}), err), it returns an Error whose message is the translated
+// message with each WrappedArg replaced by the message of the wrapped error at its index. The other args, e.g., a file
+// name containing %w, are rendered as is by the template.
+func Errorf(translatedMessage string, wrapped ...error) error {
+	message := wrappedArgRegexp.ReplaceAllStringFunc(translatedMessage, func(placeholder string) string {
+		index, _ := strconv.Atoi(wrappedArgRegexp.FindStringSubmatch(placeholder)[1])
+		return wrappedErrorMessage(wrapped, index)
+	})
+
+	return NewError(message, wrapped...)
+}
+
+// WrappedArg returns the template arg of the wrapped error at index of an Errorf call, which renders as a placeholder
+// that the other args cannot contain, so that the wrapped errors are only substituted where the message wraps them
+func WrappedArg(index int) fmt.Stringer {
+	return wrappedArg(index)
+}
+
+func (err *Error) Error() string {
+	return err.Message
+}
+
+// Unwrap returns the wrapped errors, see errors.Is and errors.As
+func (err *Error) Unwrap() []error {
+	return err.Wrapped
+}
+
+// Private
+
+var (
+	// wrappedArgNonce makes the placeholders of the wrapped errors unique to the process
+	wrappedArgNonce  = newWrappedArgNonce()
+	wrappedArgRegexp = regexp.MustCompile("\x00" + wrappedArgNonce + ":([0-9]+)\x00")
+)
+
+type wrappedArg int
+
+func (arg wrappedArg) String() string {
+	return "\x00" + wrappedArgNonce + ":" + strconv.Itoa(int(arg)) + "\x00"
+}
+
+func newWrappedArgNonce() string {
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+
+	return hex.EncodeToString(nonce)
+}
+
+// wrappedErrorMessage formats the wrapped error at index the same way as fmt.Errorf
+func wrappedErrorMessage(wrapped []error, index int) string {
+	if index >= len(wrapped) {
+		return "%!w(MISSING)"
+	}
+
+	if wrapped[index] == nil {
+		return "%!w(<nil>)"
+	}
+
+	return wrapped[index].Error()
+}
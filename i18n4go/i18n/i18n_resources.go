@@ -67,6 +67,10 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "\"{{.Arg0}}\" exists in {{.Arg1}}, but not in {{.Arg2}}\n",
       "translation": "\"{{.Arg0}}\" exists in {{.Arg1}}, but not in {{.Arg2}}\n"
    },
+   {
+      "id": "\"{{.Arg0}}\" in {{.Arg1}} violates the glossary for term \"{{.Arg2}}\": {{.Arg3}}\n",
+      "translation": "\"{{.Arg0}}\" in {{.Arg1}} violates the glossary for term \"{{.Arg2}}\": {{.Arg3}}\n"
+   },
    {
       "id": "Add, update, or remove translation keys from source files and resources files",
       "translation": "Add, update, or remove translation keys from source files and resources files"
@@ -95,6 +99,10 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "Build Date:   {{.Arg0}}\n",
       "translation": "Build Date:   {{.Arg0}}\n"
    },
+   {
+      "id": "COVERAGE",
+      "translation": "COVERAGE"
+   },
    {
       "id": "Canceling fixup",
       "translation": "Canceling fixup"
@@ -151,10 +159,18 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "ERROR opening file",
       "translation": "ERROR opening file"
    },
+   {
+      "id": "EXTRA",
+      "translation": "EXTRA"
+   },
    {
       "id": "Error when inspecting go file: ",
       "translation": "Error when inspecting go file: "
    },
+   {
+      "id": "Error when migrating the keys of go file: ",
+      "translation": "Error when migrating the keys of go file: "
+   },
    {
       "id": "Excluding regexps in file:",
       "translation": "Excluding regexps in file:"
@@ -164,8 +180,8 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "translation": "Excluding strings in file:"
    },
    {
-      "id": "Extract the translation strings from go source files",
-      "translation": "Extract the translation strings from go source files"
+      "id": "Extract the translation strings from go source files and templates",
+      "translation": "Extract the translation strings from go source files and templates"
    },
    {
       "id": "Extracted total of {{.Arg0}} strings\n\n",
@@ -195,10 +211,22 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "General purpose tool for i18n",
       "translation": "General purpose tool for i18n"
    },
+   {
+      "id": "Generates a Go package with one function per message of a source language resource file, whose parameters are the template args of the message, and a count for the plural messages, so that the IDs and args are checked at compile time",
+      "translation": "Generates a Go package with one function per message of a source language resource file, whose parameters are the template args of the message, and a count for the plural messages, so that the IDs and args are checked at compile time"
+   },
+   {
+      "id": "Generates typed Go functions translating the messages of a resource file",
+      "translation": "Generates typed Go functions translating the messages of a resource file"
+   },
    {
       "id": "Git Revision: {{.Arg0}}\n",
       "translation": "Git Revision: {{.Arg0}}\n"
    },
+   {
+      "id": "INVALID",
+      "translation": "INVALID"
+   },
    {
       "id": "Invalid response.",
       "translation": "Invalid response."
@@ -207,6 +235,10 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "Is the string \"%s\" a new or updated string? [new/upd]\n",
       "translation": "Is the string \"%s\" a new or updated string? [new/upd]\n"
    },
+   {
+      "id": "LOCALE",
+      "translation": "LOCALE"
+   },
    {
       "id": "Loaded {{.Arg0}} excluded regexps",
       "translation": "Loaded {{.Arg0}} excluded regexps"
@@ -219,10 +251,26 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "Loaded {{.Arg0}} substring regexps",
       "translation": "Loaded {{.Arg0}} substring regexps"
    },
+   {
+      "id": "MISSING",
+      "translation": "MISSING"
+   },
    {
       "id": "Merge translation strings",
       "translation": "Merge translation strings"
    },
+   {
+      "id": "Migrated {{.Arg0}} IDs to {{.Arg1}} keys",
+      "translation": "Migrated {{.Arg0}} IDs to {{.Arg1}} keys"
+   },
+   {
+      "id": "Migrating the keys of the go file:",
+      "translation": "Migrating the keys of the go file:"
+   },
+   {
+      "id": "Migrating the keys of the translation file:",
+      "translation": "Migrating the keys of the translation file:"
+   },
    {
       "id": "Missing Strings!",
       "translation": "Missing Strings!"
@@ -239,10 +287,22 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "OK",
       "translation": "OK"
    },
+   {
+      "id": "PACKAGE",
+      "translation": "PACKAGE"
+   },
    {
       "id": "Removing these strings from the %s translation file:\n",
       "translation": "Removing these strings from the %s translation file:\n"
    },
+   {
+      "id": "Replace the T(...) calls of go source files by their source language string or an equivalent fmt.Sprintf(...) call, the inverse of rewrite-package, and remove the IDs which are not used anymore from the translation files",
+      "translation": "Replace the T(...) calls of go source files by their source language string or an equivalent fmt.Sprintf(...) call, the inverse of rewrite-package, and remove the IDs which are not used anymore from the translation files"
+   },
+   {
+      "id": "Reports the translation coverage of each locale",
+      "translation": "Reports the translation coverage of each locale"
+   },
    {
       "id": "Rewrite translated packages from go source files",
       "translation": "Rewrite translated packages from go source files"
@@ -271,6 +331,14 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "Strings don't match",
       "translation": "Strings don't match"
    },
+   {
+      "id": "TOTAL",
+      "translation": "TOTAL"
+   },
+   {
+      "id": "TRANSLATED",
+      "translation": "TRANSLATED"
+   },
    {
       "id": "Total extracted strings:",
       "translation": "Total extracted strings:"
@@ -287,14 +355,34 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "Total time:",
       "translation": "Total time:"
    },
+   {
+      "id": "Total unwrapped strings:",
+      "translation": "Total unwrapped strings:"
+   },
+   {
+      "id": "Translation coverage",
+      "translation": "Translation coverage"
+   },
+   {
+      "id": "Translations violate the glossary",
+      "translation": "Translations violate the glossary"
+   },
    {
       "id": "UNDER",
       "translation": "UNDER"
    },
+   {
+      "id": "UNTRANSLATED",
+      "translation": "UNTRANSLATED"
+   },
    {
       "id": "Unable to find english translation files",
       "translation": "Unable to find english translation files"
    },
+   {
+      "id": "Unwrap the T(...) calls of go source files back to string literals",
+      "translation": "Unwrap the T(...) calls of go source files back to string literals"
+   },
    {
       "id": "Updating the following strings from the %s translation file:\n",
       "translation": "Updating the following strings from the %s translation file:\n"
@@ -315,6 +403,10 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "WARNING No capturing group found in {{.Arg0}}",
       "translation": "WARNING No capturing group found in {{.Arg0}}"
    },
+   {
+      "id": "WARNING cannot migrate the ID of the T(...) call at {{.Arg0}}, it is not a string literal",
+      "translation": "WARNING cannot migrate the ID of the T(...) call at {{.Arg0}}, it is not a string literal"
+   },
    {
       "id": "WARNING compiling ignore-regexp:",
       "translation": "WARNING compiling ignore-regexp:"
@@ -335,14 +427,78 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "WARNING: fail to compile ignore-regexp:",
       "translation": "WARNING: fail to compile ignore-regexp:"
    },
+   {
+      "id": "WARNING: skipping the ID {{.Arg0}} whose printf verbs cannot be generated as parameters, use templated args instead",
+      "translation": "WARNING: skipping the ID {{.Arg0}} whose printf verbs cannot be generated as parameters, use templated args instead"
+   },
    {
       "id": "[optional] a comma separated list of target files for different languages to compare,  e.g., \\\"en, en_US, fr_FR, es\\\"\t                                                                  if not specified then the languages flag is used to find target files in same directory as source",
       "translation": "[optional] a comma separated list of target files for different languages to compare,  e.g., \\\"en, en_US, fr_FR, es\\\"\t                                                                  if not specified then the languages flag is used to find target files in same directory as source"
    },
+   {
+      "id": "[optional] a comma separated list of the build tags of the build constraints of the go files",
+      "translation": "[optional] a comma separated list of the build tags of the build constraints of the go files"
+   },
+   {
+      "id": "[optional] a comma separated list of the extensions of the text/template and html/template files whose strings are extracted with the go files of a directory",
+      "translation": "[optional] a comma separated list of the extensions of the text/template and html/template files whose strings are extracted with the go files of a directory"
+   },
+   {
+      "id": "[optional] a comma separated list of the names of the functions translating strings, whose calls are not rewritten again",
+      "translation": "[optional] a comma separated list of the names of the functions translating strings, whose calls are not rewritten again"
+   },
+   {
+      "id": "[optional] a comma separated list of the names of the functions translating strings, whose calls are unwrapped",
+      "translation": "[optional] a comma separated list of the names of the functions translating strings, whose calls are unwrapped"
+   },
+   {
+      "id": "[optional] a comma separated list of the names of the template functions translating strings, whose string args are extracted from the templates",
+      "translation": "[optional] a comma separated list of the names of the template functions translating strings, whose string args are extracted from the templates"
+   },
+   {
+      "id": "[optional] a comma separated list of the sources skipped by default to include, one of: vendor, testdata, generated, gitignored",
+      "translation": "[optional] a comma separated list of the sources skipped by default to include, one of: vendor, testdata, generated, gitignored"
+   },
+   {
+      "id": "[optional] a comma separated list of translation checks to skip, one of: template-args, printf, template-syntax, markup, whitespace, newlines, punctuation, ansi, glossary",
+      "translation": "[optional] a comma separated list of translation checks to skip, one of: template-args, printf, template-syntax, markup, whitespace, newlines, punctuation, ansi, glossary"
+   },
    {
       "id": "[optional] create a *.extracted.json file with metadata such as: filename, directory, and positions of the strings in source file",
       "translation": "[optional] create a *.extracted.json file with metadata such as: filename, directory, and positions of the strings in source file"
    },
+   {
+      "id": "[optional] migrate the source text IDs of the translation files of all locales and of the source files to keys of the key format",
+      "translation": "[optional] migrate the source text IDs of the translation files of all locales and of the source files to keys of the key format"
+   },
+   {
+      "id": "[optional] output directory for the unwrapped files, the original files are overwritten if not specified",
+      "translation": "[optional] output directory for the unwrapped files, the original files are overwritten if not specified"
+   },
+   {
+      "id": "[optional] print the generated code instead of writing the i18n_messages.go file",
+      "translation": "[optional] print the generated code instead of writing the i18n_messages.go file"
+   },
+   {
+      "id": "[optional] regenerate the i18n_init.go files which already exist",
+      "translation": "[optional] regenerate the i18n_init.go files which already exist"
+   },
+   {
+      "id": "[optional] the GOARCH of the build constraints of the go files, defaults to the host one",
+      "translation": "[optional] the GOARCH of the build constraints of the go files, defaults to the host one"
+   },
+   {
+      "id": "[optional] the GOOS of the build constraints of the go files, defaults to the host one",
+      "translation": "[optional] the GOOS of the build constraints of the go files, defaults to the host one"
+   },
+   {
+      "id": "[optional] the directory containing the translation files, defaults to current directory",
+      "translation": "[optional] the directory containing the translation files, defaults to current directory"
+   },
+   {
+      "id": "[optional] the directory of the cache of the strings of the go files, so that only the files which changed are parsed again",
+      "translation": "[optional] the directory of the cache of the strings of the go files, so that only the files which changed are parsed again"
+   },
    {
       "id": "[optional] the directory where the source go files are located, defaults to current directory",
       "translation": "[optional] the directory where the source go files are located, defaults to current directory"
@@ -355,6 +511,42 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "[optional] the excluded JSON file name, all strings there will be excluded",
       "translation": "[optional] the excluded JSON file name, all strings there will be excluded"
    },
+   {
+      "id": "[optional] the format of the IDs of the strings, one of: text, slug, hash, the slug and hash IDs are scoped by package, e.g., greetings.hello_world",
+      "translation": "[optional] the format of the IDs of the strings, one of: text, slug, hash, the slug and hash IDs are scoped by package, e.g., greetings.hello_world"
+   },
+   {
+      "id": "[optional] the format of the logged messages, one of: text, json",
+      "translation": "[optional] the format of the logged messages, one of: text, json"
+   },
+   {
+      "id": "[optional] the format of the report, one of: table, json, html",
+      "translation": "[optional] the format of the report, one of: table, json, html"
+   },
+   {
+      "id": "[optional] the glossary JSON file with the terms that must not be translated or must be translated with specific terms per locale",
+      "translation": "[optional] the glossary JSON file with the terms that must not be translated or must be translated with specific terms per locale"
+   },
+   {
+      "id": "[optional] the level of the logged messages, one of: error, warn, info, debug, overrides -v and --quiet",
+      "translation": "[optional] the level of the logged messages, one of: error, warn, info, debug, overrides -v and --quiet"
+   },
+   {
+      "id": "[optional] the name of the generated package, defaults to the name of the output directory",
+      "translation": "[optional] the name of the generated package, defaults to the name of the output directory"
+   },
+   {
+      "id": "[optional] the number of files inspected concurrently, defaults to the number of CPUs",
+      "translation": "[optional] the number of files inspected concurrently, defaults to the number of CPUs"
+   },
+   {
+      "id": "[optional] the output directory of the generated package, defaults to current directory",
+      "translation": "[optional] the output directory of the generated package, defaults to current directory"
+   },
+   {
+      "id": "[optional] the output directory where the stats report file will be placed, defaults to printing the report",
+      "translation": "[optional] the output directory where the stats report file will be placed, defaults to printing the report"
+   },
    {
       "id": "[optional] the path to a file containing the template snippet for the code that is used for go-i18n initialization",
       "translation": "[optional] the path to a file containing the template snippet for the code that is used for go-i18n initialization"
@@ -387,10 +579,22 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "a comma separated list of target files for different languages to compare,  e.g., \\\"en, en_US, fr_FR, es\\\"\t                                                                  if not specified then the languages flag is used to find target files in same directory as source",
       "translation": "a comma separated list of target files for different languages to compare,  e.g., \\\"en, en_US, fr_FR, es\\\"\t                                                                  if not specified then the languages flag is used to find target files in same directory as source"
    },
+   {
+      "id": "a comma separated list of the functions whose string arguments must be translated, e.g., fmt.Println,(*log.Logger).Printf",
+      "translation": "a comma separated list of the functions whose string arguments must be translated, e.g., fmt.Println,(*log.Logger).Printf"
+   },
+   {
+      "id": "a comma separated list of the names of the functions translating strings, e.g., T,t",
+      "translation": "a comma separated list of the names of the functions translating strings, e.g., T,t"
+   },
    {
       "id": "a comma separated list of valid languages with optional territory, e.g., \"en, en_US, fr_FR, es\"",
       "translation": "a comma separated list of valid languages with optional territory, e.g., \"en, en_US, fr_FR, es\""
    },
+   {
+      "id": "a constant expression is required",
+      "translation": "a constant expression is required"
+   },
    {
       "id": "a directory with the extracted JSON files, using -output-match-package with -extract-strings this directory should match the input files package name",
       "translation": "a directory with the extracted JSON files, using -output-match-package with -extract-strings this directory should match the input files package name"
@@ -407,10 +611,26 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "cowardly refusing to translate the strings in test file:",
       "translation": "cowardly refusing to translate the strings in test file:"
    },
+   {
+      "id": "extra printf args: {{.Arg0}}",
+      "translation": "extra printf args: {{.Arg0}}"
+   },
+   {
+      "id": "extra template args: {{.Arg0}}",
+      "translation": "extra template args: {{.Arg0}}"
+   },
+   {
+      "id": "forbidden term \"{{.Arg0}}\" used for locale {{.Arg1}}",
+      "translation": "forbidden term \"{{.Arg0}}\" used for locale {{.Arg1}}"
+   },
    {
       "id": "generate standard .po file for translation",
       "translation": "generate standard .po file for translation"
    },
+   {
+      "id": "generated files are created in directory to match the import path of the package, resolved with the go.mod file of its module",
+      "translation": "generated files are created in directory to match the import path of the package, resolved with the go.mod file of its module"
+   },
    {
       "id": "generated files are created in directory to match the package name",
       "translation": "generated files are created in directory to match the package name"
@@ -419,10 +639,22 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "generated files are created in the specified output directory",
       "translation": "generated files are created in the specified output directory"
    },
+   {
+      "id": "glossary term \"{{.Arg0}}\" must be translated as \"{{.Arg1}}\" for locale {{.Arg2}}",
+      "translation": "glossary term \"{{.Arg0}}\" must be translated as \"{{.Arg1}}\" for locale {{.Arg2}}"
+   },
+   {
+      "id": "glossary term \"{{.Arg0}}\" must not be translated for locale {{.Arg1}}",
+      "translation": "glossary term \"{{.Arg0}}\" must not be translated for locale {{.Arg1}}"
+   },
    {
       "id": "i18n4go: Could not checkup, err:",
       "translation": "i18n4go: Could not checkup, err:"
    },
+   {
+      "id": "i18n4go: Could not compute the translation stats, err:",
+      "translation": "i18n4go: Could not compute the translation stats, err:"
+   },
    {
       "id": "i18n4go: Could not create translation files, err:",
       "translation": "i18n4go: Could not create translation files, err:"
@@ -443,10 +675,18 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "i18n4go: Could not show missing strings, err:",
       "translation": "i18n4go: Could not show missing strings, err:"
    },
+   {
+      "id": "i18n4go: Could not successfully generate the message functions, err:",
+      "translation": "i18n4go: Could not successfully generate the message functions, err:"
+   },
    {
       "id": "i18n4go: Could not successfully rewrite package, err:",
       "translation": "i18n4go: Could not successfully rewrite package, err:"
    },
+   {
+      "id": "i18n4go: Could not successfully unwrap package, err:",
+      "translation": "i18n4go: Could not successfully unwrap package, err:"
+   },
    {
       "id": "i18n4go: Could not verify strings for input filename, err:",
       "translation": "i18n4go: Could not verify strings for input filename, err:"
@@ -483,6 +723,14 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "i18n4go: Error input file: {{.Arg0}} is empty",
       "translation": "i18n4go: Error input file: {{.Arg0}} is empty"
    },
+   {
+      "id": "i18n4go: Error loading the glossary file:",
+      "translation": "i18n4go: Error loading the glossary file:"
+   },
+   {
+      "id": "i18n4go: Error loading the i18n strings from file:",
+      "translation": "i18n4go: Error loading the i18n strings from file:"
+   },
    {
       "id": "i18n4go: Error loading the i18n strings from input filename:",
       "translation": "i18n4go: Error loading the i18n strings from input filename:"
@@ -492,8 +740,24 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "translation": "i18n4go: Error loading the i18n strings from target filename:"
    },
    {
-      "id": "i18n4go: Error verifying target filename: ",
-      "translation": "i18n4go: Error verifying target filename: "
+      "id": "i18n4go: Error verifying target filename {{.Arg0}}: {{.Arg1}}",
+      "translation": "i18n4go: Error verifying target filename {{.Arg0}}: {{.Arg1}}"
+   },
+   {
+      "id": "i18n4go: Error writing the generated messages file:",
+      "translation": "i18n4go: Error writing the generated messages file:"
+   },
+   {
+      "id": "i18n4go: Error writing the stats report file:",
+      "translation": "i18n4go: Error writing the stats report file:"
+   },
+   {
+      "id": "i18n4go: Google Translate responded with status {{.Arg0}}",
+      "translation": "i18n4go: Google Translate responded with status {{.Arg0}}"
+   },
+   {
+      "id": "i18n4go: Google Translate returned no translation for: {{.Arg0}}",
+      "translation": "i18n4go: Google Translate returned no translation for: {{.Arg0}}"
    },
    {
       "id": "i18n4go: Non-regular source file {{.Arg0}} ({{.Arg1}})\n",
@@ -503,6 +767,14 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "i18n4go: WARNING could not find JSON file:",
       "translation": "i18n4go: WARNING could not find JSON file:"
    },
+   {
+      "id": "i18n4go: WARNING keeping the T(...) call at {{.Arg0}}:",
+      "translation": "i18n4go: WARNING keeping the T(...) call at {{.Arg0}}:"
+   },
+   {
+      "id": "i18n4go: WARNING skipping string {{.Arg0}} at {{.Arg1}}, {{.Arg2}}\n",
+      "translation": "i18n4go: WARNING skipping string {{.Arg0}} at {{.Arg1}}, {{.Arg2}}\n"
+   },
    {
       "id": "i18n4go: WARNING target file contains total of extra keys:",
       "translation": "i18n4go: WARNING target file contains total of extra keys:"
@@ -516,8 +788,28 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "translation": "i18n4go: WARNING target file has extra key with ID: "
    },
    {
-      "id": "i18n4go: WARNING target file has invalid templated translations with key ID: ",
-      "translation": "i18n4go: WARNING target file has invalid templated translations with key ID: "
+      "id": "i18n4go: WARNING target file has invalid translations with key ID: ",
+      "translation": "i18n4go: WARNING target file has invalid translations with key ID: "
+   },
+   {
+      "id": "i18n4go: a directory is required",
+      "translation": "i18n4go: a directory is required"
+   },
+   {
+      "id": "i18n4go: a file or a directory is required",
+      "translation": "i18n4go: a file or a directory is required"
+   },
+   {
+      "id": "i18n4go: a go file or a directory to unwrap is required",
+      "translation": "i18n4go: a go file or a directory to unwrap is required"
+   },
+   {
+      "id": "i18n4go: a source language resource file is required",
+      "translation": "i18n4go: a source language resource file is required"
+   },
+   {
+      "id": "i18n4go: a source translation file is required",
+      "translation": "i18n4go: a source translation file is required"
    },
    {
       "id": "i18n4go: adding init func to package:",
@@ -543,6 +835,10 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "i18n4go: could not extract strings from directory:",
       "translation": "i18n4go: could not extract strings from directory:"
    },
+   {
+      "id": "i18n4go: could not find any translation files in: {{.Arg0}}",
+      "translation": "i18n4go: could not find any translation files in: {{.Arg0}}"
+   },
    {
       "id": "i18n4go: could not load i18n strings from file: {{.Arg0}}",
       "translation": "i18n4go: could not load i18n strings from file: {{.Arg0}}"
@@ -591,6 +887,10 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "i18n4go: error appending i18n.T() to AST file:",
       "translation": "i18n4go: error appending i18n.T() to AST file:"
    },
+   {
+      "id": "i18n4go: error applying the edits to the source file:",
+      "translation": "i18n4go: error applying the edits to the source file:"
+   },
    {
       "id": "i18n4go: error determining the import path:",
       "translation": "i18n4go: error determining the import path:"
@@ -603,6 +903,18 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "i18n4go: error invoking Google Translate for string:",
       "translation": "i18n4go: error invoking Google Translate for string:"
    },
+   {
+      "id": "i18n4go: error loading the source strings:",
+      "translation": "i18n4go: error loading the source strings:"
+   },
+   {
+      "id": "i18n4go: error loading the translation file:",
+      "translation": "i18n4go: error loading the translation file:"
+   },
+   {
+      "id": "i18n4go: error parsing the package files:",
+      "translation": "i18n4go: error parsing the package files:"
+   },
    {
       "id": "i18n4go: error reading content of init code snippet file: {{.Arg0}}\n, using default",
       "translation": "i18n4go: error reading content of init code snippet file: {{.Arg0}}\n, using default"
@@ -611,10 +923,26 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "i18n4go: error saving AST file:",
       "translation": "i18n4go: error saving AST file:"
    },
+   {
+      "id": "i18n4go: error saving the translation file:",
+      "translation": "i18n4go: error saving the translation file:"
+   },
+   {
+      "id": "i18n4go: error saving the unwrapped file:",
+      "translation": "i18n4go: error saving the unwrapped file:"
+   },
    {
       "id": "i18n4go: error saving updated i18n strings file:",
       "translation": "i18n4go: error saving updated i18n strings file:"
    },
+   {
+      "id": "i18n4go: error type checking the rewritten file:",
+      "translation": "i18n4go: error type checking the rewritten file:"
+   },
+   {
+      "id": "i18n4go: error updating the imports of the source file:",
+      "translation": "i18n4go: error updating the imports of the source file:"
+   },
    {
       "id": "i18n4go: extracting strings from file:",
       "translation": "i18n4go: extracting strings from file:"
@@ -623,6 +951,14 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "i18n4go: generated diff file:",
       "translation": "i18n4go: generated diff file:"
    },
+   {
+      "id": "i18n4go: generated stats report file: {{.Arg0}}",
+      "translation": "i18n4go: generated stats report file: {{.Arg0}}"
+   },
+   {
+      "id": "i18n4go: generated {{.Arg0}} message functions in file: {{.Arg1}}",
+      "translation": "i18n4go: generated {{.Arg0}} message functions in file: {{.Arg1}}"
+   },
    {
       "id": "i18n4go: got a local import {{.Arg0}} so using {{.Arg1}} instead for pkg",
       "translation": "i18n4go: got a local import {{.Arg0}} so using {{.Arg1}} instead for pkg"
@@ -651,10 +987,62 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "i18n4go: inspecting dir {{.Arg0}}, recursive: {{.Arg1}}\n",
       "translation": "i18n4go: inspecting dir {{.Arg0}}, recursive: {{.Arg1}}\n"
    },
+   {
+      "id": "i18n4go: interpolated string is invalid, extra args in translation:",
+      "translation": "i18n4go: interpolated string is invalid, extra args in translation:"
+   },
+   {
+      "id": "i18n4go: interpolated string is invalid, verb count does not match:",
+      "translation": "i18n4go: interpolated string is invalid, verb count does not match:"
+   },
+   {
+      "id": "i18n4go: interpolated string is invalid, verb types do not match:",
+      "translation": "i18n4go: interpolated string is invalid, verb types do not match:"
+   },
+   {
+      "id": "i18n4go: interpolated string is invalid, verbs are out of order:",
+      "translation": "i18n4go: interpolated string is invalid, verbs are out of order:"
+   },
+   {
+      "id": "i18n4go: invalid package name: {{.Arg0}}, use the --package option",
+      "translation": "i18n4go: invalid package name: {{.Arg0}}, use the --package option"
+   },
+   {
+      "id": "i18n4go: invalid translation of ID: {{.Arg0}}",
+      "translation": "i18n4go: invalid translation of ID: {{.Arg0}}"
+   },
+   {
+      "id": "i18n4go: keeping the existing init func file:",
+      "translation": "i18n4go: keeping the existing init func file:"
+   },
    {
       "id": "i18n4go: loading JSON strings from file: {{.Arg0}}\n",
       "translation": "i18n4go: loading JSON strings from file: {{.Arg0}}\n"
    },
+   {
+      "id": "i18n4go: loading the source strings from file:",
+      "translation": "i18n4go: loading the source strings from file:"
+   },
+   {
+      "id": "i18n4go: migrating the keys requires the slug or hash key format",
+      "translation": "i18n4go: migrating the keys requires the slug or hash key format"
+   },
+   {
+      "id": "i18n4go: no export data found for {{.Arg0}}",
+      "translation": "i18n4go: no export data found for {{.Arg0}}"
+   },
+   {
+      "id": "i18n4go: no unused IDs to remove from the translation files",
+      "translation": "i18n4go: no unused IDs to remove from the translation files"
+   },
+   {
+      "id": "i18n4go: not generating the i18n_resources.go file with the diff option",
+      "translation": "i18n4go: not generating the i18n_resources.go file with the diff option"
+   },
+   {
+      "id": "i18n4go: removed {{.Arg0}} unused IDs from the translation file {{.Arg1}}\n",
+      "translation": "i18n4go: removed {{.Arg0}} unused IDs from the translation file {{.Arg1}}\n"
+   },
    {
       "id": "i18n4go: rewriting strings for source file:",
       "translation": "i18n4go: rewriting strings for source file:"
@@ -663,6 +1051,10 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "i18n4go: rewriting strings in dir {{.Arg0}}, recursive: {{.Arg1}}\n",
       "translation": "i18n4go: rewriting strings in dir {{.Arg0}}, recursive: {{.Arg1}}\n"
    },
+   {
+      "id": "i18n4go: saved the unwrapped file:",
+      "translation": "i18n4go: saved the unwrapped file:"
+   },
    {
       "id": "i18n4go: saving combined language file: ",
       "translation": "i18n4go: saving combined language file: "
@@ -671,6 +1063,10 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "i18n4go: scanning file: ",
       "translation": "i18n4go: scanning file: "
    },
+   {
+      "id": "i18n4go: skipping file:",
+      "translation": "i18n4go: skipping file:"
+   },
    {
       "id": "i18n4go: target file has extra i18n strings with IDs: {{.Arg0}}",
       "translation": "i18n4go: target file has extra i18n strings with IDs: {{.Arg0}}"
@@ -683,10 +1079,98 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "i18n4go: target file is missing i18n strings with IDs: {{.Arg0}}",
       "translation": "i18n4go: target file is missing i18n strings with IDs: {{.Arg0}}"
    },
+   {
+      "id": "i18n4go: templated string is invalid, extra args in translation:",
+      "translation": "i18n4go: templated string is invalid, extra args in translation:"
+   },
    {
       "id": "i18n4go: templated string is invalid, missing args in translation:",
       "translation": "i18n4go: templated string is invalid, missing args in translation:"
    },
+   {
+      "id": "i18n4go: the IDs {{.Arg0}} and {{.Arg1}} have the same function name {{.Arg2}}, use key based IDs",
+      "translation": "i18n4go: the IDs {{.Arg0}} and {{.Arg1}} have the same function name {{.Arg2}}, use key based IDs"
+   },
+   {
+      "id": "i18n4go: the go.mod file {{.Arg0}} has no module directive",
+      "translation": "i18n4go: the go.mod file {{.Arg0}} has no module directive"
+   },
+   {
+      "id": "i18n4go: the {{.Arg0}} key format requires the i18n strings file or dirname to save the source texts",
+      "translation": "i18n4go: the {{.Arg0}} key format requires the i18n strings file or dirname to save the source texts"
+   },
+   {
+      "id": "i18n4go: translation has unbalanced markup tags:",
+      "translation": "i18n4go: translation has unbalanced markup tags:"
+   },
+   {
+      "id": "i18n4go: translation is missing ANSI escape sequences:",
+      "translation": "i18n4go: translation is missing ANSI escape sequences:"
+   },
+   {
+      "id": "i18n4go: translation is not a valid template:",
+      "translation": "i18n4go: translation is not a valid template:"
+   },
+   {
+      "id": "i18n4go: translation leading whitespace does not match source:",
+      "translation": "i18n4go: translation leading whitespace does not match source:"
+   },
+   {
+      "id": "i18n4go: translation markup tags do not match source:",
+      "translation": "i18n4go: translation markup tags do not match source:"
+   },
+   {
+      "id": "i18n4go: translation newlines do not match source:",
+      "translation": "i18n4go: translation newlines do not match source:"
+   },
+   {
+      "id": "i18n4go: translation terminal punctuation does not match source:",
+      "translation": "i18n4go: translation terminal punctuation does not match source:"
+   },
+   {
+      "id": "i18n4go: translation trailing whitespace does not match source:",
+      "translation": "i18n4go: translation trailing whitespace does not match source:"
+   },
+   {
+      "id": "i18n4go: translation violates the glossary:",
+      "translation": "i18n4go: translation violates the glossary:"
+   },
+   {
+      "id": "i18n4go: unknown include {{.Arg0}}, one of: vendor, testdata, generated, gitignored",
+      "translation": "i18n4go: unknown include {{.Arg0}}, one of: vendor, testdata, generated, gitignored"
+   },
+   {
+      "id": "i18n4go: unknown key format {{.Arg0}}, one of: text, slug, hash",
+      "translation": "i18n4go: unknown key format {{.Arg0}}, one of: text, slug, hash"
+   },
+   {
+      "id": "i18n4go: unknown log format {{.Arg0}}, one of: text, json",
+      "translation": "i18n4go: unknown log format {{.Arg0}}, one of: text, json"
+   },
+   {
+      "id": "i18n4go: unknown log level {{.Arg0}}, one of: error, warn, info, debug",
+      "translation": "i18n4go: unknown log level {{.Arg0}}, one of: error, warn, info, debug"
+   },
+   {
+      "id": "i18n4go: unknown output layout {{.Arg0}}, one of: flat, match-package, match-import",
+      "translation": "i18n4go: unknown output layout {{.Arg0}}, one of: flat, match-package, match-import"
+   },
+   {
+      "id": "i18n4go: unknown stats format: {{.Arg0}}",
+      "translation": "i18n4go: unknown stats format: {{.Arg0}}"
+   },
+   {
+      "id": "i18n4go: unknown translation check: {{.Arg0}}",
+      "translation": "i18n4go: unknown translation check: {{.Arg0}}"
+   },
+   {
+      "id": "i18n4go: unwrapping strings for source file:",
+      "translation": "i18n4go: unwrapping strings for source file:"
+   },
+   {
+      "id": "i18n4go: unwrapping strings in dir {{.Arg0}}, recursive: {{.Arg1}}\n",
+      "translation": "i18n4go: unwrapping strings in dir {{.Arg0}}, recursive: {{.Arg1}}\n"
+   },
    {
       "id": "i18n4go: using import path as:",
       "translation": "i18n4go: using import path as:"
@@ -695,6 +1179,38 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "i18n4go: using the PWD as the rootPath:",
       "translation": "i18n4go: using the PWD as the rootPath:"
    },
+   {
+      "id": "i18n4go: using the cached strings of file:",
+      "translation": "i18n4go: using the cached strings of file:"
+   },
+   {
+      "id": "invalid template: {{.Arg0}}",
+      "translation": "invalid template: {{.Arg0}}"
+   },
+   {
+      "id": "its type {{.Arg0}} is not string",
+      "translation": "its type {{.Arg0}} is not string"
+   },
+   {
+      "id": "leading whitespace mismatch: expected {{.Arg0}}, found {{.Arg1}}",
+      "translation": "leading whitespace mismatch: expected {{.Arg0}}, found {{.Arg1}}"
+   },
+   {
+      "id": "markup tags mismatch: expected {{.Arg0}}, found {{.Arg1}}",
+      "translation": "markup tags mismatch: expected {{.Arg0}}, found {{.Arg1}}"
+   },
+   {
+      "id": "missing ANSI escape sequences: {{.Arg0}}",
+      "translation": "missing ANSI escape sequences: {{.Arg0}}"
+   },
+   {
+      "id": "missing template args: {{.Arg0}}",
+      "translation": "missing template args: {{.Arg0}}"
+   },
+   {
+      "id": "newlines count mismatch: expected {{.Arg0}}, found {{.Arg1}}",
+      "translation": "newlines count mismatch: expected {{.Arg0}}, found {{.Arg1}}"
+   },
    {
       "id": "output directory where the translation files will be placed",
       "translation": "output directory where the translation files will be placed"
@@ -703,10 +1219,30 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "prevents any output files from being created",
       "translation": "prevents any output files from being created"
    },
+   {
+      "id": "print a unified diff of the rewritten files and updated i18n strings files instead of writing them",
+      "translation": "print a unified diff of the rewritten files and updated i18n strings files instead of writing them"
+   },
+   {
+      "id": "printf verb count mismatch: expected {{.Arg0}}, found {{.Arg1}}",
+      "translation": "printf verb count mismatch: expected {{.Arg0}}, found {{.Arg1}}"
+   },
+   {
+      "id": "printf verb order mismatch, use indexed verbs such as %[2]s to reorder args: {{.Arg0}}",
+      "translation": "printf verb order mismatch, use indexed verbs such as %[2]s to reorder args: {{.Arg0}}"
+   },
+   {
+      "id": "printf verb type mismatch: {{.Arg0}}",
+      "translation": "printf verb type mismatch: {{.Arg0}}"
+   },
    {
       "id": "prints the usage",
       "translation": "prints the usage"
    },
+   {
+      "id": "quiet mode where only the errors are logged",
+      "translation": "quiet mode where only the errors are logged"
+   },
    {
       "id": "recursively extract strings from all files in the same directory as filename or dirName",
       "translation": "recursively extract strings from all files in the same directory as filename or dirName"
@@ -715,29 +1251,69 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "recursively rewrite packages from all files in the same directory as filename or dirName",
       "translation": "recursively rewrite packages from all files in the same directory as filename or dirName"
    },
+   {
+      "id": "recursively unwrap the go files of all the sub directories of dirName",
+      "translation": "recursively unwrap the go files of all the sub directories of dirName"
+   },
+   {
+      "id": "refusing to write {{.Arg0}} which no longer type checks: {{.Arg1}}",
+      "translation": "refusing to write {{.Arg0}} which no longer type checks: {{.Arg1}}"
+   },
+   {
+      "id": "reports untranslated string literals passed to user facing functions and T(...) calls with IDs missing from the source catalog",
+      "translation": "reports untranslated string literals passed to user facing functions and T(...) calls with IDs missing from the source catalog"
+   },
    {
       "id": "saving file to path",
       "translation": "saving file to path"
    },
    {
-      "id": "src",
-      "translation": "src"
+      "id": "string literal passed to {{.Arg0}} is not translated, wrap it with T(...)",
+      "translation": "string literal passed to {{.Arg0}} is not translated, wrap it with T(...)"
+   },
+   {
+      "id": "switch cases are compared with the untranslated values",
+      "translation": "switch cases are compared with the untranslated values"
    },
    {
       "id": "targetFilenames:",
       "translation": "targetFilenames:"
    },
+   {
+      "id": "terminal punctuation mismatch: expected {{.Arg0}}, found {{.Arg1}}",
+      "translation": "terminal punctuation mismatch: expected {{.Arg0}}, found {{.Arg1}}"
+   },
+   {
+      "id": "the JSON file with strings and regexps to be excluded, see extract-strings",
+      "translation": "the JSON file with strings and regexps to be excluded, see extract-strings"
+   },
    {
       "id": "the JSON file with strings to be excluded, defaults to excluded.json if present",
       "translation": "the JSON file with strings to be excluded, defaults to excluded.json if present"
    },
+   {
+      "id": "the T(...) call has no translation ID",
+      "translation": "the T(...) call has no translation ID"
+   },
+   {
+      "id": "the arg {{.Arg0}} of the translation is missing",
+      "translation": "the arg {{.Arg0}} of the translation is missing"
+   },
+   {
+      "id": "the args of the T(...) call are not a map literal",
+      "translation": "the args of the T(...) call are not a map literal"
+   },
    {
       "id": "the code",
       "translation": "the code"
    },
    {
-      "id": "the command, one of: extract-strings, create-translations, rewrite-package, verify-strings, merge-strings, checkup, fixup",
-      "translation": "the command, one of: extract-strings, create-translations, rewrite-package, verify-strings, merge-strings, checkup, fixup"
+      "id": "the command, one of: extract-strings, create-translations, rewrite-package, unwrap-package, generate, verify-strings, merge-strings, checkup, fixup, stats",
+      "translation": "the command, one of: extract-strings, create-translations, rewrite-package, unwrap-package, generate, verify-strings, merge-strings, checkup, fixup, stats"
+   },
+   {
+      "id": "the dir name for which all .go files will be unwrapped",
+      "translation": "the dir name for which all .go files will be unwrapped"
    },
    {
       "id": "the dir name for which all .go files will have their strings extracted",
@@ -759,6 +1335,10 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "the output directory where the newly created translation files will be placed",
       "translation": "the output directory where the newly created translation files will be placed"
    },
+   {
+      "id": "the qualifier used when calling the T(...) function, e.g., i18n for i18n.T(...)",
+      "translation": "the qualifier used when calling the T(...) function, e.g., i18n for i18n.T(...)"
+   },
    {
       "id": "the root path to the Go source files whose packages are being rewritten, defaults to working directory, if not specified",
       "translation": "the root path to the Go source files whose packages are being rewritten, defaults to working directory, if not specified"
@@ -767,6 +1347,18 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "the source go file to be rewritten",
       "translation": "the source go file to be rewritten"
    },
+   {
+      "id": "the source go file to be unwrapped",
+      "translation": "the source go file to be unwrapped"
+   },
+   {
+      "id": "the source language JSON file, e.g., en_US.all.json, used to report T(...) calls with unknown IDs",
+      "translation": "the source language JSON file, e.g., en_US.all.json, used to report T(...) calls with unknown IDs"
+   },
+   {
+      "id": "the source language JSON resource file, e.g., en_US.all.json",
+      "translation": "the source language JSON resource file, e.g., en_US.all.json"
+   },
    {
       "id": "the source language of the file, typically also part of the file name, e.g., \"en_US\"",
       "translation": "the source language of the file, typically also part of the file name, e.g., \"en_US\""
@@ -775,10 +1367,42 @@ var _i18n4goI18nResourcesAllEn_usJson = []byte(`[
       "id": "the source translation file",
       "translation": "the source translation file"
    },
+   {
+      "id": "the star args of {{.Arg0}} are not in order",
+      "translation": "the star args of {{.Arg0}} are not in order"
+   },
    {
       "id": "the substring capturing JSON file name, all strings there will only have their first capturing group saved as a translation",
       "translation": "the substring capturing JSON file name, all strings there will only have their first capturing group saved as a translation"
    },
+   {
+      "id": "the template {{.Arg0}} is not only made of args",
+      "translation": "the template {{.Arg0}} is not only made of args"
+   },
+   {
+      "id": "the translation ID is not a string literal",
+      "translation": "the translation ID is not a string literal"
+   },
+   {
+      "id": "the translation wraps more errors than the call",
+      "translation": "the translation wraps more errors than the call"
+   },
+   {
+      "id": "the wrapped error {{.Arg0}} is formatted with {{.Arg1}}",
+      "translation": "the wrapped error {{.Arg0}} is formatted with {{.Arg1}}"
+   },
+   {
+      "id": "trailing whitespace mismatch: expected {{.Arg0}}, found {{.Arg1}}",
+      "translation": "trailing whitespace mismatch: expected {{.Arg0}}, found {{.Arg1}}"
+   },
+   {
+      "id": "translation ID \"{{.Arg0}}\" is not in the catalog {{.Arg1}}",
+      "translation": "translation ID \"{{.Arg0}}\" is not in the catalog {{.Arg1}}"
+   },
+   {
+      "id": "unbalanced markup tags: {{.Arg0}}",
+      "translation": "unbalanced markup tags: {{.Arg0}}"
+   },
    {
       "id": "verbose mode where lots of output is generated during execution",
       "translation": "verbose mode where lots of output is generated during execution"
@@ -800,7 +1424,7 @@ func i18n4goI18nResourcesAllEn_usJson() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "i18n4go/i18n/resources/all.en_US.json", size: 28990, mode: os.FileMode(420), modTime: time.Unix(1764191632, 0)}
+	info := bindataFileInfo{name: "i18n4go/i18n/resources/all.en_US.json", size: 57106, mode: os.FileMode(420), modTime: time.Unix(1792435137, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
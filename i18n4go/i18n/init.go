@@ -22,8 +22,6 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/pivotal-cf-experimental/jibber_jabber"
-
 	go_i18n "github.com/nicksnyder/go-i18n/v2/i18n"
 	"golang.org/x/text/language"
 )
@@ -57,12 +55,19 @@ func GetResourcesPath() string {
 	return RESOURCES_PATH
 }
 
-func Init(packageName string, i18nDirname string, assetFn AssetFunc) TranslateFunc {
+// Init loads the translations of the user locale, using the first of the detectors which detects a locale,
+// or the JibberJabberDetector if no detectors are given, and falls back to en_US
+func Init(packageName string, i18nDirname string, assetFn AssetFunc, detectors ...Detector) TranslateFunc {
 	if bundle == nil {
 		bundle = go_i18n.NewBundle(language.AmericanEnglish)
 		bundle.RegisterUnmarshalFunc("json", json.Unmarshal)
 	}
-	userLocale, err := initWithUserLocale(packageName, i18nDirname, assetFn)
+
+	if len(detectors) == 0 {
+		detectors = []Detector{NewJibberJabberDetector()}
+	}
+
+	userLocale, err := initWithUserLocale(packageName, i18nDirname, assetFn, detectors)
 	if err != nil {
 		userLocale = mustLoadDefaultLocale(packageName, i18nDirname, assetFn)
 	}
@@ -70,19 +75,11 @@ func Init(packageName string, i18nDirname string, assetFn AssetFunc) TranslateFu
 	return Tfunc(userLocale, DEFAULT_LOCALE)
 }
 
-func initWithUserLocale(packageName, i18nDirname string, assetFn AssetFunc) (string, error) {
-	userLocale, err := jibber_jabber.DetectIETF()
-	if err != nil {
-		userLocale = DEFAULT_LOCALE
-	}
-
-	language, err := jibber_jabber.DetectLanguage()
-	if err != nil {
-		language = DEFAULT_LANGUAGE
-	}
+func initWithUserLocale(packageName, i18nDirname string, assetFn AssetFunc, detectors []Detector) (string, error) {
+	userLocale, language := detectLocale(detectors)
 
 	userLocale = strings.Replace(userLocale, "-", "_", 1)
-	err = loadFromAsset(packageName, i18nDirname, userLocale, language, assetFn)
+	err := loadFromAsset(packageName, i18nDirname, userLocale, language, assetFn)
 	if err != nil {
 		locale := SUPPORTED_LOCALES[language]
 		if locale == "" {
@@ -96,6 +93,24 @@ func initWithUserLocale(packageName, i18nDirname string, assetFn AssetFunc) (str
 	return userLocale, err
 }
 
+func detectLocale(detectors []Detector) (string, string) {
+	for _, detector := range detectors {
+		userLocale, err := detector.DetectIETF()
+		if err != nil || userLocale == "" {
+			continue
+		}
+
+		language, err := detector.DetectLanguage()
+		if err != nil || language == "" {
+			language = DEFAULT_LANGUAGE
+		}
+
+		return userLocale, language
+	}
+
+	return DEFAULT_LOCALE, DEFAULT_LANGUAGE
+}
+
 func mustLoadDefaultLocale(packageName, i18nDirname string, assetFn AssetFunc) string {
 	userLocale := DEFAULT_LOCALE
 
@@ -41,6 +41,13 @@ func main() {
 		return
 	}
 
+	if options.CommandFlag != "" {
+		if _, err := common.NewLogger(options); err != nil {
+			logError(options, err)
+			os.Exit(common.ExitCode(err))
+		}
+	}
+
 	switch options.CommandFlag {
 	case "extract-strings":
 		extractStringsCmd()
@@ -48,6 +55,10 @@ func main() {
 		createTranslationsCmd()
 	case "rewrite-package":
 		rewritePackageCmd()
+	case "unwrap-package":
+		unwrapPackageCmd()
+	case "generate":
+		generateCmd()
 	case "verify-strings":
 		verifyStringsCmd()
 	case "merge-strings":
@@ -58,6 +69,8 @@ func main() {
 		checkupCmd()
 	case "fixup":
 		fixupCmd()
+	case "stats":
+		statsCmd()
 	default:
 		rootCobraCmd(options)
 	}
@@ -67,24 +80,51 @@ func rootCobraCmd(opts common.Options) {
 	cmd := &cobra.Command{
 		Use:  "i18n4go",
 		Long: i18n.T("General purpose tool for i18n"),
+
+		// the errors are logged once below, with the format of the --log-format flag
+		SilenceErrors: true,
+		SilenceUsage:  true,
 	}
 
 	cmd.PersistentFlags().BoolVarP(&opts.VerboseFlag, "verbose", "v", false, i18n.T("verbose mode where lots of output is generated during execution"))
+	cmd.PersistentFlags().BoolVar(&opts.QuietFlag, "quiet", false, i18n.T("quiet mode where only the errors are logged"))
+	cmd.PersistentFlags().StringVar(&opts.LogLevelFlag, "log-level", "", i18n.T("[optional] the level of the logged messages, one of: error, warn, info, debug, overrides -v and --quiet"))
+	cmd.PersistentFlags().StringVar(&opts.LogFormatFlag, "log-format", common.LOG_FORMAT_TEXT, i18n.T("[optional] the format of the logged messages, one of: text, json"))
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		_, err := common.NewLogger(opts)
+		return err
+	}
 
 	cmd.AddCommand(cmds.NewCreateTranslationsCommand(&opts))
 	cmd.AddCommand(cmds.NewCheckupCommand(&opts))
 	cmd.AddCommand(cmds.NewExtractStringsCommand(&opts))
 	cmd.AddCommand(cmds.NewRewritePackageCommand(&opts))
+	cmd.AddCommand(cmds.NewUnwrapPackageCommand(&opts))
+	cmd.AddCommand(cmds.NewGenerateCommand(&opts))
 	cmd.AddCommand(cmds.NewVerifyStringsCommand(&opts))
 	cmd.AddCommand(cmds.NewFixupCommand(&opts))
 	cmd.AddCommand(cmds.NewMergeStringsCommand(&opts))
 	cmd.AddCommand(cmds.NewShowMissingStringsCommand(&opts))
+	cmd.AddCommand(cmds.NewStatsCommand(&opts))
 
 	if err := cmd.Execute(); err != nil {
-		fmt.Println(err.Error())
-		os.Exit(1)
+		logError(opts, err)
+		os.Exit(common.ExitCode(err))
 	}
+}
 
+// logError logs the error of a command with the logger of the options, or with the default logger of their log format
+// if their log flags are invalid
+func logError(opts common.Options, err error) {
+	logger, loggerErr := common.NewLogger(opts)
+	if loggerErr != nil {
+		logger, loggerErr = common.NewLogger(common.Options{LogFormatFlag: opts.LogFormatFlag})
+	}
+	if loggerErr != nil {
+		logger, _ = common.NewLogger(common.Options{})
+	}
+
+	logger.Error(err)
 }
 
 func extractStringsCmd() {
@@ -99,12 +139,12 @@ func extractStringsCmd() {
 
 	err := cmd.Run()
 	if err != nil {
-		cmd.Println(i18n.T("i18n4go: Could not extract strings, err:"), err)
-		os.Exit(1)
+		cmd.Logger().Error(i18n.T("i18n4go: Could not extract strings, err:"), err)
+		os.Exit(common.ExitCode(err))
 	}
 
 	duration := time.Now().Sub(startTime)
-	cmd.Println(i18n.T("Total time:"), duration)
+	cmd.Logger().Info(i18n.T("Total time:"), duration)
 }
 
 func createTranslationsCmd() {
@@ -119,12 +159,12 @@ func createTranslationsCmd() {
 
 	err := cmd.Run()
 	if err != nil {
-		cmd.Println(i18n.T("i18n4go: Could not create translation files, err:"), err)
-		os.Exit(1)
+		cmd.Logger().Error(i18n.T("i18n4go: Could not create translation files, err:"), err)
+		os.Exit(common.ExitCode(err))
 	}
 
 	duration := time.Now().Sub(startTime)
-	cmd.Println(i18n.T("Total time:"), duration)
+	cmd.Logger().Info(i18n.T("Total time:"), duration)
 }
 
 func verifyStringsCmd() {
@@ -139,12 +179,12 @@ func verifyStringsCmd() {
 
 	err := cmd.Run()
 	if err != nil {
-		cmd.Println(i18n.T("i18n4go: Could not verify strings for input filename, err:"), err)
-		os.Exit(1)
+		cmd.Logger().Error(i18n.T("i18n4go: Could not verify strings for input filename, err:"), err)
+		os.Exit(common.ExitCode(err))
 	}
 
 	duration := time.Now().Sub(startTime)
-	cmd.Println(i18n.T("Total time:"), duration)
+	cmd.Logger().Info(i18n.T("Total time:"), duration)
 }
 
 func rewritePackageCmd() {
@@ -160,12 +200,52 @@ func rewritePackageCmd() {
 
 	err := cmd.Run()
 	if err != nil {
-		cmd.Println(i18n.T("i18n4go: Could not successfully rewrite package, err:"), err)
-		os.Exit(1)
+		cmd.Logger().Error(i18n.T("i18n4go: Could not successfully rewrite package, err:"), err)
+		os.Exit(common.ExitCode(err))
+	}
+
+	duration := time.Now().Sub(startTime)
+	cmd.Logger().Info(i18n.T("Total time:"), duration)
+}
+
+func unwrapPackageCmd() {
+	if options.HelpFlag || (options.FilenameFlag == "" && options.DirnameFlag == "") {
+		usage()
+		return
+	}
+
+	cmd := cmds.NewUnwrapPackage(&options)
+
+	startTime := time.Now()
+
+	err := cmd.Run()
+	if err != nil {
+		cmd.Logger().Error(i18n.T("i18n4go: Could not successfully unwrap package, err:"), err)
+		os.Exit(common.ExitCode(err))
+	}
+
+	duration := time.Now().Sub(startTime)
+	cmd.Logger().Info(i18n.T("Total time:"), duration)
+}
+
+func generateCmd() {
+	if options.HelpFlag || options.FilenameFlag == "" {
+		usage()
+		return
+	}
+
+	cmd := cmds.NewGenerate(&options)
+
+	startTime := time.Now()
+
+	err := cmd.Run()
+	if err != nil {
+		cmd.Logger().Error(i18n.T("i18n4go: Could not successfully generate the message functions, err:"), err)
+		os.Exit(common.ExitCode(err))
 	}
 
 	duration := time.Now().Sub(startTime)
-	cmd.Println(i18n.T("Total time:"), duration)
+	cmd.Logger().Info(i18n.T("Total time:"), duration)
 }
 
 func mergeStringsCmd() {
@@ -180,12 +260,12 @@ func mergeStringsCmd() {
 
 	err := mergeStrings.Run()
 	if err != nil {
-		mergeStrings.Println(i18n.T("i18n4go: Could not merge strings, err:"), err)
-		os.Exit(1)
+		mergeStrings.Logger().Error(i18n.T("i18n4go: Could not merge strings, err:"), err)
+		os.Exit(common.ExitCode(err))
 	}
 
 	duration := time.Now().Sub(startTime)
-	mergeStrings.Println(i18n.T("Total time:"), duration)
+	mergeStrings.Logger().Info(i18n.T("Total time:"), duration)
 }
 
 func showMissingStringsCmd() {
@@ -200,12 +280,12 @@ func showMissingStringsCmd() {
 
 	err := showMissingStrings.Run()
 	if err != nil {
-		showMissingStrings.Println(i18n.T("i18n4go: Could not show missing strings, err:"), err)
-		os.Exit(1)
+		showMissingStrings.Logger().Error(i18n.T("i18n4go: Could not show missing strings, err:"), err)
+		os.Exit(common.ExitCode(err))
 	}
 
 	duration := time.Now().Sub(startTime)
-	showMissingStrings.Println(i18n.T("Total time:"), duration)
+	showMissingStrings.Logger().Info(i18n.T("Total time:"), duration)
 }
 
 func checkupCmd() {
@@ -220,12 +300,12 @@ func checkupCmd() {
 
 	err := checkup.Run()
 	if err != nil {
-		checkup.Println(i18n.T("i18n4go: Could not checkup, err:"), err)
-		os.Exit(1)
+		checkup.Logger().Error(i18n.T("i18n4go: Could not checkup, err:"), err)
+		os.Exit(common.ExitCode(err))
 	}
 
 	duration := time.Now().Sub(startTime)
-	checkup.Println(i18n.T("Total time:"), duration)
+	checkup.Logger().Info(i18n.T("Total time:"), duration)
 }
 
 func fixupCmd() {
@@ -240,16 +320,36 @@ func fixupCmd() {
 
 	err := fixup.Run()
 	if err != nil {
-		fixup.Println(i18n.T("i18n4go: Could not fixup, err:"), err)
-		os.Exit(1)
+		fixup.Logger().Error(i18n.T("i18n4go: Could not fixup, err:"), err)
+		os.Exit(common.ExitCode(err))
 	}
 
 	duration := time.Now().Sub(startTime)
-	fixup.Println(i18n.T("Total time:"), duration)
+	fixup.Logger().Info(i18n.T("Total time:"), duration)
+}
+
+func statsCmd() {
+	if options.HelpFlag {
+		usage()
+		return
+	}
+
+	stats := cmds.NewStats(&options)
+
+	startTime := time.Now()
+
+	err := stats.Run()
+	if err != nil {
+		stats.Logger().Error(i18n.T("i18n4go: Could not compute the translation stats, err:"), err)
+		os.Exit(common.ExitCode(err))
+	}
+
+	duration := time.Now().Sub(startTime)
+	stats.Logger().Info(i18n.T("Total time:"), duration)
 }
 
 func init() {
-	flag.StringVar(&options.CommandFlag, "c", "", i18n.T("the command, one of: extract-strings, create-translations, rewrite-package, verify-strings, merge-strings, checkup, fixup"))
+	flag.StringVar(&options.CommandFlag, "c", "", i18n.T("the command, one of: extract-strings, create-translations, rewrite-package, unwrap-package, generate, verify-strings, merge-strings, checkup, fixup, stats"))
 
 	flag.BoolVar(&options.HelpFlag, "h", false, i18n.T("prints the usage"))
 	flag.BoolVar(&options.LongHelpFlag, "help", false, i18n.T("prints the usage"))
@@ -259,6 +359,9 @@ func init() {
 	flag.StringVar(&options.GoogleTranslateApiKeyFlag, "google-translate-api-key", "", i18n.T("[optional] your public Google Translate API key which is used to generate translations (charge is applicable)"))
 
 	flag.BoolVar(&options.VerboseFlag, "v", false, i18n.T("verbose mode where lots of output is generated during execution"))
+	flag.BoolVar(&options.QuietFlag, "quiet", false, i18n.T("quiet mode where only the errors are logged"))
+	flag.StringVar(&options.LogLevelFlag, "log-level", "", i18n.T("[optional] the level of the logged messages, one of: error, warn, info, debug, overrides -v and --quiet"))
+	flag.StringVar(&options.LogFormatFlag, "log-format", common.LOG_FORMAT_TEXT, i18n.T("[optional] the format of the logged messages, one of: text, json"))
 
 	flag.BoolVar(&options.PoFlag, "po", false, i18n.T("generate standard .po file for translation"))
 
@@ -273,6 +376,7 @@ func init() {
 
 	flag.BoolVar(&options.OutputFlatFlag, "output-flat", true, i18n.T("generated files are created in the specified output directory"))
 	flag.BoolVar(&options.OutputMatchPackageFlag, "output-match-package", false, i18n.T("generated files are created in directory to match the package name"))
+	flag.BoolVar(&options.OutputMatchImportFlag, "output-match-import", false, i18n.T("generated files are created in directory to match the import path of the package, resolved with the go.mod file of its module"))
 
 	flag.StringVar(&options.FilenameFlag, "f", "", i18n.T("the file name for which strings are extracted"))
 
@@ -284,42 +388,100 @@ func init() {
 
 	flag.StringVar(&options.LanguageFilesFlag, "language-files", "", i18n.T(`[optional] a comma separated list of target files for different languages to compare,  e.g., \"en, en_US, fr_FR, es\"	                                                                  if not specified then the languages flag is used to find target files in same directory as source`))
 
+	flag.StringVar(&options.SkipChecksFlag, "skip-checks", "", i18n.T("[optional] a comma separated list of translation checks to skip, one of: template-args, printf, template-syntax, markup, whitespace, newlines, punctuation, ansi, glossary"))
+	flag.StringVar(&options.GlossaryFilenameFlag, "glossary", "", i18n.T("[optional] the glossary JSON file with the terms that must not be translated or must be translated with specific terms per locale"))
+
+	flag.StringVar(&options.FormatFlag, "format", "table", i18n.T("[optional] the format of the report, one of: table, json, html"))
+
 	flag.StringVar(&options.I18nStringsFilenameFlag, "i18n-strings-filename", "", i18n.T("a JSON file with the strings that should be i18n enabled, typically the output of -extract-strings command"))
 	flag.StringVar(&options.I18nStringsDirnameFlag, "i18n-strings-dirname", "", i18n.T("a directory with the extracted JSON files, using -output-match-package with -extract-strings this directory should match the input files package name"))
+	flag.StringVar(&options.KeyFormatFlag, "key-format", common.TEXT_KEY_FORMAT, i18n.T("[optional] the format of the IDs of the strings, one of: text, slug, hash, the slug and hash IDs are scoped by package, e.g., greetings.hello_world"))
+	flag.BoolVar(&options.MigrateKeysFlag, "migrate-keys", false, i18n.T("[optional] migrate the source text IDs of the translation files of all locales and of the source files to keys of the key format"))
 	flag.StringVar(&options.RootPathFlag, "root-path", "", i18n.T("the root path to the Go source files whose packages are being rewritten, defaults to working directory, if not specified"))
 
 	flag.StringVar(&options.InitCodeSnippetFilenameFlag, "init-code-snippet-filename", "", i18n.T("[optional] the path to a file containing the template snippet for the code that is used for go-i18n initialization"))
+	flag.StringVar(&options.TFuncNamesFlag, "t-func-names", common.DEFAULT_T_FUNC_NAMES, i18n.T("[optional] a comma separated list of the names of the functions translating strings, whose calls are not rewritten again"))
+	flag.StringVar(&options.TemplateExtensionsFlag, "template-extensions", common.DEFAULT_TEMPLATE_EXTENSIONS, i18n.T("[optional] a comma separated list of the extensions of the text/template and html/template files whose strings are extracted with the go files of a directory"))
+	flag.BoolVar(&options.RegenerateInitFlag, "regenerate-init", false, i18n.T("[optional] regenerate the i18n_init.go files which already exist"))
+	flag.BoolVar(&options.DiffFlag, "diff", false, i18n.T("print a unified diff of the rewritten files and updated i18n strings files instead of writing them"))
 
 	flag.StringVar(&options.QualifierFlag, "q", "", i18n.T("[optional] the qualifier string that is used when using the i18n.T(...) function, default to nothing but could be set to `i18n` so that all calls would be: i18n.T(...)"))
 
 	flag.StringVar(&options.SourceDirFlag, "source", ".", i18n.T("[optional] the directory where the source go files are located, defaults to current directory"))
 	flag.StringVar(&options.ResourceDirFlag, "resource", ".", i18n.T("[optional] the directory where the translation files are located, defaults to current directory"))
 
+	flag.StringVar(&options.PackageNameFlag, "package", "", i18n.T("[optional] the name of the generated package, defaults to the name of the output directory"))
+
+	flag.StringVar(&options.CacheDirFlag, "cache-dir", "", i18n.T("[optional] the directory of the cache of the strings of the go files, so that only the files which changed are parsed again"))
+	flag.IntVar(&options.JobsFlag, "jobs", 0, i18n.T("[optional] the number of files inspected concurrently, defaults to the number of CPUs"))
+
+	flag.StringVar(&options.GoosFlag, "goos", "", i18n.T("[optional] the GOOS of the build constraints of the go files, defaults to the host one"))
+	flag.StringVar(&options.GoarchFlag, "goarch", "", i18n.T("[optional] the GOARCH of the build constraints of the go files, defaults to the host one"))
+	flag.StringVar(&options.BuildTagsFlag, "tags", "", i18n.T("[optional] a comma separated list of the build tags of the build constraints of the go files"))
+	flag.StringVar(&options.IncludeFlag, "include", "", i18n.T("[optional] a comma separated list of the sources skipped by default to include, one of: vendor, testdata, generated, gitignored"))
+
 	flag.Parse()
 }
 
 func usage() {
 	usageString := `
-usage: i18n4go -c extract-strings [-vpe] [--dry-run] [--output-flat|--output-match-package|-o <outputDir>] -f <fileName>
-   or: i18n4go -c extract-strings [-vpe] [--dry-run] [--output-flat|--output-match-package|-o <outputDir>] -d <dirName> [-r] [--ignore-regexp <fileNameRegexp>]
+usage: i18n4go -c extract-strings [-vpe] [--dry-run] [--key-format text|slug|hash] [--output-flat|--output-match-package|--output-match-import] [-o <outputDir>] -f <fileName>
+   or: i18n4go -c extract-strings [-vpe] [--dry-run] [--key-format text|slug|hash] [--output-flat|--output-match-package|--output-match-import] [-o <outputDir>] -d <dirName> [-r] [--ignore-regexp <fileNameRegexp>] [--template-extensions <ext1,ext2,...>] [--t-func-names <name1,name2,...>] [--jobs <count>] [--cache-dir <dirName>] [--goos <os>] [--goarch <arch>] [--tags <tag1,tag2,...>] [--include <vendor,testdata,generated,gitignored>]
+
+usage: i18n4go -c rewrite-package [-v] [-r] [--diff] [--key-format text|slug|hash] [-q <qualifier>] [--t-func-names <name1,name2,...>] [--regenerate-init] -d <dirName> [--i18n-strings-filename <fileName> | --i18n-strings-dirname <dirName>] [--init-code-snippet-filename <fileName>] [--ignore-regexp <fileNameRegexp>] [--goos <os>] [--goarch <arch>] [--tags <tag1,tag2,...>] [--include <vendor,testdata,generated,gitignored>]
+   or: i18n4go -c rewrite-package [-v] [-r] [--diff] [--key-format text|slug|hash] [-q <qualifier>] [--t-func-names <name1,name2,...>] [--regenerate-init] -f <fileName> --i18n-strings-filename <fileName> [--init-code-snippet-filename <fileName>] [--ignore-regexp <fileNameRegexp>]
 
-usage: i18n4go -c rewrite-package [-v] [-r] -d <dirName> [--i18n-strings-filename <fileName> | --i18n-strings-dirname <dirName>] [--init-code-snippet-filename <fileName>] [--ignore-regexp <fileNameRegexp>]
-   or: i18n4go -c rewrite-package [-v] [-r] -f <fileName> --i18n-strings-filename <fileName> [--init-code-snippet-filename <fileName>] [--ignore-regexp <fileNameRegexp>]
+usage: i18n4go -c unwrap-package [-v] [-r] [-q <qualifier>] [--t-func-names <name1,name2,...>] [--source-language <language>] [--source <dirName>] [--resource <dirName>] [-o <outputDir>] -d <dirName> [--ignore-regexp <fileNameRegexp>] [--goos <os>] [--goarch <arch>] [--tags <tag1,tag2,...>] [--include <vendor,testdata,generated,gitignored>]
+   or: i18n4go -c unwrap-package [-v] [-q <qualifier>] [--t-func-names <name1,name2,...>] [--source-language <language>] [--source <dirName>] [--resource <dirName>] [-o <outputDir>] -f <fileName>
+
+usage: i18n4go -c generate [-v] [--dry-run] [-o <outputDir>] [--package <packageName>] -f <fileName>
 
 usage: i18n4go -c create-translations [-v] [--google-translate-api-key <api key>] [--source-language <language>] -f <fileName> --languages <lang1,lang2,...> -o <outputDir>
 
 usage: i18n4go -c merge-strings [-v] [-r] [--source-language <language>] -d <dirName>
 
-usage: i18n4go -c verify-strings [-v] [--source-language <language>] -f <sourceFileName> --language-files <language files> [-o <outputDir>]
-   or: i18n4go -c verify-strings [-v] [--source-language <language>] -f <sourceFileName> --languages <lang1,lang2,...> [-o <outputDir>]
+usage: i18n4go -c verify-strings [-v] [--source-language <language>] -f <sourceFileName> --language-files <language files> [-o <outputDir>] [--skip-checks <check1,check2,...>] [--glossary <glossaryFile>]
+   or: i18n4go -c verify-strings [-v] [--source-language <language>] -f <sourceFileName> --languages <lang1,lang2,...> [-o <outputDir>] [--skip-checks <check1,check2,...>] [--glossary <glossaryFile>]
+
+usage: i18n4go -c show-missing-strings [-v] [--jobs <count>] [--goos <os>] [--goarch <arch>] [--tags <tag1,tag2,...>] [--include <vendor,testdata,generated,gitignored>] -d <dirName> --i18n-strings-filename <language file>
 
-usage: i18n4go -c show-missing-strings [-v] -d <dirName> --i18n-strings-filename <language file>
+usage: i18n4go -c checkup [-v] [-q <qualifier>] [--glossary <glossaryFile>] [--jobs <count>] [--cache-dir <dirName>] [--goos <os>] [--goarch <arch>] [--tags <tag1,tag2,...>] [--include <vendor,testdata,generated,gitignored>]
 
-usage: i18n4go -c checkup
+usage: i18n4go -c fixup [-v] [-q <qualifier>] [--source <dirName>] [--resource <dirName>] [--migrate-keys --key-format slug|hash] [--jobs <count>] [--cache-dir <dirName>] [--goos <os>] [--goarch <arch>] [--tags <tag1,tag2,...>] [--include <vendor,testdata,generated,gitignored>]
+
+usage: i18n4go -c stats [-v] [-d <dirName>] [--source-language <language>] [--format table|json|html] [-o <outputDir>] [--skip-checks <check1,check2,...>] [--glossary <glossaryFile>]
 
   -h | --help                prints the usage
   -v                         verbose
 
+  LOGGING:
+
+  the errors and warnings are logged by default, -v logs the verbose output of the commands at the debug level
+
+  --quiet                    quiet mode where only the errors are logged
+  --log-level                [optional] the level of the logged messages, one of: error, warn, info, debug, overrides -v and --quiet
+  --log-format               [optional] the format of the logged messages, one of: text, json, the json format logs one object with the time, level and msg fields per line
+
+  EXIT CODES:
+
+  0                          success
+  1                          invalid usage, e.g., an unknown flag value, or another error
+  2                          validation error, e.g., missing, extra or invalid translations
+  3                          parse error, e.g., a go, template or JSON file which cannot be parsed
+  4                          I/O error, e.g., a file which cannot be read or written
+  5                          provider error, e.g., a network failure during Google Translate
+
+  SOURCE TREES:
+
+  the extract-strings, rewrite-package, unwrap-package, show-missing-strings, checkup and fixup commands skip the hidden,
+  vendor and testdata directories, the files ignored by .gitignore files, the generated go files and the go files excluded
+  by the build constraints
+
+  --goos                     [optional] the GOOS of the build constraints of the go files, defaults to the host one
+  --goarch                   [optional] the GOARCH of the build constraints of the go files, defaults to the host one
+  --tags                     [optional] a comma separated list of the build tags of the build constraints of the go files
+  --include                  [optional] a comma separated list of the sources skipped by default to include, one of: vendor, testdata, generated, gitignored
+
   EXTRACT-STRINGS:
 
   -c extract-strings         the extract strings command
@@ -329,18 +491,24 @@ usage: i18n4go -c checkup
 	-s												 [optional] the JSON file with regexp that specify a capturing group to be extracted instead of the full string matching the regexp
   --meta                     [optional] create a *.extracted.json file with metadata such as: filename, directory, and positions of the strings in source file
   --dry-run                  [optional] prevents any output files from being created
+  --key-format               [optional] the format of the IDs of the strings, one of: text (default), slug or hash, e.g., greetings.hello_world with slug
 
 
   --output-flat              generated files are created in the specified output directory (default)
   --output-match-package     generated files are created in directory to match the package name
+  --output-match-import      generated files are created in directories matching the import paths of the packages, e.g., <outputDir>/github.com/org/module/pkg
   -o                         the output directory where the translation files will be placed
 
-  -f                         the go file name to extract strings
+  -f                         the go file name to extract strings, or a text/template or html/template file
 
   -d                         the directory containing the go files to extract strings
 
   -r                         [optional] recursesively extract strings from all subdirectories
   --ignore-regexp            [optional] a perl-style regular expression for files to ignore, e.g., ".*test.*"
+  --template-extensions      [optional] the extensions of the template files extracted with the go files, defaults to ".tmpl,.gotmpl,.gohtml"
+  --t-func-names             [optional] the names of the template functions whose string args are extracted, defaults to "T,t"
+  --jobs                     [optional] the number of files extracted concurrently, defaults to the number of CPUs
+  --cache-dir                [optional] the directory of the cache of the strings of the go files, only the changed files are parsed again
 
   REWRITE-PACKAGE:
 
@@ -354,9 +522,38 @@ usage: i18n4go -c checkup
 
   --init-code-snippet-filename [optional] the path to a file containing the template snippet for the code that is used for go-i18n initialization"
   -o                           [optional] output diretory for rewritten file. If not specified, the original file will be overwritten
+  -q                           [optional] the qualifier of the i18n.T(...) calls, which are not rewritten again like the T(...) and i18n.T(...) calls
+  --t-func-names               [optional] a comma separated list of the names of the functions translating strings, defaults to "T,t"
+  --regenerate-init            [optional] regenerate the i18n_init.go files which already exist, they are kept by default
+  --diff                       [optional] print a unified diff of the rewritten files and updated i18n strings files, relative to the root path, instead of writing them
+  --key-format                 [optional] the format of the IDs of the strings, one of: text (default), slug or hash, the i18n strings are updated with the generated keys
 
   --ignore-regexp		[optional] a perl-style regular expression for files to ignore, e.g., ".*test.*"
 
+  UNWRAP-PACKAGE:
+
+  -c unwrap-package          the unwrap package command, the inverse of rewrite-package
+  -f                         the source go file to be unwrapped
+  -d                         the directory containing the go files to unwrap
+  -r                         [optional] recursively unwrap the go files of all subdirectories
+
+  -o                         [optional] output directory for the unwrapped files. If not specified, the original files will be overwritten
+  -q                         [optional] the qualifier of the i18n.T(...) calls to unwrap like the T(...) and i18n.T(...) calls
+  --t-func-names             [optional] a comma separated list of the names of the functions translating strings, defaults to "T,t"
+  --source-language          [optional] the source language of the translations replacing the T(...) calls, e.g., "en_US" (default to 'en')
+  --source                   [optional] the directory of the go files whose T(...) calls still use the IDs, defaults to current directory
+  --resource                 [optional] the directory of the translation files from which the unused IDs are removed, defaults to current directory
+
+  --ignore-regexp            [optional] a perl-style regular expression for files to ignore, e.g., ".*test.*"
+
+  GENERATE:
+
+  -c generate                the generate command which generates a Go package with one typed function per message
+  -f                         the source language JSON resource file, e.g., en_US.all.json
+  -o                         [optional] the output directory of the generated package, defaults to current directory
+  --package                  [optional] the name of the generated package, defaults to the name of the output directory
+  --dry-run                  [optional] print the generated code instead of writing the i18n_messages.go file
+
   MERGE STRINGS:
 
   -c merge-strings           the merge strings command which merges multiple <filename>.go.<language>.json files into a all.<language>.json
@@ -390,6 +587,9 @@ usage: i18n4go -c checkup
   --languages                a comma separated list of valid languages with optional territory, e.g., "en, en_US, fr_FR, es"
 
   -o                         the output directory where the missing translation keys will be placed
+  --skip-checks              [optional] a comma separated list of translation checks to skip, one of: template-args, printf, template-syntax,
+                             markup, whitespace, newlines, punctuation, ansi, glossary
+  --glossary                 [optional] the glossary JSON file with the terms that must not be translated or must be translated with specific terms per locale
 
   SHOW-MISSING-STRINGS:
 
@@ -397,11 +597,23 @@ usage: i18n4go -c checkup
 
   -d                         the directory containing the go files to validate
   --i18n-strings-filename    a JSON file with the strings that should be i18n enabled, typically the output of -extract-strings command
+  --jobs                     [optional] the number of files inspected concurrently, defaults to the number of CPUs
 
   CHECKUP:
 
   -c checkup                 the checkup command which ensures that the strings in code match strings in resource files and vice versa
   -q                         the qualifier to use when calling the i18n.T(...), defaults to empty but can be used to set to something like i18n for example, such that, i18n.T(...) is used for i18n.T(...) function
+  --glossary                 [optional] the glossary JSON file with the terms that must not be translated or must be translated with specific terms per locale
+  --jobs                     [optional] the number of files inspected concurrently, defaults to the number of CPUs
+  --cache-dir                [optional] the directory of the cache of the strings of the go files, only the changed files are parsed again
+
+  STATS:
+
+  -c stats                   the stats command which reports per locale and package the total, translated, untranslated, missing, extra and invalid keys
+  -d                         [optional] the directory containing the translation files, defaults to current directory
+  --source-language          [optional] the source language of the translation files (default to 'en')
+  --format                   [optional] the format of the report, one of: table, json, html
+  -o                         [optional] the output directory where the stats report file will be placed, defaults to printing the report
 
   FIXUP:
 
@@ -412,6 +624,11 @@ usage: i18n4go -c checkup
   --resource 		     [optional] the directory where the translation files are located, defaults to current directory
 
   -q 			     [optional] the qualifier string that is used when importing the package for i18n4go to use the i18n.T(...) function
+
+  --migrate-keys             [optional] migrate the source text IDs of the translation files of all locales and of the source files to keys
+  --key-format               [optional] the format of the migrated keys, one of: slug or hash
+  --jobs                     [optional] the number of files inspected concurrently, defaults to the number of CPUs
+  --cache-dir                [optional] the directory of the cache of the strings of the go files, only the changed files are parsed again
 `
 	fmt.Println(fmt.Sprintf(i18n.T("{{.Arg0}}\nVersion {{.Arg1}}", map[string]interface{}{"Arg0": usageString, "Arg1": VERSION})))
 }
@@ -430,7 +647,7 @@ func handlePanic() {
 	}
 
 	if err != nil {
-		os.Exit(1)
+		os.Exit(common.EXIT_CODE_ERROR)
 	}
 }
 
@@ -0,0 +1,113 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyzer_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("i18n4go-vet", func() {
+	var (
+		fixturesPath string
+		curDir       string
+		output       string
+	)
+
+	BeforeEach(func() {
+		var err error
+		curDir, err = os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath = filepath.Join("..", "..", "test_fixtures", "analyzer", "input_files")
+		err = os.Chdir(fixturesPath)
+		Ω(err).ShouldNot(HaveOccurred(), "Could not change to fixtures directory")
+	})
+
+	AfterEach(func() {
+		err := os.Chdir(curDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("running standalone", func() {
+		var session *Session
+
+		BeforeEach(func() {
+			session = Runi18nVet("-catalog", "en_US.all.json", "-excluded", "excluded.json", ".")
+			output = string(session.Err.Contents())
+		})
+
+		It("reports the untranslated string literals passed to user facing functions", func() {
+			Ω(output).Should(ContainSubstring("main.go:16:14: string literal passed to fmt.Println is not translated, wrap it with T(...)"))
+			Ω(output).Should(ContainSubstring("main.go:18:13: string literal passed to fmt.Printf is not translated, wrap it with T(...)"))
+			Ω(output).Should(ContainSubstring("main.go:25:20: string literal passed to errors.New is not translated, wrap it with T(...)"))
+		})
+
+		It("reports the T(...) calls with IDs missing from the catalog", func() {
+			Ω(output).Should(ContainSubstring("main.go:19:28: translation ID \"Unknown translation\" is not in the catalog en_US.all.json"))
+		})
+
+		It("ignores translated, printf verbs only and excluded strings", func() {
+			Ω(output).ShouldNot(ContainSubstring("main.go:15:"))
+			Ω(output).ShouldNot(ContainSubstring("main.go:17:"))
+			Ω(output).ShouldNot(ContainSubstring("main.go:20:"))
+			Ω(output).ShouldNot(ContainSubstring("main.go:23:"))
+		})
+
+		It("exits with 3 when problems are found", func() {
+			Ω(session.ExitCode()).Should(Equal(3))
+		})
+	})
+
+	Context("with custom sinks", func() {
+		It("only reports the literals passed to those functions", func() {
+			session := Runi18nVet("-sinks", "(*log.Logger).Printf", ".")
+			output = string(session.Err.Contents())
+
+			Ω(output).Should(ContainSubstring("main.go:23:16: string literal passed to (*log.Logger).Printf is not translated, wrap it with T(...)"))
+			Ω(output).ShouldNot(ContainSubstring("fmt.Println"))
+		})
+	})
+
+	Context("with custom T function names", func() {
+		It("only checks the IDs of the calls of those functions", func() {
+			session := Runi18nVet("-catalog", "en_US.all.json", "-t-func-names", "Tr", ".")
+			output = string(session.Err.Contents())
+
+			Ω(output).Should(ContainSubstring("main.go:16:14: string literal passed to fmt.Println is not translated, wrap it with T(...)"))
+			Ω(output).ShouldNot(ContainSubstring("Unknown translation"))
+		})
+	})
+
+	Context("running with go vet", func() {
+		It("reports the same problems", func() {
+			catalogPath, err := filepath.Abs("en_US.all.json")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			session := RunCommandWithTimeout(time.Minute, "go", "vet", "-vettool="+I18n4goVetExec, "-catalog="+catalogPath, ".")
+			Ω(session.ExitCode()).ShouldNot(Equal(0))
+
+			output = string(session.Err.Contents())
+			Ω(output).Should(ContainSubstring("main.go:16:14: string literal passed to fmt.Println is not translated, wrap it with T(...)"))
+			Ω(output).Should(ContainSubstring("main.go:19:28: translation ID \"Unknown translation\" is not in the catalog"))
+		})
+	})
+})
@@ -0,0 +1,355 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/maximilien/i18n4go/i18n4go/api"
+	"github.com/maximilien/i18n4go/i18n4go/common"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("api", func() {
+	var (
+		fixturesPath string
+		tmpDir       string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath = filepath.Join("..", "..", "test_fixtures")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	Context("Extract", func() {
+		It("returns the strings extracted from each file", func() {
+			result, err := api.Extract(api.ExtractOptions{
+				Dirname: filepath.Join(fixturesPath, "extract_strings", "output_match_import", "input_files"),
+				Recurse: true,
+				DryRun:  true,
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(result.TotalFiles).Should(Equal(2))
+			Ω(result.TotalStrings).Should(Equal(2))
+			Ω(result.Files).Should(HaveLen(2))
+
+			Ω(filepath.Base(result.Files[0].Filename)).Should(Equal("main.go"))
+			Ω(result.Files[0].PackageName).Should(Equal("main"))
+			Ω(result.Files[0].Strings).Should(Equal([]api.ExtractedString{
+				{ID: "Welcome to the greeter", Value: "Welcome to the greeter", Offset: 103, Line: 10, Column: 14},
+			}))
+
+			Ω(filepath.Base(result.Files[1].Filename)).Should(Equal("greetings.go"))
+			Ω(result.Files[1].PackageName).Should(Equal("greetings"))
+			Ω(result.Files[1].Strings[0].Value).Should(Equal("Hello world"))
+		})
+
+		It("generates the IDs with the key format", func() {
+			result, err := api.Extract(api.ExtractOptions{
+				Filename:  filepath.Join(fixturesPath, "extract_strings", "output_match_import", "input_files", "internal", "greetings", "greetings.go"),
+				KeyFormat: api.SLUG_KEY_FORMAT,
+				DryRun:    true,
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(result.Files).Should(HaveLen(1))
+			Ω(result.Files[0].Strings[0].ID).Should(Equal("greetings.hello_world"))
+		})
+
+		It("fails without a file or a directory", func() {
+			_, err := api.Extract(api.ExtractOptions{})
+			Ω(err).Should(HaveOccurred())
+		})
+
+		It("fails with an unknown output layout", func() {
+			_, err := api.Extract(api.ExtractOptions{Dirname: tmpDir, OutputLayout: "nested"})
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("Rewrite", func() {
+		It("returns the unified diff of the rewritten files without writing them", func() {
+			inputFilesPath := filepath.Join(fixturesPath, "rewrite_package", "diff_option", "input_files")
+			for _, fileName := range []string{"greetings.go", "farewells.go", "en.all.json"} {
+				CopyFile(filepath.Join(inputFilesPath, fileName), filepath.Join(tmpDir, fileName))
+			}
+
+			result, err := api.Rewrite(api.RewriteOptions{
+				Dirname:             tmpDir,
+				I18nStringsFilename: filepath.Join(tmpDir, "en.all.json"),
+				RootPath:            tmpDir,
+				Diff:                true,
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			expectedDiff, err := ioutil.ReadFile(filepath.Join(fixturesPath, "rewrite_package", "diff_option", "expected_output", "rewrite.diff"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(result.Diff).Should(Equal(string(expectedDiff)))
+
+			Ω(result.WrittenFiles).Should(ContainElement(filepath.Join(tmpDir, "greetings.go")))
+			Ω(filepath.Join(tmpDir, "i18n_init.go")).ShouldNot(BeAnExistingFile())
+		})
+	})
+
+	Context("Verify", func() {
+		It("returns the problems of each target file", func() {
+			inputFilesPath := filepath.Join(fixturesPath, "verify_strings", "printf_verbs", "input_files")
+			for _, fileName := range []string{"app.go.en.json", "app.go.fr.json"} {
+				CopyFile(filepath.Join(inputFilesPath, fileName), filepath.Join(tmpDir, fileName))
+			}
+
+			result, err := api.Verify(api.VerifyOptions{
+				Filename:  filepath.Join(tmpDir, "app.go.en.json"),
+				Languages: []string{"fr"},
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(result.Files).Should(HaveLen(1))
+			Ω(result.Files[0].Locale).Should(Equal("fr"))
+			Ω(result.Files[0].MissingIDs).Should(BeEmpty())
+			Ω(result.Files[0].ExtraIDs).Should(BeEmpty())
+			Ω(result.Files[0].InvalidTranslations).Should(BeEmpty())
+		})
+
+		It("returns an error and the missing IDs when a target file is missing strings", func() {
+			CopyFile(filepath.Join(fixturesPath, "verify_strings", "printf_verbs", "input_files", "app.go.en.json"), filepath.Join(tmpDir, "app.go.en.json"))
+			err := ioutil.WriteFile(filepath.Join(tmpDir, "app.go.fr.json"), []byte(`[{"id": "Hello {{.Name}}", "translation": "Bonjour {{.Name}}"}]`), 0644)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			result, err := api.Verify(api.VerifyOptions{
+				Filename:  filepath.Join(tmpDir, "app.go.en.json"),
+				Languages: []string{"fr"},
+			})
+			Ω(err).Should(HaveOccurred())
+
+			var validationErr *api.ValidationError
+			Ω(errors.As(err, &validationErr)).Should(BeTrue())
+
+			Ω(result.Files).Should(HaveLen(1))
+			Ω(result.Files[0].MissingIDs).Should(Equal([]string{"Memory: %5.2f MB", "Pushing app %s to org %s", "Scaled %s to %d instances"}))
+		})
+
+		It("returns an IOError when the input file does not exist", func() {
+			_, err := api.Verify(api.VerifyOptions{
+				Filename:  filepath.Join(tmpDir, "app.go.en.json"),
+				Languages: []string{"fr"},
+			})
+
+			var ioErr *api.IOError
+			Ω(errors.As(err, &ioErr)).Should(BeTrue())
+		})
+	})
+
+	Context("Merge", func() {
+		It("returns the merged translations", func() {
+			result, err := api.Merge(api.MergeOptions{
+				Dirname:        filepath.Join(fixturesPath, "merge_strings", "source_language", "input_files"),
+				SourceLanguage: "fr",
+				DryRun:         true,
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			expectedTranslations := ReadJson(filepath.Join(fixturesPath, "merge_strings", "source_language", "expected_output", "all.fr.json"))
+
+			Ω(result.Files).Should(HaveLen(1))
+			Ω(filepath.Base(result.Files[0].Filename)).Should(Equal("all.fr.json"))
+			Ω(result.Files[0].Translations).Should(HaveLen(len(expectedTranslations)))
+			for _, translation := range result.Files[0].Translations {
+				Ω(translation.Translation).Should(Equal(expectedTranslations[translation.ID]))
+			}
+		})
+	})
+
+	Context("Checkup", func() {
+		It("returns no problems when the code and the translations match", func() {
+			checkupPath := filepath.Join(fixturesPath, "checkup", "allgood")
+			result, err := api.Checkup(api.CheckupOptions{
+				SourceDirname:   filepath.Join(checkupPath, "src"),
+				ResourceDirname: filepath.Join(checkupPath, "translations"),
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(result.Mismatches).Should(BeEmpty())
+		})
+
+		It("returns an error and the mismatches when the code and the translations do not match", func() {
+			checkupPath := filepath.Join(fixturesPath, "checkup", "notsogood")
+			result, err := api.Checkup(api.CheckupOptions{
+				SourceDirname:   filepath.Join(checkupPath, "src"),
+				ResourceDirname: filepath.Join(checkupPath, "translations"),
+			})
+			Ω(err).Should(HaveOccurred())
+
+			Ω(result.Mismatches).Should(Equal([]api.Mismatch{
+				{ID: "Heal the world", FoundIn: "", MissingFrom: "en_US"},
+				{ID: "Make it a better place", FoundIn: "en_US", MissingFrom: ""},
+				{ID: "And the entire human race", FoundIn: "en_US", MissingFrom: "zh_CN"},
+				{ID: "For you and for me", FoundIn: "zh_CN", MissingFrom: "en_US"},
+			}))
+		})
+	})
+
+	Context("Fixup", func() {
+		BeforeEach(func() {
+			CopyDir(filepath.Join(fixturesPath, "fixup", "notsogood", "update"), tmpDir)
+		})
+
+		It("adds the new strings of the code by default", func() {
+			result, err := api.Fixup(api.FixupOptions{
+				SourceDirname:   filepath.Join(tmpDir, "src"),
+				ResourceDirname: filepath.Join(tmpDir, "translations"),
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(result.AddedIDs).Should(Equal([]string{"I like apples."}))
+			Ω(result.RemovedIDs).Should(Equal([]string{"I like bananas."}))
+			Ω(result.UpdatedIDs).Should(BeEmpty())
+		})
+
+		It("updates the strings resolved as updated", func() {
+			var resolvedRemovedIDs []string
+			result, err := api.Fixup(api.FixupOptions{
+				SourceDirname:   filepath.Join(tmpDir, "src"),
+				ResourceDirname: filepath.Join(tmpDir, "translations"),
+				ResolveUpdate: func(id string, removedIDs []string) string {
+					resolvedRemovedIDs = removedIDs
+					return "I like bananas."
+				},
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(resolvedRemovedIDs).Should(Equal([]string{"I like bananas."}))
+			Ω(result.AddedIDs).Should(BeEmpty())
+			Ω(result.RemovedIDs).Should(BeEmpty())
+			Ω(result.UpdatedIDs).Should(Equal(map[string]string{"I like bananas.": "I like apples."}))
+
+			translations := ReadJson(filepath.Join(tmpDir, "translations", "all.en_US.json"))
+			Ω(translations).Should(HaveKey("I like apples."))
+			Ω(translations).ShouldNot(HaveKey("I like bananas."))
+		})
+	})
+
+	Context("FS", func() {
+		It("extracts the strings of an in memory source tree", func() {
+			memFS := common.NewMemFS(map[string][]byte{
+				"/src/app/main.go": []byte("package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"Hello from memory\")\n}\n"),
+			})
+
+			result, err := api.Extract(api.ExtractOptions{
+				Dirname:       "/src/app",
+				OutputDirname: "/out",
+				FS:            memFS,
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(result.Files).Should(HaveLen(1))
+			Ω(result.Files[0].Strings[0].Value).Should(Equal("Hello from memory"))
+			Ω(memFS.Filenames()).Should(Equal([]string{"/out/main.go.en.json", "/src/app/main.go"}))
+
+			translations, err := common.LoadI18nStringInfosFS(memFS, "/out/main.go.en.json")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(translations).Should(Equal([]common.I18nStringInfo{{ID: "Hello from memory", Translation: "Hello from memory"}}))
+		})
+
+		It("extracts the strings of the overlay files instead of the files on disk", func() {
+			inputFilesPath := filepath.Join(fixturesPath, "extract_strings", "output_match_import", "input_files")
+			greetingsFilename := filepath.Join(inputFilesPath, "internal", "greetings", "greetings.go")
+
+			overlayFS := common.NewOverlayFS(common.OsFS{}, map[string][]byte{
+				greetingsFilename: []byte("package greetings\n\nfunc Hello() string {\n\treturn \"Hello unsaved world\"\n}\n"),
+			})
+
+			result, err := api.Extract(api.ExtractOptions{
+				Filename: greetingsFilename,
+				DryRun:   true,
+				FS:       overlayFS,
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(result.Files).Should(HaveLen(1))
+			Ω(result.Files[0].Strings[0].Value).Should(Equal("Hello unsaved world"))
+		})
+
+		It("records the writes of a dry run without writing the files", func() {
+			inputFilesPath := filepath.Join(fixturesPath, "rewrite_package", "diff_option", "input_files")
+			for _, fileName := range []string{"greetings.go", "farewells.go", "en.all.json"} {
+				CopyFile(filepath.Join(inputFilesPath, fileName), filepath.Join(tmpDir, fileName))
+			}
+
+			dryRunFS := common.NewDryRunFS(common.OsFS{})
+			result, err := api.Rewrite(api.RewriteOptions{
+				Dirname:             tmpDir,
+				I18nStringsFilename: filepath.Join(tmpDir, "en.all.json"),
+				RootPath:            tmpDir,
+				FS:                  dryRunFS,
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(dryRunFS.Written()).Should(ContainElement(filepath.Join(tmpDir, "greetings.go")))
+			Ω(dryRunFS.Written()).Should(ContainElement(filepath.Join(tmpDir, "i18n_init.go")))
+			for _, fileName := range result.WrittenFiles {
+				Ω(dryRunFS.Written()).Should(ContainElement(fileName))
+			}
+
+			rewrittenContent, err := dryRunFS.ReadFile(filepath.Join(tmpDir, "greetings.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(rewrittenContent)).Should(ContainSubstring(`T("Hello world!")`))
+
+			content, err := ioutil.ReadFile(filepath.Join(tmpDir, "greetings.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).ShouldNot(ContainSubstring(`T("Hello world!")`))
+			Ω(filepath.Join(tmpDir, "i18n_init.go")).ShouldNot(BeAnExistingFile())
+		})
+	})
+
+	Context("Logger", func() {
+		It("logs the warnings of the command with the logger of the options", func() {
+			memFS := common.NewMemFS(map[string][]byte{
+				"/src/app/main.go": []byte("package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"Hello from memory\")\n}\n"),
+			})
+
+			output := &bytes.Buffer{}
+			_, err := api.Extract(api.ExtractOptions{
+				Dirname:       "/src/app",
+				OutputDirname: "/out",
+				IgnoreRegexp:  "[",
+				FS:            memFS,
+				Logger:        &api.Logger{Level: common.WARN, Format: common.LOG_FORMAT_JSON, Writer: output},
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			var entry map[string]string
+			Ω(json.Unmarshal(output.Bytes(), &entry)).Should(Succeed())
+			Ω(entry["level"]).Should(Equal("warn"))
+			Ω(entry["msg"]).Should(HavePrefix("WARNING: fail to compile ignore-regexp:"))
+		})
+	})
+})
@@ -0,0 +1,78 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkup_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("checkup --cache-dir dirName", func() {
+	var (
+		curDir    string
+		cachePath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		curDir, err = os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		cachePath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		err = os.Chdir(filepath.Join("..", "..", "test_fixtures", "checkup", "allgood"))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		err := os.Chdir(curDir)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		os.RemoveAll(cachePath)
+	})
+
+	Context("Using legacy commands", func() {
+		It("loads the T() calls of the files which did not change from the cache", func() {
+			session := Runi18n("-c", "checkup", "-v", "--cache-dir", cachePath)
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session.Err.Contents()).ShouldNot(ContainSubstring("using the cached strings of file"))
+
+			session = Runi18n("-c", "checkup", "-v", "--cache-dir", cachePath)
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session.Err).Should(Say("using the cached strings of file: .*.go"))
+			Ω(session.Err).Should(Say("OK"))
+		})
+	})
+
+	Context("Using cobra commands", func() {
+		It("loads the T() calls of the files which did not change from the cache", func() {
+			session := Runi18n("checkup", "-v", "--cache-dir", cachePath)
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session.Err.Contents()).ShouldNot(ContainSubstring("using the cached strings of file"))
+
+			session = Runi18n("checkup", "-v", "--cache-dir", cachePath)
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session.Err).Should(Say("using the cached strings of file: .*.go"))
+			Ω(session.Err).Should(Say("OK"))
+		})
+	})
+})
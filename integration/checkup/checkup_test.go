@@ -66,7 +66,7 @@ var _ = Describe("checkup", func() {
 			})
 
 			It("prints a reassuring message", func() {
-				Ω(session).Should(Say("OK"))
+				Ω(session.Err).Should(Say("OK"))
 			})
 		})
 
@@ -85,7 +85,7 @@ var _ = Describe("checkup", func() {
 
 			It("prints a reassuring message", func() {
 				session = Runi18n("-c", "checkup", "-v", "-q", "i18n")
-				Ω(session).Should(Say("OK"))
+				Ω(session.Err).Should(Say("OK"))
 			})
 		})
 
@@ -103,7 +103,7 @@ var _ = Describe("checkup", func() {
 			})
 
 			It("prints a reassuring message", func() {
-				Ω(session).Should(Say("OK"))
+				Ω(session.Err).Should(Say("OK"))
 			})
 		})
 
@@ -117,7 +117,7 @@ var _ = Describe("checkup", func() {
 			})
 
 			It("shows all inconsistent strings and returns 1", func() {
-				output := string(session.Out.Contents())
+				output := string(session.Err.Contents())
 
 				// strings wrapped in T() in the code that don't have corresponding keys in the translation files
 				Ω(output).Should(ContainSubstring("\"Heal the world\" exists in the code, but not in en_US"))
@@ -131,7 +131,7 @@ var _ = Describe("checkup", func() {
 				// keys that exist in the english translation but are missing in non-english translations
 				Ω(output).Should(ContainSubstring("\"And the entire human race\" exists in en_US, but not in zh_CN"))
 
-				Ω(session.ExitCode()).Should(Equal(1))
+				Ω(session.ExitCode()).Should(Equal(2))
 			})
 		})
 
@@ -149,7 +149,7 @@ var _ = Describe("checkup", func() {
 			})
 
 			It("prints a reassuring message", func() {
-				Ω(session).Should(Say("OK"))
+				Ω(session.Err).Should(Say("OK"))
 			})
 		})
 
@@ -171,7 +171,7 @@ var _ = Describe("checkup", func() {
 			})
 
 			It("prints a reassuring message", func() {
-				Ω(session).Should(Say("OK"))
+				Ω(session.Err).Should(Say("OK"))
 			})
 		})
 
@@ -190,7 +190,7 @@ var _ = Describe("checkup", func() {
 
 			It("prints a reassuring message", func() {
 				session = Runi18n("-c", "checkup", "-v", "-q", "i18n")
-				Ω(session).Should(Say("OK"))
+				Ω(session.Err).Should(Say("OK"))
 			})
 		})
 
@@ -208,7 +208,7 @@ var _ = Describe("checkup", func() {
 			})
 
 			It("prints a reassuring message", func() {
-				Ω(session).Should(Say("OK"))
+				Ω(session.Err).Should(Say("OK"))
 			})
 		})
 
@@ -222,7 +222,7 @@ var _ = Describe("checkup", func() {
 			})
 
 			It("shows all inconsistent strings and returns 1", func() {
-				output := string(session.Out.Contents())
+				output := string(session.Err.Contents())
 
 				// strings wrapped in T() in the code that don't have corresponding keys in the translation files
 				Ω(output).Should(ContainSubstring("\"Heal the world\" exists in the code, but not in en_US"))
@@ -236,7 +236,7 @@ var _ = Describe("checkup", func() {
 				// keys that exist in the english translation but are missing in non-english translations
 				Ω(output).Should(ContainSubstring("\"And the entire human race\" exists in en_US, but not in zh_CN"))
 
-				Ω(session.ExitCode()).Should(Equal(1))
+				Ω(session.ExitCode()).Should(Equal(2))
 			})
 		})
 
@@ -254,7 +254,40 @@ var _ = Describe("checkup", func() {
 			})
 
 			It("prints a reassuring message", func() {
-				Ω(session).Should(Say("OK"))
+				Ω(session.Err).Should(Say("OK"))
+			})
+		})
+
+		Context("When translations violate the glossary", func() {
+			BeforeEach(func() {
+				fixturesPath = filepath.Join("..", "..", "test_fixtures", "checkup", "glossary")
+				err = os.Chdir(fixturesPath)
+				Ω(err).ToNot(HaveOccurred(), "Could not change to fixtures directory")
+
+				session = Runi18n("checkup", "-v", "--glossary", "glossary.json")
+			})
+
+			It("shows the key, locale and term of each violation and returns 1", func() {
+				output := string(session.Err.Contents())
+
+				Ω(output).Should(ContainSubstring("\"Push the app to Cloud Foundry\" in fr_FR violates the glossary for term \"Cloud Foundry\""))
+				Ω(output).Should(ContainSubstring("\"Push the app to Cloud Foundry\" in fr_FR violates the glossary for term \"appli\""))
+
+				Ω(session.ExitCode()).Should(Equal(2))
+			})
+		})
+
+		Context("When the glossary is not used", func() {
+			BeforeEach(func() {
+				fixturesPath = filepath.Join("..", "..", "test_fixtures", "checkup", "glossary")
+				err = os.Chdir(fixturesPath)
+				Ω(err).ToNot(HaveOccurred(), "Could not change to fixtures directory")
+
+				session = Runi18n("checkup", "-v")
+			})
+
+			It("ignores the glossary file and returns 0", func() {
+				Ω(session.ExitCode()).Should(Equal(0))
 			})
 		})
 
@@ -91,7 +91,7 @@ var _ = Describe("create-translations -f fileName --languages \"[lang,?]+\"", fu
 			Context("and file does not exist", func() {
 				BeforeEach(func() {
 					session := Runi18n("-c", "create-translations", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.de.json"), "--languages", "\"fr\"", "-o", expectedFilesPath, "--source-language", "zh_TW")
-					Ω(session.ExitCode()).Should(Equal(1))
+					Ω(session.ExitCode()).Should(Equal(4))
 				})
 
 				It("fails verification", func() {
@@ -103,7 +103,7 @@ var _ = Describe("create-translations -f fileName --languages \"[lang,?]+\"", fu
 			Context("and file is empty", func() {
 				BeforeEach(func() {
 					session := Runi18n("-c", "create-translations", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.ja.json"), "--languages", "\"fr\"", "-o", expectedFilesPath, "--source-language", "ja")
-					Ω(session.ExitCode()).Should(Equal(1))
+					Ω(session.ExitCode()).Should(Equal(2))
 				})
 
 				It("fails verification", func() {
@@ -166,7 +166,7 @@ var _ = Describe("create-translations -f fileName --languages \"[lang,?]+\"", fu
 			Context("and file does not exist", func() {
 				BeforeEach(func() {
 					session := Runi18n("create-translations", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.de.json"), "--languages", "\"fr\"", "-o", expectedFilesPath, "--source-language", "zh_TW")
-					Ω(session.ExitCode()).Should(Equal(1))
+					Ω(session.ExitCode()).Should(Equal(4))
 				})
 
 				It("fails verification", func() {
@@ -178,7 +178,7 @@ var _ = Describe("create-translations -f fileName --languages \"[lang,?]+\"", fu
 			Context("and file is empty", func() {
 				BeforeEach(func() {
 					session := Runi18n("create-translations", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.ja.json"), "--languages", "\"fr\"", "-o", expectedFilesPath, "--source-language", "ja")
-					Ω(session.ExitCode()).Should(Equal(1))
+					Ω(session.ExitCode()).Should(Equal(2))
 				})
 
 				It("fails verification", func() {
@@ -0,0 +1,125 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("extract-strings --cache-dir dirName", func() {
+	var (
+		inputPath         string
+		outputPath        string
+		cachePath         string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		inputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		cachePath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "extract_strings", "cobra")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+
+		CopyFile(filepath.Join(fixturesPath, "input_files", "cmd.go"), filepath.Join(inputPath, "cmd.go"))
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(inputPath)
+		os.RemoveAll(outputPath)
+		os.RemoveAll(cachePath)
+	})
+
+	appendString := func() {
+		file, err := os.OpenFile(filepath.Join(inputPath, "cmd.go"), os.O_APPEND|os.O_WRONLY, 0644)
+		Ω(err).ShouldNot(HaveOccurred())
+		defer file.Close()
+
+		_, err = file.WriteString("\nfunc farewell() string {\n\treturn \"Goodbye\"\n}\n")
+		Ω(err).ShouldNot(HaveOccurred())
+	}
+
+	Context("Using legacy commands", func() {
+		It("loads the strings of the files which did not change from the cache", func() {
+			session := Runi18n("-c", "extract-strings", "-v", "-f", filepath.Join(inputPath, "cmd.go"), "-o", outputPath, "--cache-dir", cachePath)
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session.Err.Contents()).ShouldNot(ContainSubstring("using the cached strings of file"))
+
+			session = Runi18n("-c", "extract-strings", "-v", "-f", filepath.Join(inputPath, "cmd.go"), "-o", outputPath, "--cache-dir", cachePath)
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session.Err).Should(Say("using the cached strings of file: .*cmd.go"))
+
+			CompareExpectedToGeneratedTraslationJson(
+				GetFilePath(expectedFilesPath, "cmd.go.en.json"),
+				filepath.Join(outputPath, "cmd.go.en.json"),
+			)
+		})
+
+		It("extracts the strings of the files which changed again", func() {
+			session := Runi18n("-c", "extract-strings", "-f", filepath.Join(inputPath, "cmd.go"), "-o", outputPath, "--cache-dir", cachePath)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			appendString()
+
+			session = Runi18n("-c", "extract-strings", "-v", "-f", filepath.Join(inputPath, "cmd.go"), "-o", outputPath, "--cache-dir", cachePath)
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session.Err.Contents()).ShouldNot(ContainSubstring("using the cached strings of file"))
+			Ω(ReadJson(filepath.Join(outputPath, "cmd.go.en.json"))).Should(HaveKeyWithValue("Goodbye", "Goodbye"))
+		})
+	})
+
+	Context("Using cobra commands", func() {
+		It("loads the strings of the files which did not change from the cache", func() {
+			session := Runi18n("extract-strings", "-v", "-f", filepath.Join(inputPath, "cmd.go"), "-o", outputPath, "--cache-dir", cachePath)
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session.Err.Contents()).ShouldNot(ContainSubstring("using the cached strings of file"))
+
+			session = Runi18n("extract-strings", "-v", "-f", filepath.Join(inputPath, "cmd.go"), "-o", outputPath, "--cache-dir", cachePath)
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session.Err).Should(Say("using the cached strings of file: .*cmd.go"))
+
+			CompareExpectedToGeneratedTraslationJson(
+				GetFilePath(expectedFilesPath, "cmd.go.en.json"),
+				filepath.Join(outputPath, "cmd.go.en.json"),
+			)
+		})
+
+		It("extracts the strings of the files which changed again", func() {
+			session := Runi18n("extract-strings", "-f", filepath.Join(inputPath, "cmd.go"), "-o", outputPath, "--cache-dir", cachePath)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			appendString()
+
+			session = Runi18n("extract-strings", "-v", "-f", filepath.Join(inputPath, "cmd.go"), "-o", outputPath, "--cache-dir", cachePath)
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session.Err.Contents()).ShouldNot(ContainSubstring("using the cached strings of file"))
+			Ω(ReadJson(filepath.Join(outputPath, "cmd.go.en.json"))).Should(HaveKeyWithValue("Goodbye", "Goodbye"))
+		})
+	})
+})
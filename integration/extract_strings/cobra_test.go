@@ -0,0 +1,72 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extract-strings -f fileName with cobra commands and flags", func() {
+	var (
+		outputPath        string
+		fixturesPath      string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath = filepath.Join("..", "..", "test_fixtures", "extract_strings", "cobra")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outputPath)
+	})
+
+	Context("Using legacy commands", func() {
+		It("extracts the help texts and usages but not the command and flag names", func() {
+			session := Runi18n("-c", "extract-strings", "-f", filepath.Join(inputFilesPath, "cmd.go"), "-o", outputPath)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			CompareExpectedToGeneratedTraslationJson(
+				GetFilePath(expectedFilesPath, "cmd.go.en.json"),
+				filepath.Join(outputPath, "cmd.go.en.json"),
+			)
+		})
+	})
+
+	Context("Using cobra commands", func() {
+		It("extracts the help texts and usages but not the command and flag names", func() {
+			session := Runi18n("extract-strings", "-f", filepath.Join(inputFilesPath, "cmd.go"), "-o", outputPath)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			CompareExpectedToGeneratedTraslationJson(
+				GetFilePath(expectedFilesPath, "cmd.go.en.json"),
+				filepath.Join(outputPath, "cmd.go.en.json"),
+			)
+		})
+	})
+})
@@ -0,0 +1,83 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extract-strings exit codes", func() {
+	var (
+		inputPath  string
+		outputPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		inputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		err = ioutil.WriteFile(filepath.Join(inputPath, "broken.go"), []byte("package main\n\nfunc main() {\n\tprintln(\"Hello\"\n"), 0644)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(inputPath)
+		os.RemoveAll(outputPath)
+	})
+
+	Context("Using legacy commands", func() {
+		It("exits with 3 when a go file cannot be parsed", func() {
+			session := Runi18n("-c", "extract-strings", "-f", filepath.Join(inputPath, "broken.go"), "-o", outputPath)
+			Ω(session.ExitCode()).Should(Equal(3))
+		})
+
+		It("exits with 4 when a go file cannot be read", func() {
+			session := Runi18n("-c", "extract-strings", "-f", filepath.Join(inputPath, "missing.go"), "-o", outputPath)
+			Ω(session.ExitCode()).Should(Equal(4))
+		})
+
+		It("exits with 1 when a flag value is invalid", func() {
+			session := Runi18n("-c", "extract-strings", "-f", filepath.Join(inputPath, "broken.go"), "-o", outputPath, "--key-format", "uuid")
+			Ω(session.ExitCode()).Should(Equal(1))
+		})
+	})
+
+	Context("Using cobra commands", func() {
+		It("exits with 3 when a go file cannot be parsed", func() {
+			session := Runi18n("extract-strings", "-f", filepath.Join(inputPath, "broken.go"), "-o", outputPath)
+			Ω(session.ExitCode()).Should(Equal(3))
+		})
+
+		It("exits with 4 when a go file cannot be read", func() {
+			session := Runi18n("extract-strings", "-f", filepath.Join(inputPath, "missing.go"), "-o", outputPath)
+			Ω(session.ExitCode()).Should(Equal(4))
+		})
+
+		It("exits with 1 when a flag value is invalid", func() {
+			session := Runi18n("extract-strings", "-f", filepath.Join(inputPath, "broken.go"), "-o", outputPath, "--key-format", "uuid")
+			Ω(session.ExitCode()).Should(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,93 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extract-strings --jobs count", func() {
+	var (
+		inputFilesPath string
+		sequentialPath string
+		concurrentPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		sequentialPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		concurrentPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		inputFilesPath = filepath.Join("..", "..", "test_fixtures", "extract_strings", "d_option", "input_files")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(sequentialPath)
+		os.RemoveAll(concurrentPath)
+	})
+
+	compareGeneratedFiles := func() {
+		fileInfos, err := ioutil.ReadDir(sequentialPath)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(fileInfos).ShouldNot(BeEmpty())
+
+		for _, fileInfo := range fileInfos {
+			sequentialContent, err := ioutil.ReadFile(filepath.Join(sequentialPath, fileInfo.Name()))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			concurrentContent, err := ioutil.ReadFile(filepath.Join(concurrentPath, fileInfo.Name()))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(string(concurrentContent)).Should(Equal(string(sequentialContent)), fileInfo.Name())
+		}
+
+		concurrentFileInfos, err := ioutil.ReadDir(concurrentPath)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(concurrentFileInfos).Should(HaveLen(len(fileInfos)))
+	}
+
+	Context("Using legacy commands", func() {
+		It("generates the same files whatever the number of files extracted concurrently", func() {
+			session := Runi18n("-c", "extract-strings", "-d", inputFilesPath, "-r", "-o", sequentialPath, "--key-format", "slug", "--ignore-regexp", "^[.]\\w+.go$", "--jobs", "1")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			session = Runi18n("-c", "extract-strings", "-d", inputFilesPath, "-r", "-o", concurrentPath, "--key-format", "slug", "--ignore-regexp", "^[.]\\w+.go$", "--jobs", "8")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			compareGeneratedFiles()
+		})
+	})
+
+	Context("Using cobra commands", func() {
+		It("generates the same files whatever the number of files extracted concurrently", func() {
+			session := Runi18n("extract-strings", "-d", inputFilesPath, "-r", "-o", sequentialPath, "--key-format", "slug", "--ignore-regexp", "^[.]\\w+.go$", "--jobs", "1")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			session = Runi18n("extract-strings", "-d", inputFilesPath, "-r", "-o", concurrentPath, "--key-format", "slug", "--ignore-regexp", "^[.]\\w+.go$", "--jobs", "8")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			compareGeneratedFiles()
+		})
+	})
+})
@@ -0,0 +1,102 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extract-strings --key-format keyFormat", func() {
+	var (
+		outputPath        string
+		fixturesPath      string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath = filepath.Join("..", "..", "test_fixtures", "extract_strings", "key_format")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outputPath)
+	})
+
+	Context("Using legacy commands", func() {
+		It("generates package scoped slug keys", func() {
+			session := Runi18n("-c", "extract-strings", "-f", filepath.Join(inputFilesPath, "app.go"), "-o", outputPath, "--key-format", "slug")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			CompareExpectedToGeneratedTraslationJson(
+				GetFilePath(expectedFilesPath, "app.go.slug.en.json"),
+				filepath.Join(outputPath, "app.go.en.json"),
+			)
+		})
+
+		It("generates package scoped hash keys", func() {
+			session := Runi18n("-c", "extract-strings", "-f", filepath.Join(inputFilesPath, "app.go"), "-o", outputPath, "--key-format", "hash")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			CompareExpectedToGeneratedTraslationJson(
+				GetFilePath(expectedFilesPath, "app.go.hash.en.json"),
+				filepath.Join(outputPath, "app.go.en.json"),
+			)
+		})
+
+		It("fails with an unknown key format", func() {
+			session := Runi18n("-c", "extract-strings", "-f", filepath.Join(inputFilesPath, "app.go"), "-o", outputPath, "--key-format", "uuid")
+			Ω(session.ExitCode()).ShouldNot(Equal(0))
+		})
+	})
+
+	Context("Using cobra commands", func() {
+		It("generates package scoped slug keys", func() {
+			session := Runi18n("extract-strings", "-f", filepath.Join(inputFilesPath, "app.go"), "-o", outputPath, "--key-format", "slug")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			CompareExpectedToGeneratedTraslationJson(
+				GetFilePath(expectedFilesPath, "app.go.slug.en.json"),
+				filepath.Join(outputPath, "app.go.en.json"),
+			)
+		})
+
+		It("generates package scoped hash keys", func() {
+			session := Runi18n("extract-strings", "-f", filepath.Join(inputFilesPath, "app.go"), "-o", outputPath, "--key-format", "hash")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			CompareExpectedToGeneratedTraslationJson(
+				GetFilePath(expectedFilesPath, "app.go.hash.en.json"),
+				filepath.Join(outputPath, "app.go.en.json"),
+			)
+		})
+
+		It("fails with an unknown key format", func() {
+			session := Runi18n("extract-strings", "-f", filepath.Join(inputFilesPath, "app.go"), "-o", outputPath, "--key-format", "uuid")
+			Ω(session.ExitCode()).ShouldNot(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,172 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extract_strings_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("extract-strings --quiet --log-level --log-format", func() {
+	var (
+		inputFilesPath string
+		outputPath     string
+	)
+
+	BeforeEach(func() {
+		var err error
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		inputFilesPath = filepath.Join("..", "..", "test_fixtures", "extract_strings", "f_option", "input_files")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outputPath)
+	})
+
+	jsonEntries := func(output []byte) []map[string]string {
+		var entries []map[string]string
+		for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+			var entry map[string]string
+			Ω(json.Unmarshal([]byte(line), &entry)).Should(Succeed(), line)
+			Ω(entry).Should(HaveKey("time"))
+			entries = append(entries, entry)
+		}
+
+		return entries
+	}
+
+	Context("Using legacy commands", func() {
+		It("logs the warnings and errors by default", func() {
+			session := Runi18n("-c", "extract-strings", "-d", inputFilesPath, "-o", outputPath, "--ignore-regexp", "[")
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session.Err).Should(Say("WARNING: fail to compile ignore-regexp:"))
+			Ω(session.Err.Contents()).ShouldNot(ContainSubstring("Extracting strings in package:"))
+			Ω(session.Err.Contents()).ShouldNot(ContainSubstring("Total files parsed:"))
+		})
+
+		It("logs only the errors with --quiet", func() {
+			session := Runi18n("-c", "extract-strings", "-d", inputFilesPath, "-o", outputPath, "--ignore-regexp", "[", "-v", "--quiet")
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session.Err.Contents()).Should(BeEmpty())
+		})
+
+		It("logs the summary with --log-level info", func() {
+			session := Runi18n("-c", "extract-strings", "-d", inputFilesPath, "-o", outputPath, "--ignore-regexp", "_test.go$", "--log-level", "info")
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session.Err).Should(Say("Total files parsed: 5"))
+			Ω(session.Err.Contents()).ShouldNot(ContainSubstring("Extracting strings in package:"))
+		})
+
+		It("logs JSON objects with --log-format json", func() {
+			session := Runi18n("-c", "extract-strings", "-d", inputFilesPath, "-o", outputPath, "--ignore-regexp", "[", "-v", "--log-format", "json")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			levels := map[string]bool{}
+			for _, entry := range jsonEntries(session.Err.Contents()) {
+				levels[entry["level"]] = true
+				if entry["level"] == "warn" {
+					Ω(entry["msg"]).Should(HavePrefix("WARNING: fail to compile ignore-regexp:"))
+				}
+			}
+			Ω(levels).Should(HaveKey("warn"))
+			Ω(levels).Should(HaveKey("info"))
+			Ω(levels).Should(HaveKey("debug"))
+		})
+
+		It("fails with an unknown log level", func() {
+			session := Runi18n("-c", "extract-strings", "-d", inputFilesPath, "-o", outputPath, "--log-level", "chatty")
+			Ω(session.ExitCode()).Should(Equal(1))
+			Ω(session.Err).Should(Say("i18n4go: unknown log level chatty, one of: error, warn, info, debug"))
+		})
+
+		It("fails with an unknown log format", func() {
+			session := Runi18n("-c", "extract-strings", "-d", inputFilesPath, "-o", outputPath, "--log-format", "xml")
+			Ω(session.ExitCode()).Should(Equal(1))
+			Ω(session.Err).Should(Say("i18n4go: unknown log format xml, one of: text, json"))
+		})
+	})
+
+	Context("Using cobra commands", func() {
+		It("logs the warnings and errors by default", func() {
+			session := Runi18n("extract-strings", "-d", inputFilesPath, "-o", outputPath, "--ignore-regexp", "[")
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session.Err).Should(Say("WARNING: fail to compile ignore-regexp:"))
+			Ω(session.Err.Contents()).ShouldNot(ContainSubstring("Extracting strings in package:"))
+			Ω(session.Err.Contents()).ShouldNot(ContainSubstring("Total files parsed:"))
+		})
+
+		It("logs only the errors with --quiet", func() {
+			session := Runi18n("extract-strings", "-d", inputFilesPath, "-o", outputPath, "--ignore-regexp", "[", "-v", "--quiet")
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session.Err.Contents()).Should(BeEmpty())
+		})
+
+		It("logs the summary with --log-level info", func() {
+			session := Runi18n("extract-strings", "-d", inputFilesPath, "-o", outputPath, "--ignore-regexp", "_test.go$", "--log-level", "info")
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session.Err).Should(Say("Total files parsed: 5"))
+			Ω(session.Err.Contents()).ShouldNot(ContainSubstring("Extracting strings in package:"))
+		})
+
+		It("logs JSON objects with --log-format json", func() {
+			session := Runi18n("extract-strings", "-d", inputFilesPath, "-o", outputPath, "--ignore-regexp", "[", "-v", "--log-format", "json")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			levels := map[string]bool{}
+			for _, entry := range jsonEntries(session.Err.Contents()) {
+				levels[entry["level"]] = true
+				if entry["level"] == "warn" {
+					Ω(entry["msg"]).Should(HavePrefix("WARNING: fail to compile ignore-regexp:"))
+				}
+			}
+			Ω(levels).Should(HaveKey("warn"))
+			Ω(levels).Should(HaveKey("info"))
+			Ω(levels).Should(HaveKey("debug"))
+		})
+
+		It("logs the error of the command once as a JSON object with --log-format json", func() {
+			session := Runi18n("extract-strings", "-f", filepath.Join(inputFilesPath, "missing.go"), "-o", outputPath, "--log-format", "json")
+			Ω(session.ExitCode()).Should(Equal(4))
+			Ω(session.Out.Contents()).Should(BeEmpty())
+
+			entries := jsonEntries(session.Err.Contents())
+			Ω(entries).Should(HaveLen(1))
+			Ω(entries[0]["level"]).Should(Equal("error"))
+			Ω(entries[0]["msg"]).Should(ContainSubstring("missing.go: no such file or directory"))
+		})
+
+		It("fails with an unknown log level", func() {
+			session := Runi18n("extract-strings", "-d", inputFilesPath, "-o", outputPath, "--log-level", "chatty")
+			Ω(session.ExitCode()).Should(Equal(1))
+			Ω(session.Err).Should(Say("i18n4go: unknown log level chatty, one of: error, warn, info, debug"))
+		})
+
+		It("fails with an unknown log format", func() {
+			session := Runi18n("extract-strings", "-d", inputFilesPath, "-o", outputPath, "--log-format", "xml")
+			Ω(session.ExitCode()).Should(Equal(1))
+			Ω(session.Err).Should(Say("i18n4go: unknown log format xml, one of: text, json"))
+		})
+	})
+})
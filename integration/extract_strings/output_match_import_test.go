@@ -0,0 +1,77 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extract-strings --output-match-import", func() {
+	var (
+		outputPath        string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "extract_strings", "output_match_import")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outputPath)
+	})
+
+	compareGeneratedFiles := func() {
+		for _, fileName := range []string{
+			filepath.Join("example.com", "greeter", "main.go.en.json"),
+			filepath.Join("example.com", "greeter", "internal", "greetings", "greetings.go.en.json"),
+		} {
+			CompareExpectedToGeneratedTraslationJson(
+				GetFilePath(expectedFilesPath, fileName),
+				filepath.Join(outputPath, fileName),
+			)
+		}
+	}
+
+	Context("Using legacy commands", func() {
+		It("creates the files in directories matching the import paths of the module packages", func() {
+			session := Runi18n("-c", "extract-strings", "-d", inputFilesPath, "-r", "--output-match-import", "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			compareGeneratedFiles()
+		})
+	})
+
+	Context("Using cobra commands", func() {
+		It("creates the files in directories matching the import paths of the module packages", func() {
+			session := Runi18n("extract-strings", "-d", inputFilesPath, "-r", "--output-match-import", "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			compareGeneratedFiles()
+		})
+	})
+})
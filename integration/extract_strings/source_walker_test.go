@@ -0,0 +1,162 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extract-strings source walker", func() {
+	var (
+		inputPath         string
+		outputPath        string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		inputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "extract_strings", "source_walker")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+
+		// the input files are copied since the .gitignore of the fixtures would ignore them in this repository
+		inputFilesPath := filepath.Join(fixturesPath, "input_files")
+		err = filepath.Walk(inputFilesPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+
+			relPath, err := filepath.Rel(inputFilesPath, path)
+			if err != nil {
+				return err
+			}
+			if relPath == "gitignore" {
+				relPath = ".gitignore"
+			}
+
+			destPath := filepath.Join(inputPath, relPath)
+			err = os.MkdirAll(filepath.Dir(destPath), 0755)
+			if err != nil {
+				return err
+			}
+			CopyFile(path, destPath)
+			return nil
+		})
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(inputPath)
+		os.RemoveAll(outputPath)
+	})
+
+	compareGeneratedFiles := func(extractedFileNames []string, skippedFileNames []string) {
+		for _, fileName := range extractedFileNames {
+			CompareExpectedToGeneratedTraslationJson(
+				GetFilePath(expectedFilesPath, fileName+".en.json"),
+				filepath.Join(outputPath, fileName+".en.json"),
+			)
+		}
+
+		for _, fileName := range skippedFileNames {
+			Ω(filepath.Join(outputPath, fileName+".en.json")).ShouldNot(BeAnExistingFile())
+		}
+	}
+
+	Context("Using legacy commands", func() {
+		It("skips the vendor, testdata, ignored, generated and not built files by default", func() {
+			session := Runi18n("-c", "extract-strings", "-d", inputPath, "-r", "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			compareGeneratedFiles(
+				[]string{"main.go"},
+				[]string{"config_local.go", "output.go", "lib.go", "sample.go", "generated.go", "windows.go", "feature.go"},
+			)
+		})
+
+		It("evaluates the build constraints with the GOOS, GOARCH and build tags", func() {
+			session := Runi18n("-c", "extract-strings", "-d", inputPath, "-r", "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$", "--goos", "windows", "--goarch", "amd64", "--tags", "feature")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			compareGeneratedFiles(
+				[]string{"main.go", "windows.go", "feature.go"},
+				[]string{"config_local.go", "output.go", "lib.go", "sample.go", "generated.go"},
+			)
+		})
+
+		It("includes the sources skipped by default", func() {
+			session := Runi18n("-c", "extract-strings", "-d", inputPath, "-r", "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$", "--include", "vendor,testdata,generated,gitignored")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			compareGeneratedFiles(
+				[]string{"main.go", "config_local.go", "output.go", "lib.go", "sample.go", "generated.go"},
+				[]string{"windows.go", "feature.go"},
+			)
+		})
+
+		It("fails with an unknown include", func() {
+			session := Runi18n("-c", "extract-strings", "-d", inputPath, "-r", "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$", "--include", "examples")
+			Ω(session.ExitCode()).ShouldNot(Equal(0))
+		})
+	})
+
+	Context("Using cobra commands", func() {
+		It("skips the vendor, testdata, ignored, generated and not built files by default", func() {
+			session := Runi18n("extract-strings", "-d", inputPath, "-r", "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			compareGeneratedFiles(
+				[]string{"main.go"},
+				[]string{"config_local.go", "output.go", "lib.go", "sample.go", "generated.go", "windows.go", "feature.go"},
+			)
+		})
+
+		It("evaluates the build constraints with the GOOS, GOARCH and build tags", func() {
+			session := Runi18n("extract-strings", "-d", inputPath, "-r", "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$", "--goos", "windows", "--goarch", "amd64", "--tags", "feature")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			compareGeneratedFiles(
+				[]string{"main.go", "windows.go", "feature.go"},
+				[]string{"config_local.go", "output.go", "lib.go", "sample.go", "generated.go"},
+			)
+		})
+
+		It("includes the sources skipped by default", func() {
+			session := Runi18n("extract-strings", "-d", inputPath, "-r", "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$", "--include", "vendor,testdata,generated,gitignored")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			compareGeneratedFiles(
+				[]string{"main.go", "config_local.go", "output.go", "lib.go", "sample.go", "generated.go"},
+				[]string{"windows.go", "feature.go"},
+			)
+		})
+
+		It("fails with an unknown include", func() {
+			session := Runi18n("extract-strings", "-d", inputPath, "-r", "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$", "--include", "examples")
+			Ω(session.ExitCode()).ShouldNot(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,111 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extract-strings with templates", func() {
+	var (
+		outputPath        string
+		fixturesPath      string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath = filepath.Join("..", "..", "test_fixtures", "extract_strings", "templates")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outputPath)
+	})
+
+	compareExtractedStrings := func(fileNames ...string) {
+		for _, fileName := range fileNames {
+			CompareExpectedToGeneratedTraslationJson(
+				filepath.Join(expectedFilesPath, fileName+".en.json"),
+				filepath.Join(outputPath, fileName+".en.json"),
+			)
+
+			CompareExpectedToGeneratedExtendedJson(
+				filepath.Join(expectedFilesPath, fileName+".extracted.json"),
+				filepath.Join(outputPath, fileName+".extracted.json"),
+			)
+		}
+	}
+
+	Context("Using legacy commands", func() {
+		It("extracts the strings of the template files and of the embedded templates with the go files", func() {
+			session := Runi18n("-c", "extract-strings", "--meta", "-d", inputFilesPath, "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			compareExtractedStrings("app.go", "mail.gohtml", "welcome.txt")
+
+			_, err := os.Stat(filepath.Join(outputPath, "data.json.en.json"))
+			Ω(os.IsNotExist(err)).Should(BeTrue())
+		})
+
+		It("extracts the strings of a template file", func() {
+			session := Runi18n("-c", "extract-strings", "--meta", "-f", filepath.Join(inputFilesPath, "mail.gohtml"), "-o", outputPath)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			compareExtractedStrings("mail.gohtml")
+		})
+
+		It("does not extract the template files of other extensions", func() {
+			session := Runi18n("-c", "extract-strings", "-d", inputFilesPath, "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$", "--template-extensions", ".tmpl")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			_, err := os.Stat(filepath.Join(outputPath, "mail.gohtml.en.json"))
+			Ω(os.IsNotExist(err)).Should(BeTrue())
+		})
+	})
+
+	Context("Using cobra commands", func() {
+		It("extracts the strings of the template files and of the embedded templates with the go files", func() {
+			session := Runi18n("extract-strings", "--meta", "-d", inputFilesPath, "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			compareExtractedStrings("app.go", "mail.gohtml", "welcome.txt")
+
+			_, err := os.Stat(filepath.Join(outputPath, "data.json.en.json"))
+			Ω(os.IsNotExist(err)).Should(BeTrue())
+		})
+
+		It("extracts the string args of the calls to the template functions", func() {
+			session := Runi18n("extract-strings", "-f", filepath.Join(inputFilesPath, "templates", "welcome.txt"), "-o", outputPath, "--t-func-names", "tr")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			bytes, err := ioutil.ReadFile(filepath.Join(outputPath, "welcome.txt.en.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(bytes)).Should(ContainSubstring(`"You are an administrator."`))
+			Ω(string(bytes)).ShouldNot(ContainSubstring(`"You are a user."`))
+		})
+	})
+})
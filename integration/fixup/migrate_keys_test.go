@@ -0,0 +1,128 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fixup_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("fixup --migrate-keys", func() {
+	var (
+		outputDir         string
+		fixturesPath      string
+		inputFilesPath    string
+		expectedFilesPath string
+		session           *Session
+	)
+
+	BeforeEach(func() {
+		var err error
+		outputDir, err = ioutil.TempDir("", "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath = filepath.Join("..", "..", "test_fixtures", "fixup", "migrate_keys")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+
+		for _, dirName := range []string{filepath.Join("src", "code"), "translations"} {
+			err = os.MkdirAll(filepath.Join(outputDir, dirName), 0755)
+			Ω(err).ShouldNot(HaveOccurred())
+		}
+
+		for _, fileName := range migratedFileNames() {
+			CopyFile(filepath.Join(inputFilesPath, fileName), filepath.Join(outputDir, fileName))
+		}
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("Using legacy commands", func() {
+		It("migrates the IDs of all locales and source files to slug keys", func() {
+			session = Runi18n("-c", "fixup",
+				"--source", filepath.Join(outputDir, "src"),
+				"--resource", filepath.Join(outputDir, "translations"),
+				"--migrate-keys", "--key-format", "slug",
+			)
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session).Should(Say("Migrated 2 IDs to slug keys"))
+
+			for _, fileName := range migratedFileNames() {
+				CompareExpectedOutputToGeneratedOutput(filepath.Join(expectedFilesPath, fileName), filepath.Join(outputDir, fileName))
+			}
+		})
+
+		It("fails without a slug or hash key format", func() {
+			session = Runi18n("-c", "fixup",
+				"--source", filepath.Join(outputDir, "src"),
+				"--resource", filepath.Join(outputDir, "translations"),
+				"--migrate-keys",
+			)
+			Ω(session.ExitCode()).ShouldNot(Equal(0))
+		})
+	})
+
+	Context("Using cobra commands", func() {
+		It("migrates the IDs of all locales and source files to slug keys", func() {
+			session = Runi18n("fixup",
+				"--source", filepath.Join(outputDir, "src"),
+				"--resource", filepath.Join(outputDir, "translations"),
+				"--migrate-keys", "--key-format", "slug",
+			)
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session).Should(Say("Migrated 2 IDs to slug keys"))
+
+			for _, fileName := range migratedFileNames() {
+				CompareExpectedOutputToGeneratedOutput(filepath.Join(expectedFilesPath, fileName), filepath.Join(outputDir, fileName))
+			}
+		})
+
+		It("leaves already migrated files unchanged", func() {
+			for _, fileName := range migratedFileNames() {
+				CopyFile(filepath.Join(expectedFilesPath, fileName), filepath.Join(outputDir, fileName))
+			}
+
+			session = Runi18n("fixup",
+				"--source", filepath.Join(outputDir, "src"),
+				"--resource", filepath.Join(outputDir, "translations"),
+				"--migrate-keys", "--key-format", "slug",
+			)
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session).Should(Say("Migrated 0 IDs to slug keys"))
+
+			for _, fileName := range migratedFileNames() {
+				CompareExpectedOutputToGeneratedOutput(filepath.Join(expectedFilesPath, fileName), filepath.Join(outputDir, fileName))
+			}
+		})
+	})
+})
+
+func migratedFileNames() []string {
+	return []string{
+		filepath.Join("src", "code", "main.go"),
+		filepath.Join("translations", "all.en_US.json"),
+		filepath.Join("translations", "all.zh_CN.json"),
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generate_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("generate", func() {
+	var (
+		outputDir         string
+		rootPath          string
+		fixturesPath      string
+		inputFilesPath    string
+		expectedFilesPath string
+		session           *Session
+	)
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+		rootPath = filepath.Join(dir, "..", "..")
+
+		outputDir, err = ioutil.TempDir(rootPath, "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath = filepath.Join("..", "..", "test_fixtures", "generate")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("Using legacy commands", func() {
+		BeforeEach(func() {
+			session = Runi18n("-c", "generate",
+				"-f", filepath.Join(inputFilesPath, "en_US.all.json"),
+				"-o", filepath.Join(outputDir, "messages"),
+			)
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("generates one function per message with the template args as parameters", func() {
+			CompareExpectedOutputToGeneratedOutput(
+				filepath.Join(expectedFilesPath, "i18n_messages.go"),
+				filepath.Join(outputDir, "messages", "i18n_messages.go"),
+			)
+		})
+
+		It("generates a package which compiles", func() {
+			buildSession := RunCommand("go", "build", filepath.Join(outputDir, "messages"))
+			Ω(buildSession.ExitCode()).Should(Equal(0))
+		})
+	})
+
+	Context("Using cobra commands", func() {
+		It("generates one function per message with the template args as parameters", func() {
+			session = Runi18n("generate",
+				"-f", filepath.Join(inputFilesPath, "en_US.all.json"),
+				"-o", outputDir,
+				"--package", "messages",
+			)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			CompareExpectedOutputToGeneratedOutput(
+				filepath.Join(expectedFilesPath, "i18n_messages.go"),
+				filepath.Join(outputDir, "i18n_messages.go"),
+			)
+		})
+
+		It("prints the generated code with --dry-run", func() {
+			session = Runi18n("generate",
+				"-f", filepath.Join(inputFilesPath, "en_US.all.json"),
+				"-o", outputDir,
+				"--package", "messages",
+				"--dry-run",
+			)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			bytes, err := ioutil.ReadFile(filepath.Join(expectedFilesPath, "i18n_messages.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(session.Out.Contents())).Should(Equal(string(bytes)))
+
+			_, err = os.Stat(filepath.Join(outputDir, "i18n_messages.go"))
+			Ω(os.IsNotExist(err)).Should(BeTrue())
+		})
+
+		It("skips the IDs with printf verbs with a warning", func() {
+			session = Runi18n("generate",
+				"-f", filepath.Join(inputFilesPath, "en_US.all.json"),
+				"-o", outputDir,
+				"--package", "messages",
+			)
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session.Err).Should(Say("WARNING: skipping the ID %s whose printf verbs cannot be generated as parameters"))
+			Ω(session.Err).Should(Say("WARNING: skipping the ID Hello %s whose printf verbs cannot be generated as parameters"))
+		})
+
+		It("fails with an invalid package name", func() {
+			session = Runi18n("generate",
+				"-f", filepath.Join(inputFilesPath, "en_US.all.json"),
+				"-o", outputDir,
+				"--package", "my-messages",
+			)
+			Ω(session.ExitCode()).ShouldNot(Equal(0))
+		})
+	})
+})
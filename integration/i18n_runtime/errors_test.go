@@ -0,0 +1,50 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n_runtime_test
+
+import (
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("i18n.Errorf", func() {
+	var session *Session
+
+	BeforeEach(func() {
+		resourcesPath := filepath.Join("..", "..", "test_fixtures", "i18n_runtime", "errors", "resources")
+		command := exec.Command(errorsExec, resourcesPath, "fr_FR", "missing_%w_100%%.txt")
+
+		var err error
+		session, err = Start(command, GinkgoWriter, GinkgoWriter)
+		Ω(err).ShouldNot(HaveOccurred())
+		session.Wait()
+
+		Ω(session.ExitCode()).Should(Equal(0))
+	})
+
+	It("replaces the wrapped arg with the wrapped error message in the translated message, keeping the other args as is", func() {
+		Ω(session).Should(Say("Impossible d'ouvrir missing_%w_100%%.txt à 100% : open missing_%w_100%%.txt: no such file or directory"))
+	})
+
+	It("keeps the wrapped errors inspectable with errors.Is and errors.As", func() {
+		Ω(session).Should(Say("is not exist: true"))
+		Ω(session).Should(Say("as path error: true"))
+	})
+})
@@ -0,0 +1,39 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n_runtime_test
+
+import (
+	"github.com/onsi/gomega/gexec"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+var detectorExec, errorsExec string
+
+func TestI18nRuntime(t *testing.T) {
+	BeforeSuite(func() {
+		var err error
+		detectorExec, err = gexec.Build("./../../test_fixtures/i18n_runtime/detector")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		errorsExec, err = gexec.Build("./../../test_fixtures/i18n_runtime/errors")
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "i18n runtime Suite")
+}
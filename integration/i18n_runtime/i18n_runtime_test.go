@@ -0,0 +1,81 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n_runtime_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("i18n.Init with detectors", func() {
+	var resourcesPath string
+
+	BeforeEach(func() {
+		resourcesPath = filepath.Join("..", "..", "test_fixtures", "i18n_runtime", "detector", "resources")
+	})
+
+	runDetector := func(env []string, args ...string) *Session {
+		command := exec.Command(detectorExec, append([]string{resourcesPath}, args...)...)
+		command.Env = append(os.Environ(), env...)
+
+		session, err := Start(command, GinkgoWriter, GinkgoWriter)
+		Ω(err).ShouldNot(HaveOccurred())
+		session.Wait()
+
+		Ω(session.ExitCode()).Should(Equal(0))
+		return session
+	}
+
+	Context("FixedDetector", func() {
+		It("loads the translations of the locale", func() {
+			Ω(runDetector(nil, "fixed", "fr_FR")).Should(Say("Bonjour le monde"))
+		})
+
+		It("loads the translations of the supported locale of the language", func() {
+			Ω(runDetector(nil, "fixed", "de")).Should(Say("Hallo Welt"))
+		})
+
+		It("falls back to en_US for locales without translations", func() {
+			Ω(runDetector(nil, "fixed", "it_IT")).Should(Say("Hello world"))
+		})
+	})
+
+	Context("EnvDetector", func() {
+		It("uses LC_ALL before LANG", func() {
+			Ω(runDetector([]string{"LC_ALL=de_DE.UTF-8", "LANG=fr_FR.UTF-8"}, "env")).Should(Say("Hallo Welt"))
+		})
+
+		It("ignores the C locale", func() {
+			Ω(runDetector([]string{"LC_ALL=C", "LC_MESSAGES=", "LANG=fr_FR.UTF-8@euro"}, "env")).Should(Say("Bonjour le monde"))
+		})
+
+		It("reads the given environment variables", func() {
+			Ω(runDetector([]string{"APP_LOCALE=fr-FR", "LC_ALL=de_DE.UTF-8"}, "env", "APP_LOCALE")).Should(Say("Bonjour le monde"))
+		})
+	})
+
+	Context("with several detectors", func() {
+		It("uses the first detector which detects a locale", func() {
+			Ω(runDetector([]string{"APP_LOCALE=fr_FR"}, "chain", "APP_LOCALE", "de_DE")).Should(Say("Bonjour le monde"))
+			Ω(runDetector([]string{"APP_LOCALE="}, "chain", "APP_LOCALE", "de_DE")).Should(Say("Hallo Welt"))
+		})
+	})
+})
@@ -0,0 +1,107 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rewrite_package_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rewrite-package -f fileName with cobra commands and flags", func() {
+	var (
+		outputDir         string
+		rootPath          string
+		fixturesPath      string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+		rootPath = filepath.Join(dir, "..", "..")
+
+		outputDir, err = ioutil.TempDir(rootPath, "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package")
+		inputFilesPath = filepath.Join(fixturesPath, "cobra", "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "cobra", "expected_output")
+
+		CopyFile(filepath.Join(inputFilesPath, "en.all.json"), filepath.Join(outputDir, "en.all.json"))
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("Using legacy commands", func() {
+		It("wraps the help texts and usages but not the command and flag names", func() {
+			session := Runi18n("-c", "rewrite-package",
+				"-f", filepath.Join(inputFilesPath, "cmd.go"),
+				"-o", outputDir,
+				"--root-path", outputDir,
+			)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			CompareExpectedOutputToGeneratedOutput(filepath.Join(expectedFilesPath, "cmd.go"), filepath.Join(outputDir, "cmd.go"))
+		})
+
+		It("wraps the strings of an i18n strings file extracted from the commands", func() {
+			session := Runi18n("-c", "rewrite-package",
+				"-f", filepath.Join(inputFilesPath, "cmd.go"),
+				"-o", outputDir,
+				"--i18n-strings-filename", filepath.Join(outputDir, "en.all.json"),
+				"--root-path", outputDir,
+			)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			CompareExpectedOutputToGeneratedOutput(filepath.Join(expectedFilesPath, "cmd.go"), filepath.Join(outputDir, "cmd.go"))
+			CompareExpectedOutputToGeneratedOutput(filepath.Join(inputFilesPath, "en.all.json"), filepath.Join(outputDir, "en.all.json"))
+		})
+	})
+
+	Context("Using cobra commands", func() {
+		It("wraps the help texts and usages but not the command and flag names", func() {
+			session := Runi18n("rewrite-package",
+				"-f", filepath.Join(inputFilesPath, "cmd.go"),
+				"-o", outputDir,
+				"--root-path", outputDir,
+			)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			CompareExpectedOutputToGeneratedOutput(filepath.Join(expectedFilesPath, "cmd.go"), filepath.Join(outputDir, "cmd.go"))
+		})
+
+		It("wraps the strings of an i18n strings file extracted from the commands", func() {
+			session := Runi18n("rewrite-package",
+				"-f", filepath.Join(inputFilesPath, "cmd.go"),
+				"-o", outputDir,
+				"--i18n-strings-filename", filepath.Join(outputDir, "en.all.json"),
+				"--root-path", outputDir,
+			)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			CompareExpectedOutputToGeneratedOutput(filepath.Join(expectedFilesPath, "cmd.go"), filepath.Join(outputDir, "cmd.go"))
+			CompareExpectedOutputToGeneratedOutput(filepath.Join(inputFilesPath, "en.all.json"), filepath.Join(outputDir, "en.all.json"))
+		})
+	})
+})
@@ -0,0 +1,144 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rewrite_package_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("rewrite-package --diff", func() {
+	var (
+		outputDir         string
+		rootPath          string
+		fixturesPath      string
+		inputFilesPath    string
+		expectedFilesPath string
+		session           *Session
+	)
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+		rootPath = filepath.Join(dir, "..", "..")
+
+		outputDir, err = ioutil.TempDir(rootPath, "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package")
+		inputFilesPath = filepath.Join(fixturesPath, "diff_option", "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "diff_option", "expected_output")
+
+		for _, fileName := range []string{"greetings.go", "farewells.go", "en.all.json"} {
+			CopyFile(filepath.Join(inputFilesPath, fileName), filepath.Join(outputDir, fileName))
+		}
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("Using legacy commands", func() {
+		BeforeEach(func() {
+			session = Runi18n("-c", "rewrite-package",
+				"-d", outputDir,
+				"--i18n-strings-filename", filepath.Join(outputDir, "en.all.json"),
+				"--root-path", outputDir,
+				"--diff",
+			)
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("prints a unified diff of the rewritten files, the i18n init file and the updated i18n strings file", func() {
+			bytes, err := ioutil.ReadFile(filepath.Join(expectedFilesPath, "rewrite.diff"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(string(session.Out.Contents())).Should(Equal(string(bytes)))
+		})
+
+		It("does not write any file", func() {
+			for _, fileName := range []string{"greetings.go", "farewells.go", "en.all.json"} {
+				expectedBytes, err := ioutil.ReadFile(filepath.Join(inputFilesPath, fileName))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				bytes, err := ioutil.ReadFile(filepath.Join(outputDir, fileName))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(bytes)).Should(Equal(string(expectedBytes)))
+			}
+
+			for _, fileName := range []string{"i18n_init.go", "i18n_resources.go"} {
+				_, err := os.Stat(filepath.Join(outputDir, fileName))
+				Ω(os.IsNotExist(err)).Should(BeTrue())
+			}
+		})
+	})
+
+	Context("Using cobra commands", func() {
+		BeforeEach(func() {
+			session = Runi18n("rewrite-package",
+				"-d", outputDir,
+				"--i18n-strings-filename", filepath.Join(outputDir, "en.all.json"),
+				"--root-path", outputDir,
+				"--diff",
+			)
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("prints a unified diff of the rewritten files, the i18n init file and the updated i18n strings file", func() {
+			bytes, err := ioutil.ReadFile(filepath.Join(expectedFilesPath, "rewrite.diff"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(string(session.Out.Contents())).Should(Equal(string(bytes)))
+		})
+
+		It("does not write any file", func() {
+			for _, fileName := range []string{"greetings.go", "farewells.go", "en.all.json"} {
+				expectedBytes, err := ioutil.ReadFile(filepath.Join(inputFilesPath, fileName))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				bytes, err := ioutil.ReadFile(filepath.Join(outputDir, fileName))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(bytes)).Should(Equal(string(expectedBytes)))
+			}
+
+			for _, fileName := range []string{"i18n_init.go", "i18n_resources.go"} {
+				_, err := os.Stat(filepath.Join(outputDir, fileName))
+				Ω(os.IsNotExist(err)).Should(BeTrue())
+			}
+		})
+
+		It("prints a diff which applies to the source files", func() {
+			patchFile := filepath.Join(outputDir, "rewrite.diff")
+			err := ioutil.WriteFile(patchFile, session.Out.Contents(), 0644)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			patchSession := RunCommand("patch", "-p1", "-d", outputDir, "-i", patchFile)
+			Ω(patchSession.ExitCode()).Should(Equal(0))
+
+			bytes, err := ioutil.ReadFile(filepath.Join(outputDir, "greetings.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(bytes)).Should(ContainSubstring(`fmt.Printf(T("Hello {{.Arg0}}!\n", map[string]interface{}{"Arg0": name}))`))
+		})
+	})
+})
@@ -15,6 +15,7 @@
 package rewrite_package_test
 
 import (
+	"go/format"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -23,6 +24,8 @@ import (
 	. "github.com/maximilien/i18n4go/integration/test_helpers"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
 )
 
 var _ = Describe("rewrite-package -f filename", func() {
@@ -230,11 +233,470 @@ var _ = Describe("rewrite-package -f filename", func() {
 			})
 		})
 
+		Context("strings to rewrite contain printf verbs with flags, width, precision and indexes", func() {
+			BeforeEach(func() {
+				dir, err := os.Getwd()
+				Ω(err).ShouldNot(HaveOccurred())
+				rootPath = filepath.Join(dir, "..", "..")
+
+				outputDir, err = ioutil.TempDir(rootPath, "i18n4go_integration")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				fixturesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package")
+				inputFilesPath = filepath.Join(fixturesPath, "f_option", "input_files")
+				expectedFilesPath = filepath.Join(fixturesPath, "f_option", "expected_output")
+
+				session := Runi18n("-c",
+					"rewrite-package",
+					"-f", filepath.Join(inputFilesPath, "test_printf_verbs.go"),
+					"-o", filepath.Join(outputDir),
+					"-v",
+				)
+
+				Ω(session.ExitCode()).Should(Equal(0))
+			})
+
+			It("converts the verbs to templated args, keeping their formatting and mapping each arg by index", func() {
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test_printf_verbs.go")
+				bytes, err := ioutil.ReadFile(expectedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				expectedOutput := string(bytes)
+
+				generatedOutputFile := filepath.Join(outputDir, "test_printf_verbs.go")
+				bytes, err = ioutil.ReadFile(generatedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				actualOutput := string(bytes)
+
+				Ω(actualOutput).Should(Equal(expectedOutput))
+			})
+		})
+
+		Context("strings to rewrite are fmt.Errorf formats wrapping errors with %w", func() {
+			BeforeEach(func() {
+				dir, err := os.Getwd()
+				Ω(err).ShouldNot(HaveOccurred())
+				rootPath = filepath.Join(dir, "..", "..")
+
+				outputDir, err = ioutil.TempDir(rootPath, "i18n4go_integration")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				fixturesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package")
+				inputFilesPath = filepath.Join(fixturesPath, "f_option", "input_files")
+				expectedFilesPath = filepath.Join(fixturesPath, "f_option", "expected_output")
+
+				for _, fileName := range []string{"test_errors.go", "test_errors_named_import.go", "test_errors_fmt_alias.go"} {
+					session := Runi18n("-c", "rewrite-package",
+						"-f", filepath.Join(inputFilesPath, fileName),
+						"-o", filepath.Join(outputDir),
+						"-v",
+					)
+
+					Ω(session.ExitCode()).Should(Equal(0))
+				}
+			})
+
+			It("rewrites the calls with i18n.Errorf(T(...), errs...) and imports the i18n package", func() {
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test_errors.go")
+				bytes, err := ioutil.ReadFile(expectedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				expectedOutput := string(bytes)
+
+				generatedOutputFile := filepath.Join(outputDir, "test_errors.go")
+				bytes, err = ioutil.ReadFile(generatedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				actualOutput := string(bytes)
+
+				Ω(actualOutput).Should(Equal(expectedOutput))
+			})
+
+			It("imports the i18n package with another name when the file already imports an i18n package", func() {
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test_errors_named_import.go")
+				bytes, err := ioutil.ReadFile(expectedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				expectedOutput := string(bytes)
+
+				generatedOutputFile := filepath.Join(outputDir, "test_errors_named_import.go")
+				bytes, err = ioutil.ReadFile(generatedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				actualOutput := string(bytes)
+
+				Ω(actualOutput).Should(Equal(expectedOutput))
+			})
+
+			It("rewrites the calls of fmt imported with an alias", func() {
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test_errors_fmt_alias.go")
+				bytes, err := ioutil.ReadFile(expectedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				expectedOutput := string(bytes)
+
+				generatedOutputFile := filepath.Join(outputDir, "test_errors_fmt_alias.go")
+				bytes, err = ioutil.ReadFile(generatedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				actualOutput := string(bytes)
+
+				Ω(actualOutput).Should(Equal(expectedOutput))
+			})
+		})
+
+		Context("strings to rewrite are in contexts requiring constants or other types than string", func() {
+			var session *Session
+
+			BeforeEach(func() {
+				dir, err := os.Getwd()
+				Ω(err).ShouldNot(HaveOccurred())
+				rootPath = filepath.Join(dir, "..", "..")
+
+				outputDir, err = ioutil.TempDir(rootPath, "i18n4go_integration")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				fixturesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package")
+				inputFilesPath = filepath.Join(fixturesPath, "f_option", "input_files")
+				expectedFilesPath = filepath.Join(fixturesPath, "f_option", "expected_output")
+
+				session = Runi18n("-c", "rewrite-package",
+					"-f", filepath.Join(inputFilesPath, "test_constants.go"),
+					"-o", filepath.Join(outputDir),
+				)
+
+				Ω(session.ExitCode()).Should(Equal(0))
+			})
+
+			It("only rewrites the strings which keep the code compiling", func() {
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test_constants.go")
+				bytes, err := ioutil.ReadFile(expectedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				expectedOutput := string(bytes)
+
+				generatedOutputFile := filepath.Join(outputDir, "test_constants.go")
+				bytes, err = ioutil.ReadFile(generatedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				actualOutput := string(bytes)
+
+				Ω(actualOutput).Should(Equal(expectedOutput))
+			})
+
+			It("reports each skipped string", func() {
+				Ω(session).Should(Say(`skipping string "Hello" at .*test_constants.go:9:18, a constant expression is required`))
+				Ω(session).Should(Say(`skipping string "red" at .*test_constants.go:12:14, a constant expression is required`))
+				Ω(session).Should(Say(`skipping string "abc" at .*test_constants.go:15:16, a constant expression is required`))
+				Ω(session).Should(Say(`skipping string "blue" at .*test_constants.go:18:23, its type input_files.Color is not string`))
+				Ω(session).Should(Say(`skipping string "admin" at .*test_constants.go:21:7, switch cases are compared with the untranslated values`))
+				Ω(session).Should(Say(`skipping string "root" at .*test_constants.go:21:16`))
+				Ω(session).Should(Say(`skipping string "green" at .*test_constants.go:27:55, its type input_files.Color is not string`))
+			})
+		})
+
+		Context("the rewritten file no longer type checks", func() {
+			var session *Session
+
+			BeforeEach(func() {
+				dir, err := os.Getwd()
+				Ω(err).ShouldNot(HaveOccurred())
+				rootPath = filepath.Join(dir, "..", "..")
+
+				outputDir, err = ioutil.TempDir(rootPath, "i18n4go_integration")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				fixturesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package")
+				inputFilesPath = filepath.Join(fixturesPath, "f_option", "type_errors")
+
+				session = Runi18n("-c", "rewrite-package",
+					"-f", filepath.Join(inputFilesPath, "test_type_errors.go"),
+					"-o", filepath.Join(outputDir),
+				)
+			})
+
+			It("refuses to write the rewritten file and the i18n_init.go file", func() {
+				Ω(session.ExitCode()).Should(Equal(3))
+				Ω(session.Err).Should(Say(`refusing to write .*test_type_errors.go which no longer type checks`))
+
+				_, err := os.Stat(filepath.Join(outputDir, "test_type_errors.go"))
+				Ω(os.IsNotExist(err)).Should(BeTrue())
+
+				_, err = os.Stat(filepath.Join(outputDir, "i18n_init.go"))
+				Ω(os.IsNotExist(err)).Should(BeTrue())
+			})
+		})
+
+		Context("strings to rewrite are surrounded by comments and blank lines", func() {
+			BeforeEach(func() {
+				dir, err := os.Getwd()
+				Ω(err).ShouldNot(HaveOccurred())
+				rootPath = filepath.Join(dir, "..", "..")
+
+				outputDir, err = ioutil.TempDir(rootPath, "i18n4go_integration")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				fixturesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package")
+				inputFilesPath = filepath.Join(fixturesPath, "f_option", "input_files")
+				expectedFilesPath = filepath.Join(fixturesPath, "f_option", "expected_output")
+
+				session := Runi18n("-c", "rewrite-package",
+					"-f", filepath.Join(inputFilesPath, "test_comments.go"),
+					"-o", filepath.Join(outputDir),
+					"-v",
+				)
+
+				Ω(session.ExitCode()).Should(Equal(0))
+			})
+
+			It("only edits the rewritten expressions, keeping the comments and formatting", func() {
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test_comments.go")
+				bytes, err := ioutil.ReadFile(expectedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				expectedOutput := string(bytes)
+
+				generatedOutputFile := filepath.Join(outputDir, "test_comments.go")
+				bytes, err = ioutil.ReadFile(generatedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				actualOutput := string(bytes)
+
+				Ω(actualOutput).Should(Equal(expectedOutput))
+			})
+
+			It("writes gofmt-ed code", func() {
+				bytes, err := ioutil.ReadFile(filepath.Join(outputDir, "test_comments.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				formattedBytes, err := format.Source(bytes)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(bytes)).Should(Equal(string(formattedBytes)))
+			})
+		})
+
+		Context("strings are already translated with T(), i18n.T(), the qualifier or the T func names", func() {
+			BeforeEach(func() {
+				dir, err := os.Getwd()
+				Ω(err).ShouldNot(HaveOccurred())
+				rootPath = filepath.Join(dir, "..", "..")
+
+				outputDir, err = ioutil.TempDir(rootPath, "i18n4go_integration")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				fixturesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package")
+				inputFilesPath = filepath.Join(fixturesPath, "f_option", "input_files")
+				expectedFilesPath = filepath.Join(fixturesPath, "f_option", "expected_output")
+
+				session := Runi18n("-c", "rewrite-package",
+					"-f", filepath.Join(inputFilesPath, "test_already_translated.go"),
+					"-o", filepath.Join(outputDir),
+					"-q", "tr",
+					"--t-func-names", "Tr",
+					"-v",
+				)
+
+				Ω(session.ExitCode()).Should(Equal(0))
+			})
+
+			It("only rewrites the strings which are not translated yet", func() {
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test_already_translated.go")
+				bytes, err := ioutil.ReadFile(expectedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				expectedOutput := string(bytes)
+
+				generatedOutputFile := filepath.Join(outputDir, "test_already_translated.go")
+				bytes, err = ioutil.ReadFile(generatedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				actualOutput := string(bytes)
+
+				Ω(actualOutput).Should(Equal(expectedOutput))
+			})
+
+			It("does not change the rewritten file when run again", func() {
+				session := Runi18n("-c", "rewrite-package",
+					"-f", filepath.Join(outputDir, "test_already_translated.go"),
+					"-o", filepath.Join(outputDir),
+					"-q", "tr",
+					"--t-func-names", "Tr",
+					"-v",
+				)
+				Ω(session.ExitCode()).Should(Equal(0))
+
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test_already_translated.go")
+				bytes, err := ioutil.ReadFile(expectedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				expectedOutput := string(bytes)
+
+				generatedOutputFile := filepath.Join(outputDir, "test_already_translated.go")
+				bytes, err = ioutil.ReadFile(generatedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				actualOutput := string(bytes)
+
+				Ω(actualOutput).Should(Equal(expectedOutput))
+			})
+		})
+
+		Context("an i18n_init.go file already exists in the output directory", func() {
+			var initFileContent string
+
+			BeforeEach(func() {
+				dir, err := os.Getwd()
+				Ω(err).ShouldNot(HaveOccurred())
+				rootPath = filepath.Join(dir, "..", "..")
+
+				outputDir, err = ioutil.TempDir(rootPath, "i18n4go_integration")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				fixturesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package")
+				inputFilesPath = filepath.Join(fixturesPath, "f_option", "input_files")
+				expectedFilesPath = filepath.Join(fixturesPath, "f_option", "expected_output")
+
+				initFileContent = "package input_files\n\n// customized i18n initialization\n"
+				err = ioutil.WriteFile(filepath.Join(outputDir, "i18n_init.go"), []byte(initFileContent), 0666)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("keeps the existing i18n_init.go file", func() {
+				session := Runi18n("-c", "rewrite-package",
+					"-f", filepath.Join(inputFilesPath, "test.go"),
+					"-o", filepath.Join(outputDir),
+					"-v",
+				)
+				Ω(session.ExitCode()).Should(Equal(0))
+
+				bytes, err := ioutil.ReadFile(filepath.Join(outputDir, "i18n_init.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(bytes)).Should(Equal(initFileContent))
+			})
+
+			It("regenerates the i18n_init.go file with --regenerate-init", func() {
+				session := Runi18n("-c", "rewrite-package",
+					"-f", filepath.Join(inputFilesPath, "test.go"),
+					"-o", filepath.Join(outputDir),
+					"--regenerate-init",
+					"-v",
+				)
+				Ω(session.ExitCode()).Should(Equal(0))
+
+				bytes, err := ioutil.ReadFile(filepath.Join(outputDir, "i18n_init.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(bytes)).Should(ContainSubstring("var T i18n.TranslateFunc"))
+			})
+		})
+
 	})
 
-	Context("Using cobra commands", func() {
+	Context("Using cobra commands", func() {
+
+		Context("no -o option passed, so input file is rewritten", func() {
+			BeforeEach(func() {
+				dir, err := os.Getwd()
+				Ω(err).ShouldNot(HaveOccurred())
+				rootPath = filepath.Join(dir, "..", "..")
+
+				outputDir, err = ioutil.TempDir(rootPath, "i18n4go_integration")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				fixturesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package")
+				inputFilesPath = filepath.Join(fixturesPath, "f_option", "input_files")
+				expectedFilesPath = filepath.Join(fixturesPath, "f_option", "expected_output")
+
+				CopyFile(filepath.Join(inputFilesPath, "test.go"), filepath.Join(outputDir, "test.go"))
+
+				session := Runi18n("rewrite-package",
+					"-f", filepath.Join(outputDir, "test.go"),
+					"--root-path", outputDir,
+					"-v",
+				)
+
+				Ω(session.ExitCode()).Should(Equal(0))
+			})
+
+			It("overwrites the input file with T() wrappers around strings", func() {
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test.go")
+				bytes, err := ioutil.ReadFile(expectedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				expectedOutput := string(bytes)
+
+				generatedOutputFile := filepath.Join(outputDir, "test.go")
+				bytes, err = ioutil.ReadFile(generatedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				actualOutput := string(bytes)
+
+				Ω(actualOutput).Should(Equal(expectedOutput))
+			})
+
+			It("adds T func declaration and i18n init() func in the same directory as input file", func() {
+				initFile := filepath.Join(outputDir, "i18n_init.go")
+				expectedBytes, err := ioutil.ReadFile(initFile)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(expectedBytes).ShouldNot(Equal(""))
+			})
+		})
+
+		Context("all strings to rewrite are simple strings", func() {
+			BeforeEach(func() {
+				dir, err := os.Getwd()
+				Ω(err).ShouldNot(HaveOccurred())
+				rootPath = filepath.Join(dir, "..", "..")
+
+				outputDir, err = ioutil.TempDir(rootPath, "i18n4go_integration")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				fixturesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package")
+				inputFilesPath = filepath.Join(fixturesPath, "f_option", "input_files")
+				expectedFilesPath = filepath.Join(fixturesPath, "f_option", "expected_output")
+
+				session := Runi18n("rewrite-package",
+					"-f", filepath.Join(inputFilesPath, "test.go"),
+					"-o", outputDir,
+					"--root-path", rootPath,
+					"-v",
+				)
+
+				Ω(session.ExitCode()).Should(Equal(0))
+			})
+
+			It("rewrites the input file with T() wrappers around strings", func() {
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test.go")
+				bytes, err := ioutil.ReadFile(expectedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				expectedOutput := string(bytes)
+
+				generatedOutputFile := filepath.Join(outputDir, "test.go")
+				bytes, err = ioutil.ReadFile(generatedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				actualOutput := string(bytes)
+
+				Ω(actualOutput).Should(Equal(expectedOutput))
+			})
+
+			It("adds T func declaration and i18n init() func", func() {
+				initFile := filepath.Join(outputDir, "i18n_init.go")
+				expectedBytes, err := ioutil.ReadFile(initFile)
+				Ω(err).ShouldNot(HaveOccurred())
+				expected := strings.TrimSpace(string(expectedBytes))
+
+				expectedInitFile := filepath.Join(expectedFilesPath, "i18n_init.go")
+				actualBytes, err := ioutil.ReadFile(expectedInitFile)
+				Ω(err).ShouldNot(HaveOccurred())
+				actual := strings.TrimSpace(string(actualBytes))
 
-		Context("no -o option passed, so input file is rewritten", func() {
+				Ω(actual).Should(Equal(expected))
+			})
+		})
+
+		Context("strings to rewrite contain complex templated strings", func() {
 			BeforeEach(func() {
 				dir, err := os.Getwd()
 				Ω(err).ShouldNot(HaveOccurred())
@@ -247,25 +709,23 @@ var _ = Describe("rewrite-package -f filename", func() {
 				inputFilesPath = filepath.Join(fixturesPath, "f_option", "input_files")
 				expectedFilesPath = filepath.Join(fixturesPath, "f_option", "expected_output")
 
-				CopyFile(filepath.Join(inputFilesPath, "test.go"), filepath.Join(outputDir, "test.go"))
-
 				session := Runi18n("rewrite-package",
-					"-f", filepath.Join(outputDir, "test.go"),
-					"--root-path", outputDir,
+					"-f", filepath.Join(inputFilesPath, "test_templated_strings.go"),
+					"-o", filepath.Join(outputDir),
 					"-v",
 				)
 
 				Ω(session.ExitCode()).Should(Equal(0))
 			})
 
-			It("overwrites the input file with T() wrappers around strings", func() {
-				expectedOutputFile := filepath.Join(expectedFilesPath, "test.go")
+			It("rewrites the input file with T() wrappers around all (simple and templated) strings", func() {
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test_templated_strings.go")
 				bytes, err := ioutil.ReadFile(expectedOutputFile)
 				Ω(err).ShouldNot(HaveOccurred())
 
 				expectedOutput := string(bytes)
 
-				generatedOutputFile := filepath.Join(outputDir, "test.go")
+				generatedOutputFile := filepath.Join(outputDir, "test_templated_strings.go")
 				bytes, err = ioutil.ReadFile(generatedOutputFile)
 				Ω(err).ShouldNot(HaveOccurred())
 
@@ -273,16 +733,48 @@ var _ = Describe("rewrite-package -f filename", func() {
 
 				Ω(actualOutput).Should(Equal(expectedOutput))
 			})
+		})
 
-			It("adds T func declaration and i18n init() func in the same directory as input file", func() {
-				initFile := filepath.Join(outputDir, "i18n_init.go")
-				expectedBytes, err := ioutil.ReadFile(initFile)
+		Context("strings to rewrite contain interpolated strings", func() {
+			BeforeEach(func() {
+				dir, err := os.Getwd()
 				Ω(err).ShouldNot(HaveOccurred())
-				Ω(expectedBytes).ShouldNot(Equal(""))
+				rootPath = filepath.Join(dir, "..", "..")
+
+				outputDir, err = ioutil.TempDir(rootPath, "i18n4go_integration")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				fixturesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package")
+				inputFilesPath = filepath.Join(fixturesPath, "f_option", "input_files")
+				expectedFilesPath = filepath.Join(fixturesPath, "f_option", "expected_output")
+
+				session := Runi18n("rewrite-package",
+					"-f", filepath.Join(inputFilesPath, "test_interpolated_strings.go"),
+					"-o", filepath.Join(outputDir),
+					"-v",
+				)
+
+				Ω(session.ExitCode()).Should(Equal(0))
+			})
+
+			It("converts interpolated strings to templated and rewrites the input file with T() wrappers around all (simple and templated) strings", func() {
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test_interpolated_strings.go")
+				bytes, err := ioutil.ReadFile(expectedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				expectedOutput := string(bytes)
+
+				generatedOutputFile := filepath.Join(outputDir, "test_interpolated_strings.go")
+				bytes, err = ioutil.ReadFile(generatedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				actualOutput := string(bytes)
+
+				Ω(actualOutput).Should(Equal(expectedOutput))
 			})
 		})
 
-		Context("all strings to rewrite are simple strings", func() {
+		Context("strings to rewrite contain printf verbs with flags, width, precision and indexes", func() {
 			BeforeEach(func() {
 				dir, err := os.Getwd()
 				Ω(err).ShouldNot(HaveOccurred())
@@ -296,23 +788,22 @@ var _ = Describe("rewrite-package -f filename", func() {
 				expectedFilesPath = filepath.Join(fixturesPath, "f_option", "expected_output")
 
 				session := Runi18n("rewrite-package",
-					"-f", filepath.Join(inputFilesPath, "test.go"),
-					"-o", outputDir,
-					"--root-path", rootPath,
+					"-f", filepath.Join(inputFilesPath, "test_printf_verbs.go"),
+					"-o", filepath.Join(outputDir),
 					"-v",
 				)
 
 				Ω(session.ExitCode()).Should(Equal(0))
 			})
 
-			It("rewrites the input file with T() wrappers around strings", func() {
-				expectedOutputFile := filepath.Join(expectedFilesPath, "test.go")
+			It("converts the verbs to templated args, keeping their formatting and mapping each arg by index", func() {
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test_printf_verbs.go")
 				bytes, err := ioutil.ReadFile(expectedOutputFile)
 				Ω(err).ShouldNot(HaveOccurred())
 
 				expectedOutput := string(bytes)
 
-				generatedOutputFile := filepath.Join(outputDir, "test.go")
+				generatedOutputFile := filepath.Join(outputDir, "test_printf_verbs.go")
 				bytes, err = ioutil.ReadFile(generatedOutputFile)
 				Ω(err).ShouldNot(HaveOccurred())
 
@@ -320,23 +811,164 @@ var _ = Describe("rewrite-package -f filename", func() {
 
 				Ω(actualOutput).Should(Equal(expectedOutput))
 			})
+		})
 
-			It("adds T func declaration and i18n init() func", func() {
-				initFile := filepath.Join(outputDir, "i18n_init.go")
-				expectedBytes, err := ioutil.ReadFile(initFile)
+		Context("strings to rewrite are fmt.Errorf formats wrapping errors with %w", func() {
+			BeforeEach(func() {
+				dir, err := os.Getwd()
 				Ω(err).ShouldNot(HaveOccurred())
-				expected := strings.TrimSpace(string(expectedBytes))
+				rootPath = filepath.Join(dir, "..", "..")
 
-				expectedInitFile := filepath.Join(expectedFilesPath, "i18n_init.go")
-				actualBytes, err := ioutil.ReadFile(expectedInitFile)
+				outputDir, err = ioutil.TempDir(rootPath, "i18n4go_integration")
 				Ω(err).ShouldNot(HaveOccurred())
-				actual := strings.TrimSpace(string(actualBytes))
 
-				Ω(actual).Should(Equal(expected))
+				fixturesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package")
+				inputFilesPath = filepath.Join(fixturesPath, "f_option", "input_files")
+				expectedFilesPath = filepath.Join(fixturesPath, "f_option", "expected_output")
+
+				for _, fileName := range []string{"test_errors.go", "test_errors_named_import.go", "test_errors_fmt_alias.go"} {
+					session := Runi18n("rewrite-package",
+						"-f", filepath.Join(inputFilesPath, fileName),
+						"-o", filepath.Join(outputDir),
+						"-v",
+					)
+
+					Ω(session.ExitCode()).Should(Equal(0))
+				}
+			})
+
+			It("rewrites the calls with i18n.Errorf(T(...), errs...) and imports the i18n package", func() {
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test_errors.go")
+				bytes, err := ioutil.ReadFile(expectedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				expectedOutput := string(bytes)
+
+				generatedOutputFile := filepath.Join(outputDir, "test_errors.go")
+				bytes, err = ioutil.ReadFile(generatedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				actualOutput := string(bytes)
+
+				Ω(actualOutput).Should(Equal(expectedOutput))
+			})
+
+			It("imports the i18n package with another name when the file already imports an i18n package", func() {
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test_errors_named_import.go")
+				bytes, err := ioutil.ReadFile(expectedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				expectedOutput := string(bytes)
+
+				generatedOutputFile := filepath.Join(outputDir, "test_errors_named_import.go")
+				bytes, err = ioutil.ReadFile(generatedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				actualOutput := string(bytes)
+
+				Ω(actualOutput).Should(Equal(expectedOutput))
+			})
+
+			It("rewrites the calls of fmt imported with an alias", func() {
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test_errors_fmt_alias.go")
+				bytes, err := ioutil.ReadFile(expectedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				expectedOutput := string(bytes)
+
+				generatedOutputFile := filepath.Join(outputDir, "test_errors_fmt_alias.go")
+				bytes, err = ioutil.ReadFile(generatedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				actualOutput := string(bytes)
+
+				Ω(actualOutput).Should(Equal(expectedOutput))
 			})
 		})
 
-		Context("strings to rewrite contain complex templated strings", func() {
+		Context("strings to rewrite are in contexts requiring constants or other types than string", func() {
+			var session *Session
+
+			BeforeEach(func() {
+				dir, err := os.Getwd()
+				Ω(err).ShouldNot(HaveOccurred())
+				rootPath = filepath.Join(dir, "..", "..")
+
+				outputDir, err = ioutil.TempDir(rootPath, "i18n4go_integration")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				fixturesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package")
+				inputFilesPath = filepath.Join(fixturesPath, "f_option", "input_files")
+				expectedFilesPath = filepath.Join(fixturesPath, "f_option", "expected_output")
+
+				session = Runi18n("rewrite-package",
+					"-f", filepath.Join(inputFilesPath, "test_constants.go"),
+					"-o", filepath.Join(outputDir),
+				)
+
+				Ω(session.ExitCode()).Should(Equal(0))
+			})
+
+			It("only rewrites the strings which keep the code compiling", func() {
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test_constants.go")
+				bytes, err := ioutil.ReadFile(expectedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				expectedOutput := string(bytes)
+
+				generatedOutputFile := filepath.Join(outputDir, "test_constants.go")
+				bytes, err = ioutil.ReadFile(generatedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				actualOutput := string(bytes)
+
+				Ω(actualOutput).Should(Equal(expectedOutput))
+			})
+
+			It("reports each skipped string", func() {
+				Ω(session).Should(Say(`skipping string "Hello" at .*test_constants.go:9:18, a constant expression is required`))
+				Ω(session).Should(Say(`skipping string "red" at .*test_constants.go:12:14, a constant expression is required`))
+				Ω(session).Should(Say(`skipping string "abc" at .*test_constants.go:15:16, a constant expression is required`))
+				Ω(session).Should(Say(`skipping string "blue" at .*test_constants.go:18:23, its type input_files.Color is not string`))
+				Ω(session).Should(Say(`skipping string "admin" at .*test_constants.go:21:7, switch cases are compared with the untranslated values`))
+				Ω(session).Should(Say(`skipping string "root" at .*test_constants.go:21:16`))
+				Ω(session).Should(Say(`skipping string "green" at .*test_constants.go:27:55, its type input_files.Color is not string`))
+			})
+		})
+
+		Context("the rewritten file no longer type checks", func() {
+			var session *Session
+
+			BeforeEach(func() {
+				dir, err := os.Getwd()
+				Ω(err).ShouldNot(HaveOccurred())
+				rootPath = filepath.Join(dir, "..", "..")
+
+				outputDir, err = ioutil.TempDir(rootPath, "i18n4go_integration")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				fixturesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package")
+				inputFilesPath = filepath.Join(fixturesPath, "f_option", "type_errors")
+
+				session = Runi18n("rewrite-package",
+					"-f", filepath.Join(inputFilesPath, "test_type_errors.go"),
+					"-o", filepath.Join(outputDir),
+				)
+			})
+
+			It("refuses to write the rewritten file and the i18n_init.go file", func() {
+				Ω(session.ExitCode()).Should(Equal(3))
+				Ω(session.Err).Should(Say(`refusing to write .*test_type_errors.go which no longer type checks`))
+
+				_, err := os.Stat(filepath.Join(outputDir, "test_type_errors.go"))
+				Ω(os.IsNotExist(err)).Should(BeTrue())
+
+				_, err = os.Stat(filepath.Join(outputDir, "i18n_init.go"))
+				Ω(os.IsNotExist(err)).Should(BeTrue())
+			})
+		})
+
+		Context("strings to rewrite are surrounded by comments and blank lines", func() {
 			BeforeEach(func() {
 				dir, err := os.Getwd()
 				Ω(err).ShouldNot(HaveOccurred())
@@ -350,7 +982,7 @@ var _ = Describe("rewrite-package -f filename", func() {
 				expectedFilesPath = filepath.Join(fixturesPath, "f_option", "expected_output")
 
 				session := Runi18n("rewrite-package",
-					"-f", filepath.Join(inputFilesPath, "test_templated_strings.go"),
+					"-f", filepath.Join(inputFilesPath, "test_comments.go"),
 					"-o", filepath.Join(outputDir),
 					"-v",
 				)
@@ -358,14 +990,14 @@ var _ = Describe("rewrite-package -f filename", func() {
 				Ω(session.ExitCode()).Should(Equal(0))
 			})
 
-			It("rewrites the input file with T() wrappers around all (simple and templated) strings", func() {
-				expectedOutputFile := filepath.Join(expectedFilesPath, "test_templated_strings.go")
+			It("only edits the rewritten expressions, keeping the comments and formatting", func() {
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test_comments.go")
 				bytes, err := ioutil.ReadFile(expectedOutputFile)
 				Ω(err).ShouldNot(HaveOccurred())
 
 				expectedOutput := string(bytes)
 
-				generatedOutputFile := filepath.Join(outputDir, "test_templated_strings.go")
+				generatedOutputFile := filepath.Join(outputDir, "test_comments.go")
 				bytes, err = ioutil.ReadFile(generatedOutputFile)
 				Ω(err).ShouldNot(HaveOccurred())
 
@@ -373,9 +1005,18 @@ var _ = Describe("rewrite-package -f filename", func() {
 
 				Ω(actualOutput).Should(Equal(expectedOutput))
 			})
+
+			It("writes gofmt-ed code", func() {
+				bytes, err := ioutil.ReadFile(filepath.Join(outputDir, "test_comments.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				formattedBytes, err := format.Source(bytes)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(bytes)).Should(Equal(string(formattedBytes)))
+			})
 		})
 
-		Context("strings to rewrite contain interpolated strings", func() {
+		Context("strings are already translated with T(), i18n.T(), the qualifier or the T func names", func() {
 			BeforeEach(func() {
 				dir, err := os.Getwd()
 				Ω(err).ShouldNot(HaveOccurred())
@@ -389,22 +1030,49 @@ var _ = Describe("rewrite-package -f filename", func() {
 				expectedFilesPath = filepath.Join(fixturesPath, "f_option", "expected_output")
 
 				session := Runi18n("rewrite-package",
-					"-f", filepath.Join(inputFilesPath, "test_interpolated_strings.go"),
+					"-f", filepath.Join(inputFilesPath, "test_already_translated.go"),
 					"-o", filepath.Join(outputDir),
+					"-q", "tr",
+					"--t-func-names", "Tr",
 					"-v",
 				)
 
 				Ω(session.ExitCode()).Should(Equal(0))
 			})
 
-			It("converts interpolated strings to templated and rewrites the input file with T() wrappers around all (simple and templated) strings", func() {
-				expectedOutputFile := filepath.Join(expectedFilesPath, "test_interpolated_strings.go")
+			It("only rewrites the strings which are not translated yet", func() {
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test_already_translated.go")
 				bytes, err := ioutil.ReadFile(expectedOutputFile)
 				Ω(err).ShouldNot(HaveOccurred())
 
 				expectedOutput := string(bytes)
 
-				generatedOutputFile := filepath.Join(outputDir, "test_interpolated_strings.go")
+				generatedOutputFile := filepath.Join(outputDir, "test_already_translated.go")
+				bytes, err = ioutil.ReadFile(generatedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				actualOutput := string(bytes)
+
+				Ω(actualOutput).Should(Equal(expectedOutput))
+			})
+
+			It("does not change the rewritten file when run again", func() {
+				session := Runi18n("rewrite-package",
+					"-f", filepath.Join(outputDir, "test_already_translated.go"),
+					"-o", filepath.Join(outputDir),
+					"-q", "tr",
+					"--t-func-names", "Tr",
+					"-v",
+				)
+				Ω(session.ExitCode()).Should(Equal(0))
+
+				expectedOutputFile := filepath.Join(expectedFilesPath, "test_already_translated.go")
+				bytes, err := ioutil.ReadFile(expectedOutputFile)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				expectedOutput := string(bytes)
+
+				generatedOutputFile := filepath.Join(outputDir, "test_already_translated.go")
 				bytes, err = ioutil.ReadFile(generatedOutputFile)
 				Ω(err).ShouldNot(HaveOccurred())
 
@@ -414,5 +1082,53 @@ var _ = Describe("rewrite-package -f filename", func() {
 			})
 		})
 
+		Context("an i18n_init.go file already exists in the output directory", func() {
+			var initFileContent string
+
+			BeforeEach(func() {
+				dir, err := os.Getwd()
+				Ω(err).ShouldNot(HaveOccurred())
+				rootPath = filepath.Join(dir, "..", "..")
+
+				outputDir, err = ioutil.TempDir(rootPath, "i18n4go_integration")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				fixturesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package")
+				inputFilesPath = filepath.Join(fixturesPath, "f_option", "input_files")
+				expectedFilesPath = filepath.Join(fixturesPath, "f_option", "expected_output")
+
+				initFileContent = "package input_files\n\n// customized i18n initialization\n"
+				err = ioutil.WriteFile(filepath.Join(outputDir, "i18n_init.go"), []byte(initFileContent), 0666)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("keeps the existing i18n_init.go file", func() {
+				session := Runi18n("rewrite-package",
+					"-f", filepath.Join(inputFilesPath, "test.go"),
+					"-o", filepath.Join(outputDir),
+					"-v",
+				)
+				Ω(session.ExitCode()).Should(Equal(0))
+
+				bytes, err := ioutil.ReadFile(filepath.Join(outputDir, "i18n_init.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(bytes)).Should(Equal(initFileContent))
+			})
+
+			It("regenerates the i18n_init.go file with --regenerate-init", func() {
+				session := Runi18n("rewrite-package",
+					"-f", filepath.Join(inputFilesPath, "test.go"),
+					"-o", filepath.Join(outputDir),
+					"--regenerate-init",
+					"-v",
+				)
+				Ω(session.ExitCode()).Should(Equal(0))
+
+				bytes, err := ioutil.ReadFile(filepath.Join(outputDir, "i18n_init.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(bytes)).Should(ContainSubstring("var T i18n.TranslateFunc"))
+			})
+		})
+
 	})
 })
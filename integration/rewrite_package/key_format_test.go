@@ -0,0 +1,139 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rewrite_package_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rewrite-package --key-format keyFormat", func() {
+	var (
+		outputDir         string
+		rootPath          string
+		fixturesPath      string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+		rootPath = filepath.Join(dir, "..", "..")
+
+		outputDir, err = ioutil.TempDir(rootPath, "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package")
+		inputFilesPath = filepath.Join(fixturesPath, "key_format", "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "key_format", "expected_output")
+
+		CopyFile(filepath.Join(inputFilesPath, "en.all.json"), filepath.Join(outputDir, "en.all.json"))
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("Using legacy commands", func() {
+		It("wraps the strings with T() calls using slug keys and keeps the text as translation", func() {
+			session := Runi18n("-c", "rewrite-package",
+				"-f", filepath.Join(inputFilesPath, "greetings.go"),
+				"-o", outputDir,
+				"--i18n-strings-filename", filepath.Join(outputDir, "en.all.json"),
+				"--root-path", outputDir,
+				"--key-format", "slug",
+			)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			CompareExpectedOutputToGeneratedOutput(filepath.Join(expectedFilesPath, "greetings.go"), filepath.Join(outputDir, "greetings.go"))
+			CompareExpectedOutputToGeneratedOutput(filepath.Join(expectedFilesPath, "en.all.json"), filepath.Join(outputDir, "en.all.json"))
+		})
+
+		It("reuses the keys of an i18n strings file extracted with slug keys", func() {
+			CopyFile(filepath.Join(inputFilesPath, "en.keys.json"), filepath.Join(outputDir, "en.all.json"))
+
+			session := Runi18n("-c", "rewrite-package",
+				"-f", filepath.Join(inputFilesPath, "greetings.go"),
+				"-o", outputDir,
+				"--i18n-strings-filename", filepath.Join(outputDir, "en.all.json"),
+				"--root-path", outputDir,
+				"--key-format", "slug",
+			)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			CompareExpectedOutputToGeneratedOutput(filepath.Join(expectedFilesPath, "greetings.go"), filepath.Join(outputDir, "greetings.go"))
+			CompareExpectedOutputToGeneratedOutput(filepath.Join(expectedFilesPath, "en.all.json"), filepath.Join(outputDir, "en.all.json"))
+		})
+
+		It("fails without an i18n strings file", func() {
+			session := Runi18n("-c", "rewrite-package",
+				"-f", filepath.Join(inputFilesPath, "greetings.go"),
+				"-o", outputDir,
+				"--root-path", outputDir,
+				"--key-format", "slug",
+			)
+			Ω(session.ExitCode()).ShouldNot(Equal(0))
+		})
+	})
+
+	Context("Using cobra commands", func() {
+		It("wraps the strings with T() calls using slug keys and keeps the text as translation", func() {
+			session := Runi18n("rewrite-package",
+				"-f", filepath.Join(inputFilesPath, "greetings.go"),
+				"-o", outputDir,
+				"--i18n-strings-filename", filepath.Join(outputDir, "en.all.json"),
+				"--root-path", outputDir,
+				"--key-format", "slug",
+			)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			CompareExpectedOutputToGeneratedOutput(filepath.Join(expectedFilesPath, "greetings.go"), filepath.Join(outputDir, "greetings.go"))
+			CompareExpectedOutputToGeneratedOutput(filepath.Join(expectedFilesPath, "en.all.json"), filepath.Join(outputDir, "en.all.json"))
+		})
+
+		It("reuses the keys of an i18n strings file extracted with slug keys", func() {
+			CopyFile(filepath.Join(inputFilesPath, "en.keys.json"), filepath.Join(outputDir, "en.all.json"))
+
+			session := Runi18n("rewrite-package",
+				"-f", filepath.Join(inputFilesPath, "greetings.go"),
+				"-o", outputDir,
+				"--i18n-strings-filename", filepath.Join(outputDir, "en.all.json"),
+				"--root-path", outputDir,
+				"--key-format", "slug",
+			)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			CompareExpectedOutputToGeneratedOutput(filepath.Join(expectedFilesPath, "greetings.go"), filepath.Join(outputDir, "greetings.go"))
+			CompareExpectedOutputToGeneratedOutput(filepath.Join(expectedFilesPath, "en.all.json"), filepath.Join(outputDir, "en.all.json"))
+		})
+
+		It("fails without an i18n strings file", func() {
+			session := Runi18n("rewrite-package",
+				"-f", filepath.Join(inputFilesPath, "greetings.go"),
+				"-o", outputDir,
+				"--root-path", outputDir,
+				"--key-format", "slug",
+			)
+			Ω(session.ExitCode()).ShouldNot(Equal(0))
+		})
+	})
+})
@@ -62,7 +62,7 @@ var _ = Describe("show-missing-strings -d dirName", func() {
 				codeDirPath := filepath.Join(inputFilesPath, "missing_strings", "code")
 				session = Runi18n("-c", "show-missing-strings", "-d", codeDirPath, "--i18n-strings-filename", languageFilePath)
 
-				Eventually(session.ExitCode()).Should(Equal(1))
+				Eventually(session.ExitCode()).Should(Equal(2))
 			})
 
 			It("Should output something", func() {
@@ -76,7 +76,7 @@ var _ = Describe("show-missing-strings -d dirName", func() {
 				codeDirPath := filepath.Join(inputFilesPath, "extra_strings", "code")
 				session = Runi18n("-c", "show-missing-strings", "-d", codeDirPath, "--i18n-strings-filename", languageFilePath)
 
-				Eventually(session.ExitCode()).Should(Equal(1))
+				Eventually(session.ExitCode()).Should(Equal(2))
 			})
 
 			It("Should output something", func() {
@@ -107,7 +107,7 @@ var _ = Describe("show-missing-strings -d dirName", func() {
 				codeDirPath := filepath.Join(inputFilesPath, "missing_strings", "code")
 				session = Runi18n("show-missing-strings", "-d", codeDirPath, "--i18n-strings-filename", languageFilePath)
 
-				Eventually(session.ExitCode()).Should(Equal(1))
+				Eventually(session.ExitCode()).Should(Equal(2))
 			})
 
 			It("Should output something", func() {
@@ -121,7 +121,7 @@ var _ = Describe("show-missing-strings -d dirName", func() {
 				codeDirPath := filepath.Join(inputFilesPath, "extra_strings", "code")
 				session = Runi18n("show-missing-strings", "-d", codeDirPath, "--i18n-strings-filename", languageFilePath)
 
-				Eventually(session.ExitCode()).Should(Equal(1))
+				Eventually(session.ExitCode()).Should(Equal(2))
 			})
 
 			It("Should output something", func() {
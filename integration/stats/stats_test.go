@@ -0,0 +1,164 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+)
+
+type localeStats struct {
+	Package      string  `json:"package"`
+	Locale       string  `json:"locale"`
+	Total        int     `json:"total"`
+	Translated   int     `json:"translated"`
+	Untranslated int     `json:"untranslated"`
+	Missing      int     `json:"missing"`
+	Extra        int     `json:"extra"`
+	Invalid      int     `json:"invalid"`
+	Coverage     float64 `json:"coverage"`
+}
+
+type statsReport struct {
+	Packages []localeStats `json:"packages"`
+	Totals   []localeStats `json:"totals"`
+}
+
+var _ = Describe("stats", func() {
+	var (
+		curDir            string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		curDir, err = os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		err = os.Chdir(filepath.Join("..", "..", "test_fixtures", "stats", "project"))
+		Ω(err).ShouldNot(HaveOccurred(), "Could not change to fixtures directory")
+
+		expectedFilesPath = filepath.Join("..", "expected_output")
+	})
+
+	AfterEach(func() {
+		err := os.Chdir(curDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("with the table format", func() {
+		It("prints the stats per package and locale with totals", func() {
+			session := Runi18n("stats")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			Ω(session).Should(Say(`PACKAGE\s+LOCALE\s+TOTAL\s+TRANSLATED\s+UNTRANSLATED\s+MISSING\s+EXTRA\s+INVALID\s+COVERAGE`))
+			Ω(session).Should(Say(`cli\s+de_DE\s+4\s+4\s+0\s+0\s+0\s+0\s+100.0%`))
+			Ω(session).Should(Say(`cli\s+fr_FR\s+4\s+2\s+1\s+1\s+1\s+1\s+50.0%`))
+			Ω(session).Should(Say(`plugin\s+fr_FR\s+2\s+1\s+0\s+1\s+0\s+0\s+50.0%`))
+			Ω(session).Should(Say(`TOTAL\s+de_DE\s+4\s+4\s+0\s+0\s+0\s+0\s+100.0%`))
+			Ω(session).Should(Say(`TOTAL\s+fr_FR\s+6\s+3\s+1\s+2\s+1\s+1\s+50.0%`))
+		})
+	})
+
+	Context("with the JSON format", func() {
+		It("prints the stats as JSON", func() {
+			session := Runi18n("stats", "--format", "json")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			var report statsReport
+			err := json.Unmarshal(session.Out.Contents(), &report)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(report.Packages).Should(Equal([]localeStats{
+				{Package: "cli", Locale: "de_DE", Total: 4, Translated: 4, Coverage: 100},
+				{Package: "cli", Locale: "fr_FR", Total: 4, Translated: 2, Untranslated: 1, Missing: 1, Extra: 1, Invalid: 1, Coverage: 50},
+				{Package: "plugin", Locale: "fr_FR", Total: 2, Translated: 1, Missing: 1, Coverage: 50},
+			}))
+			Ω(report.Totals).Should(Equal([]localeStats{
+				{Package: "TOTAL", Locale: "de_DE", Total: 4, Translated: 4, Coverage: 100},
+				{Package: "TOTAL", Locale: "fr_FR", Total: 6, Translated: 3, Untranslated: 1, Missing: 2, Extra: 1, Invalid: 1, Coverage: 50},
+			}))
+		})
+
+		It("does not count skipped checks as invalid", func() {
+			session := Runi18n("stats", "--format", "json", "--skip-checks", "template-args")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			var report statsReport
+			err := json.Unmarshal(session.Out.Contents(), &report)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(report.Totals[1].Invalid).Should(Equal(0))
+		})
+	})
+
+	Context("with the HTML format", func() {
+		AfterEach(func() {
+			RemoveAllFiles(
+				filepath.Join(expectedFilesPath, "stats.html"),
+				expectedFilesPath,
+			)
+		})
+
+		It("generates a static HTML page in the output directory", func() {
+			session := Runi18n("stats", "--format", "html", "-o", expectedFilesPath)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			content, err := ioutil.ReadFile(filepath.Join(expectedFilesPath, "stats.html"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(string(content)).Should(HavePrefix("<!DOCTYPE html>"))
+			Ω(string(content)).Should(ContainSubstring("<tr><td>cli</td><td>fr_FR</td><td>4</td><td>2</td><td>1</td><td>1</td><td>1</td><td>1</td><td>50.0%</td></tr>"))
+			Ω(string(content)).Should(ContainSubstring("<tr class=\"total\"><td>TOTAL</td><td>fr_FR</td>"))
+		})
+	})
+
+	Context("with an unknown format", func() {
+		It("fails", func() {
+			session := Runi18n("stats", "--format", "xml")
+			Ω(session.ExitCode()).Should(Equal(1))
+			Ω(session.Err).Should(Say("unknown stats format: xml"))
+		})
+	})
+
+	Context("using the legacy command", func() {
+		It("prints the stats of the current directory", func() {
+			session := Runi18n("-c", "stats")
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session).Should(Say(`TOTAL\s+fr_FR\s+6\s+3\s+1\s+2\s+1\s+1\s+50.0%`))
+		})
+	})
+
+	Context("with the directory option", func() {
+		It("prints the stats of the packages in the directory", func() {
+			session := Runi18n("stats", "-d", "plugin")
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session).Should(Say(`\.\s+fr_FR\s+2\s+1\s+0\s+1\s+0\s+0\s+50.0%`))
+		})
+
+		It("reports the other regions of the source language as translations", func() {
+			session := Runi18n("stats", "-d", filepath.Join("..", "regional"))
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(session).Should(Say(`\.\s+en_GB\s+2\s+0\s+1\s+1\s+0\s+0\s+0.0%`))
+		})
+	})
+})
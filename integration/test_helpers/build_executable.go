@@ -27,3 +27,11 @@ func BuildExecutable() {
 	I18n4goExec, err = gexec.Build("./../../i18n4go")
 	Ω(err).ShouldNot(HaveOccurred())
 }
+
+var I18n4goVetExec string
+
+func BuildVetExecutable() {
+	var err error
+	I18n4goVetExec, err = gexec.Build("./../../i18n4go/analyzer/cmd/i18n4go-vet")
+	Ω(err).ShouldNot(HaveOccurred())
+}
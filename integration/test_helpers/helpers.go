@@ -25,6 +25,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -71,6 +72,11 @@ func Runi18n(args ...string) *Session {
 	return session
 }
 
+// Runi18nVet waits longer than Runi18n since the analyzer loads and type checks the packages
+func Runi18nVet(args ...string) *Session {
+	return RunCommandWithTimeout(time.Minute, I18n4goVetExec, args...)
+}
+
 func RunCommand(cmd string, args ...string) *Session {
 	command := exec.Command(cmd, args...)
 	session, err := Start(command, GinkgoWriter, GinkgoWriter)
@@ -79,6 +85,14 @@ func RunCommand(cmd string, args ...string) *Session {
 	return session
 }
 
+func RunCommandWithTimeout(timeout time.Duration, cmd string, args ...string) *Session {
+	command := exec.Command(cmd, args...)
+	session, err := Start(command, GinkgoWriter, GinkgoWriter)
+	Ω(err).ShouldNot(HaveOccurred())
+	session.Wait(timeout)
+	return session
+}
+
 func ReadPo(fileName string) map[string]string {
 	file, _ := os.Open(fileName)
 	r := bufio.NewReader(file)
@@ -168,6 +182,27 @@ func CopyFile(srcFile, destFile string) {
 	Ω(err).ShouldNot(HaveOccurred())
 }
 
+// CopyDir copies the files of a directory and of its subdirectories
+func CopyDir(srcDir, destDir string) {
+	err := filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return os.MkdirAll(filepath.Join(destDir, relPath), 0755)
+		}
+		CopyFile(path, filepath.Join(destDir, relPath))
+		return nil
+	})
+	Ω(err).ShouldNot(HaveOccurred())
+}
+
 func CompareExpectedOutputToGeneratedOutput(expectedOutputFile, generatedOutputFile string) {
 	bytes, err := ioutil.ReadFile(expectedOutputFile)
 	Ω(err).ShouldNot(HaveOccurred())
@@ -0,0 +1,187 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unwrap_package_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("unwrap-package", func() {
+	var (
+		outputDir         string
+		rootPath          string
+		fixturesPath      string
+		inputFilesPath    string
+		expectedFilesPath string
+		session           *Session
+	)
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+		rootPath = filepath.Join(dir, "..", "..")
+
+		outputDir, err = ioutil.TempDir(rootPath, "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath = filepath.Join("..", "..", "test_fixtures", "unwrap_package")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+
+		for _, fileName := range []string{
+			filepath.Join("greetings", "greetings.go"),
+			filepath.Join("greetings", "farewells.go"),
+			filepath.Join("greetings", "i18n_init.go"),
+			filepath.Join("other", "other.go"),
+			filepath.Join("resources", "en_US.all.json"),
+			filepath.Join("resources", "fr_FR.all.json"),
+		} {
+			err = os.MkdirAll(filepath.Join(outputDir, filepath.Dir(fileName)), 0755)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			CopyFile(filepath.Join(inputFilesPath, fileName), filepath.Join(outputDir, fileName))
+		}
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("Using legacy commands", func() {
+		Context("unwrapping the files of a directory in place", func() {
+			BeforeEach(func() {
+				session = Runi18n("-c", "unwrap-package",
+					"-d", filepath.Join(outputDir, "greetings"),
+					"--source", outputDir,
+					"--resource", filepath.Join(outputDir, "resources"),
+				)
+
+				Ω(session.ExitCode()).Should(Equal(0))
+			})
+
+			It("replaces the T(...) calls by their source language strings or fmt calls", func() {
+				for _, fileName := range []string{"greetings.go", "farewells.go"} {
+					CompareExpectedOutputToGeneratedOutput(
+						filepath.Join(expectedFilesPath, "greetings", fileName),
+						filepath.Join(outputDir, "greetings", fileName),
+					)
+				}
+			})
+
+			It("keeps the T(...) calls which cannot be unwrapped with a warning", func() {
+				Ω(session.Err).Should(Say(`WARNING keeping the T\(\.\.\.\) call at .*greetings.go:31:9`))
+			})
+
+			It("keeps the i18n_init.go file", func() {
+				CompareExpectedOutputToGeneratedOutput(
+					filepath.Join(inputFilesPath, "greetings", "i18n_init.go"),
+					filepath.Join(outputDir, "greetings", "i18n_init.go"),
+				)
+			})
+
+			It("removes the IDs which are not used anymore from the translation files", func() {
+				for _, fileName := range []string{"en_US.all.json", "fr_FR.all.json"} {
+					CompareExpectedOutputToGeneratedOutput(
+						filepath.Join(expectedFilesPath, "resources", fileName),
+						filepath.Join(outputDir, "resources", fileName),
+					)
+				}
+			})
+		})
+	})
+
+	Context("Using cobra commands", func() {
+		Context("unwrapping the files of a directory in place", func() {
+			BeforeEach(func() {
+				session = Runi18n("unwrap-package",
+					"-d", filepath.Join(outputDir, "greetings"),
+					"--source", outputDir,
+					"--resource", filepath.Join(outputDir, "resources"),
+				)
+
+				Ω(session.ExitCode()).Should(Equal(0))
+			})
+
+			It("replaces the T(...) calls by their source language strings or fmt calls", func() {
+				for _, fileName := range []string{"greetings.go", "farewells.go"} {
+					CompareExpectedOutputToGeneratedOutput(
+						filepath.Join(expectedFilesPath, "greetings", fileName),
+						filepath.Join(outputDir, "greetings", fileName),
+					)
+				}
+			})
+
+			It("keeps the T(...) calls which cannot be unwrapped with a warning", func() {
+				Ω(session.Err).Should(Say(`WARNING keeping the T\(\.\.\.\) call at .*greetings.go:31:9`))
+			})
+
+			It("keeps the i18n_init.go file", func() {
+				CompareExpectedOutputToGeneratedOutput(
+					filepath.Join(inputFilesPath, "greetings", "i18n_init.go"),
+					filepath.Join(outputDir, "greetings", "i18n_init.go"),
+				)
+			})
+
+			It("removes the IDs which are not used anymore from the translation files", func() {
+				for _, fileName := range []string{"en_US.all.json", "fr_FR.all.json"} {
+					CompareExpectedOutputToGeneratedOutput(
+						filepath.Join(expectedFilesPath, "resources", fileName),
+						filepath.Join(outputDir, "resources", fileName),
+					)
+				}
+			})
+		})
+
+		Context("unwrapping a file to an output directory", func() {
+			BeforeEach(func() {
+				session = Runi18n("unwrap-package",
+					"-f", filepath.Join(outputDir, "greetings", "farewells.go"),
+					"-o", filepath.Join(outputDir, "unwrapped"),
+					"--source", outputDir,
+					"--resource", filepath.Join(outputDir, "resources"),
+				)
+
+				Ω(session.ExitCode()).Should(Equal(0))
+			})
+
+			It("writes the unwrapped file to the output directory and keeps the original file", func() {
+				CompareExpectedOutputToGeneratedOutput(
+					filepath.Join(expectedFilesPath, "greetings", "farewells.go"),
+					filepath.Join(outputDir, "unwrapped", "farewells.go"),
+				)
+
+				CompareExpectedOutputToGeneratedOutput(
+					filepath.Join(inputFilesPath, "greetings", "farewells.go"),
+					filepath.Join(outputDir, "greetings", "farewells.go"),
+				)
+			})
+
+			It("removes only the IDs of the file which are not used by the other files", func() {
+				stringInfos := ReadJson(filepath.Join(outputDir, "resources", "en_US.all.json"))
+				Ω(stringInfos).ShouldNot(HaveKey("Goodbye {{.Arg0}}!"))
+				Ω(stringInfos).Should(HaveKey("Welcome {{.Name}} to {{.Place}}"))
+			})
+		})
+	})
+})
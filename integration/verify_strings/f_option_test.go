@@ -82,7 +82,7 @@ var _ = Describe("verify-strings -f fileName --languages \"[lang,?]+\"", func()
 					Context("with missing keys", func() {
 						BeforeEach(func() {
 							session := Runi18n("-c", "verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.en.json"), "--languages", "\"de\"", "-o", expectedFilesPath, "--source-language", "en")
-							Ω(session.ExitCode()).Should(Equal(1))
+							Ω(session.ExitCode()).Should(Equal(2))
 						})
 
 						AfterEach(func() {
@@ -101,7 +101,7 @@ var _ = Describe("verify-strings -f fileName --languages \"[lang,?]+\"", func()
 					Context("with missing and extra keys", func() {
 						BeforeEach(func() {
 							session := Runi18n("-c", "verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.en.json"), "--languages", "\"af\"", "-o", expectedFilesPath, "--source-language", "en")
-							Ω(session.ExitCode()).Should(Equal(1))
+							Ω(session.ExitCode()).Should(Equal(2))
 						})
 
 						AfterEach(func() {
@@ -125,7 +125,7 @@ var _ = Describe("verify-strings -f fileName --languages \"[lang,?]+\"", func()
 					Context("with templated keys whose translation does not contain same arguments", func() {
 						BeforeEach(func() {
 							session := Runi18n("-c", "verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.en.json"), "--languages", "\"es\"", "-o", expectedFilesPath, "--source-language", "en")
-							Ω(session.ExitCode()).Should(Equal(1))
+							Ω(session.ExitCode()).Should(Equal(2))
 						})
 
 						AfterEach(func() {
@@ -145,7 +145,7 @@ var _ = Describe("verify-strings -f fileName --languages \"[lang,?]+\"", func()
 				Context("with multiple language files", func() {
 					BeforeEach(func() {
 						session := Runi18n("-c", "verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.en.json"), "--languages", "\"de,it\"", "-o", expectedFilesPath, "--source-language", "en")
-						Ω(session.ExitCode()).Should(Equal(1))
+						Ω(session.ExitCode()).Should(Equal(2))
 					})
 
 					AfterEach(func() {
@@ -170,7 +170,7 @@ var _ = Describe("verify-strings -f fileName --languages \"[lang,?]+\"", func()
 			Context("with language file", func() {
 				BeforeEach(func() {
 					session := Runi18n("-c", "verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.en.json"), "--languages", "\"ja\"", "-o", expectedFilesPath)
-					Ω(session.ExitCode()).Should(Equal(1))
+					Ω(session.ExitCode()).Should(Equal(2))
 				})
 
 				AfterEach(func() {
@@ -189,7 +189,7 @@ var _ = Describe("verify-strings -f fileName --languages \"[lang,?]+\"", func()
 			Context("with multiple language file", func() {
 				BeforeEach(func() {
 					session := Runi18n("-c", "verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.en.json"), "--languages", "\"ja,cs\"", "-o", expectedFilesPath)
-					Ω(session.ExitCode()).Should(Equal(1))
+					Ω(session.ExitCode()).Should(Equal(2))
 				})
 
 				AfterEach(func() {
@@ -213,7 +213,7 @@ var _ = Describe("verify-strings -f fileName --languages \"[lang,?]+\"", func()
 			Context("when missing a language file", func() {
 				BeforeEach(func() {
 					session := Runi18n("-c", "verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.en.json"), "--languages", "\"ja,ht\"", "-o", expectedFilesPath)
-					Ω(session.ExitCode()).Should(Equal(1))
+					Ω(session.ExitCode()).Should(Equal(4))
 				})
 
 				AfterEach(func() {
@@ -234,7 +234,7 @@ var _ = Describe("verify-strings -f fileName --languages \"[lang,?]+\"", func()
 			Context("does not exist", func() {
 				BeforeEach(func() {
 					session := Runi18n("-c", "verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.ht.json"), "--languages", "\"fr\"", "-o", expectedFilesPath, "--source-language", "en")
-					Ω(session.ExitCode()).Should(Equal(1))
+					Ω(session.ExitCode()).Should(Equal(4))
 				})
 
 				It("fails verification", func() {
@@ -246,7 +246,7 @@ var _ = Describe("verify-strings -f fileName --languages \"[lang,?]+\"", func()
 			Context("does not have any keys", func() {
 				BeforeEach(func() {
 					session := Runi18n("-c", "verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.vi.json"), "--languages", "\"fr\"", "-o", expectedFilesPath, "--source-language", "en")
-					Ω(session.ExitCode()).Should(Equal(1))
+					Ω(session.ExitCode()).Should(Equal(2))
 				})
 
 				It("fails verification", func() {
@@ -306,7 +306,7 @@ var _ = Describe("verify-strings -f fileName --languages \"[lang,?]+\"", func()
 					Context("with missing keys", func() {
 						BeforeEach(func() {
 							session := Runi18n("verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.en.json"), "--languages", "\"de\"", "-o", expectedFilesPath, "--source-language", "en")
-							Ω(session.ExitCode()).Should(Equal(1))
+							Ω(session.ExitCode()).Should(Equal(2))
 						})
 
 						AfterEach(func() {
@@ -325,7 +325,7 @@ var _ = Describe("verify-strings -f fileName --languages \"[lang,?]+\"", func()
 					Context("with missing and extra keys", func() {
 						BeforeEach(func() {
 							session := Runi18n("verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.en.json"), "--languages", "\"af\"", "-o", expectedFilesPath, "--source-language", "en")
-							Ω(session.ExitCode()).Should(Equal(1))
+							Ω(session.ExitCode()).Should(Equal(2))
 						})
 
 						AfterEach(func() {
@@ -349,7 +349,7 @@ var _ = Describe("verify-strings -f fileName --languages \"[lang,?]+\"", func()
 					Context("with templated keys whose translation does not contain same arguments", func() {
 						BeforeEach(func() {
 							session := Runi18n("verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.en.json"), "--languages", "\"es\"", "-o", expectedFilesPath, "--source-language", "en")
-							Ω(session.ExitCode()).Should(Equal(1))
+							Ω(session.ExitCode()).Should(Equal(2))
 						})
 
 						AfterEach(func() {
@@ -369,7 +369,7 @@ var _ = Describe("verify-strings -f fileName --languages \"[lang,?]+\"", func()
 				Context("with multiple language files", func() {
 					BeforeEach(func() {
 						session := Runi18n("verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.en.json"), "--languages", "\"de,it\"", "-o", expectedFilesPath, "--source-language", "en")
-						Ω(session.ExitCode()).Should(Equal(1))
+						Ω(session.ExitCode()).Should(Equal(2))
 					})
 
 					AfterEach(func() {
@@ -394,7 +394,7 @@ var _ = Describe("verify-strings -f fileName --languages \"[lang,?]+\"", func()
 			Context("with language file", func() {
 				BeforeEach(func() {
 					session := Runi18n("verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.en.json"), "--languages", "\"ja\"", "-o", expectedFilesPath)
-					Ω(session.ExitCode()).Should(Equal(1))
+					Ω(session.ExitCode()).Should(Equal(2))
 				})
 
 				AfterEach(func() {
@@ -413,7 +413,7 @@ var _ = Describe("verify-strings -f fileName --languages \"[lang,?]+\"", func()
 			Context("with multiple language file", func() {
 				BeforeEach(func() {
 					session := Runi18n("verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.en.json"), "--languages", "\"ja,cs\"", "-o", expectedFilesPath)
-					Ω(session.ExitCode()).Should(Equal(1))
+					Ω(session.ExitCode()).Should(Equal(2))
 				})
 
 				AfterEach(func() {
@@ -437,7 +437,7 @@ var _ = Describe("verify-strings -f fileName --languages \"[lang,?]+\"", func()
 			Context("when missing a language file", func() {
 				BeforeEach(func() {
 					session := Runi18n("verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.en.json"), "--languages", "\"ja,ht\"", "-o", expectedFilesPath)
-					Ω(session.ExitCode()).Should(Equal(1))
+					Ω(session.ExitCode()).Should(Equal(4))
 				})
 
 				AfterEach(func() {
@@ -458,7 +458,7 @@ var _ = Describe("verify-strings -f fileName --languages \"[lang,?]+\"", func()
 			Context("does not exist", func() {
 				BeforeEach(func() {
 					session := Runi18n("verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.ht.json"), "--languages", "\"fr\"", "-o", expectedFilesPath, "--source-language", "en")
-					Ω(session.ExitCode()).Should(Equal(1))
+					Ω(session.ExitCode()).Should(Equal(4))
 				})
 
 				It("fails verification", func() {
@@ -470,7 +470,7 @@ var _ = Describe("verify-strings -f fileName --languages \"[lang,?]+\"", func()
 			Context("does not have any keys", func() {
 				BeforeEach(func() {
 					session := Runi18n("-c", "verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "quota.go.vi.json"), "--languages", "\"fr\"", "-o", expectedFilesPath, "--source-language", "en")
-					Ω(session.ExitCode()).Should(Equal(1))
+					Ω(session.ExitCode()).Should(Equal(2))
 				})
 
 				It("fails verification", func() {
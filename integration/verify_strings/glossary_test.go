@@ -0,0 +1,79 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify_strings_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("verify-strings with a glossary", func() {
+	var (
+		inputFilesPath    string
+		expectedFilesPath string
+		glossaryFilename  string
+	)
+
+	BeforeEach(func() {
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "verify_strings")
+		inputFilesPath = filepath.Join(fixturesPath, "glossary", "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "glossary", "expected_output")
+		glossaryFilename = filepath.Join(inputFilesPath, "glossary.json")
+	})
+
+	Context("translation following the glossary", func() {
+		It("passes verification", func() {
+			session := Runi18n("verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "cli.go.en.json"), "--languages", "\"fr\"", "-o", expectedFilesPath, "--glossary", glossaryFilename)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			_, err := os.Stat(GetFilePath(expectedFilesPath, "cli.go.fr.json.invalid.diff.json"))
+			Ω(os.IsNotExist(err)).Should(Equal(true))
+		})
+	})
+
+	Context("translation violating the glossary", func() {
+		AfterEach(func() {
+			RemoveAllFiles(
+				GetFilePath(expectedFilesPath, "cli.go.de.json.invalid.diff.json"),
+			)
+		})
+
+		It("reports each violation in the invalid diff file", func() {
+			session := Runi18n("verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "cli.go.en.json"), "--languages", "\"de\"", "-o", expectedFilesPath, "--glossary", glossaryFilename)
+			Ω(session.ExitCode()).Should(Equal(2))
+
+			problems := readInvalidDiffFile(GetFilePath(expectedFilesPath, "cli.go.de.json.invalid.diff.json"))
+			Ω(problems).Should(HaveLen(3))
+
+			Ω(problems["Push the app to Cloud Foundry"]).Should(ConsistOf("glossary term \"Cloud Foundry\" must not be translated for locale de"))
+			Ω(problems["Delete an app"]).Should(ConsistOf("glossary term \"app\" must be translated as \"App\" for locale de"))
+			Ω(problems["Show help"]).Should(ConsistOf("forbidden term \"TODO\" used for locale de"))
+		})
+
+		It("passes verification without the glossary", func() {
+			session := Runi18n("verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "cli.go.en.json"), "--languages", "\"de\"", "-o", expectedFilesPath)
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("passes verification when the glossary check is skipped", func() {
+			session := Runi18n("verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "cli.go.en.json"), "--languages", "\"de\"", "-o", expectedFilesPath, "--glossary", glossaryFilename, "--skip-checks", "glossary")
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,90 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify_strings_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("verify-strings with markup and escape sequences", func() {
+	var (
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "verify_strings")
+		inputFilesPath = filepath.Join(fixturesPath, "markup", "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "markup", "expected_output")
+	})
+
+	Context("translation preserving tags, escapes, whitespace and punctuation", func() {
+		It("passes verification", func() {
+			session := Runi18n("verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "help.go.en.json"), "--languages", "\"ja\"", "-o", expectedFilesPath)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			_, err := os.Stat(GetFilePath(expectedFilesPath, "help.go.ja.json.invalid.diff.json"))
+			Ω(os.IsNotExist(err)).Should(Equal(true))
+		})
+	})
+
+	Context("translation breaking tags, escapes, whitespace and punctuation", func() {
+		AfterEach(func() {
+			RemoveAllFiles(
+				GetFilePath(expectedFilesPath, "help.go.de.json.invalid.diff.json"),
+			)
+		})
+
+		It("reports each problem in the invalid diff file", func() {
+			session := Runi18n("verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "help.go.en.json"), "--languages", "\"de\"", "-o", expectedFilesPath)
+			Ω(session.ExitCode()).Should(Equal(2))
+
+			problems := readInvalidDiffFile(GetFilePath(expectedFilesPath, "help.go.de.json.invalid.diff.json"))
+			Ω(problems).Should(HaveLen(5))
+
+			Ω(problems["Click <a href=\"{{.URL}}\">here</a> to <b>continue</b>."]).Should(ConsistOf(
+				"unbalanced markup tags: </i>,<b>",
+				"markup tags mismatch: expected <a></a><b></b>, found <a></a><b></i>",
+			))
+			Ω(problems["\x1b[31mFAILED\x1b[0m"]).Should(ConsistOf("missing ANSI escape sequences: \"\\x1b[0m\""))
+			Ω(problems["  Name: "]).Should(ConsistOf(
+				"leading whitespace mismatch: expected \"  \", found \"\"",
+				"trailing whitespace mismatch: expected \" \", found \"\"",
+			))
+			Ω(problems["Usage:\nExamples:\n"]).Should(ConsistOf("newlines count mismatch: expected 2, found 1"))
+			Ω(problems["Are you sure?"]).Should(ConsistOf("terminal punctuation mismatch: expected \"?\", found \"\""))
+		})
+
+		It("passes verification when the failing checks are skipped", func() {
+			session := Runi18n("verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "help.go.en.json"), "--languages", "\"de\"", "-o", expectedFilesPath, "--skip-checks", "markup,ansi,whitespace,newlines,punctuation")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			_, err := os.Stat(GetFilePath(expectedFilesPath, "help.go.de.json.invalid.diff.json"))
+			Ω(os.IsNotExist(err)).Should(Equal(true))
+		})
+
+		It("fails with an unknown check", func() {
+			session := Runi18n("verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "help.go.en.json"), "--languages", "\"de\"", "-o", expectedFilesPath, "--skip-checks", "spelling")
+			Ω(session.ExitCode()).Should(Equal(1))
+			Ω(session.Err).Should(gbytes.Say("unknown translation check: spelling"))
+		})
+	})
+})
@@ -0,0 +1,114 @@
+// Copyright © 2015-2023 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify_strings_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/maximilien/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type invalidStringInfo struct {
+	ID          string   `json:"id"`
+	Translation string   `json:"translation"`
+	Problems    []string `json:"problems"`
+}
+
+func readInvalidDiffFile(fileName string) map[string][]string {
+	content, err := ioutil.ReadFile(fileName)
+	Ω(err).ShouldNot(HaveOccurred())
+
+	var invalidStringInfos []invalidStringInfo
+	err = json.Unmarshal(content, &invalidStringInfos)
+	Ω(err).ShouldNot(HaveOccurred())
+
+	problems := make(map[string][]string)
+	for _, invalidStringInfo := range invalidStringInfos {
+		problems[invalidStringInfo.ID] = invalidStringInfo.Problems
+	}
+	return problems
+}
+
+var _ = Describe("verify-strings with printf verbs and templates", func() {
+	var (
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "verify_strings")
+		inputFilesPath = filepath.Join(fixturesPath, "printf_verbs", "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "printf_verbs", "expected_output")
+	})
+
+	Context("translation with matching and indexed verbs", func() {
+		It("passes verification", func() {
+			session := Runi18n("verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "app.go.en.json"), "--languages", "\"fr\"", "-o", expectedFilesPath)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			_, err := os.Stat(GetFilePath(expectedFilesPath, "app.go.fr.json.invalid.diff.json"))
+			Ω(os.IsNotExist(err)).Should(Equal(true))
+		})
+	})
+
+	Context("translation with wrong verb count, order and type", func() {
+		BeforeEach(func() {
+			session := Runi18n("verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "app.go.en.json"), "--languages", "\"de\"", "-o", expectedFilesPath)
+			Ω(session.ExitCode()).Should(Equal(2))
+		})
+
+		AfterEach(func() {
+			RemoveAllFiles(
+				GetFilePath(expectedFilesPath, "app.go.de.json.invalid.diff.json"),
+			)
+		})
+
+		It("reports each problem in the invalid diff file", func() {
+			problems := readInvalidDiffFile(GetFilePath(expectedFilesPath, "app.go.de.json.invalid.diff.json"))
+			Ω(problems).Should(HaveLen(4))
+
+			Ω(problems["Pushing app %s to org %s"]).Should(ConsistOf("printf verb count mismatch: expected 2, found 1"))
+			Ω(problems["Scaled %s to %d instances"]).Should(ConsistOf(ContainSubstring("printf verb order mismatch")))
+			Ω(problems["Memory: %5.2f MB"]).Should(ConsistOf("printf verb type mismatch: %[1]f/%[1]d"))
+			Ω(problems["Hello {{.Name}}"]).Should(ConsistOf("extra template args: Surname"))
+		})
+	})
+
+	Context("translation with extra args and broken template", func() {
+		BeforeEach(func() {
+			session := Runi18n("verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "app.go.en.json"), "--languages", "\"es\"", "-o", expectedFilesPath)
+			Ω(session.ExitCode()).Should(Equal(2))
+		})
+
+		AfterEach(func() {
+			RemoveAllFiles(
+				GetFilePath(expectedFilesPath, "app.go.es.json.invalid.diff.json"),
+			)
+		})
+
+		It("reports each problem in the invalid diff file", func() {
+			problems := readInvalidDiffFile(GetFilePath(expectedFilesPath, "app.go.es.json.invalid.diff.json"))
+			Ω(problems).Should(HaveLen(2))
+
+			Ω(problems["Pushing app %s to org %s"]).Should(ConsistOf("extra printf args: %[3]s"))
+			Ω(problems["Hello {{.Name}}"]).Should(ConsistOf(HavePrefix("invalid template:")))
+		})
+	})
+})
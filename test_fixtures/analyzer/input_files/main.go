@@ -0,0 +1,27 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+)
+
+func T(translationID string, args ...interface{}) string {
+	return translationID
+}
+
+func main() {
+	fmt.Println(T("Hello world"))
+	fmt.Println("Untranslated greeting")
+	fmt.Printf("%s: %d\n", T("Count"), 2)
+	fmt.Printf("Pushed %d apps\n", 3)
+	fmt.Fprintln(os.Stderr, T("Unknown translation"))
+	fmt.Println("debug-only")
+
+	logger := log.New(os.Stderr, "", 0)
+	logger.Printf("Logged message")
+
+	err := errors.New("Something failed")
+	fmt.Println(err)
+}
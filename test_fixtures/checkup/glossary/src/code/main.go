@@ -0,0 +1,7 @@
+package code
+
+import "fmt"
+
+func main() {
+	fmt.Println(T("Push the app to Cloud Foundry"))
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	name    string
+	verbose bool
+)
+
+func NewGreetCommand() *cobra.Command {
+	greetCmd := &cobra.Command{
+		Use:       "greet NAME",
+		Aliases:   []string{"hello", "hi"},
+		ValidArgs: []string{"world"},
+		Short:     "Greets someone",
+		Long:      "Greets someone by name, or the world when no name is given",
+		Example:   "app greet world",
+		GroupID:   "basic",
+		Annotations: map[string]string{
+			"category": "greetings",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Println("Hello")
+			return nil
+		},
+	}
+
+	greetCmd.Flags().StringVarP(&name, "name", "n", "world", "the name to greet")
+	greetCmd.Flags().BoolVar(&verbose, "verbose", false, "show more details")
+	greetCmd.Flags().Int("times", 1, "number of greetings")
+	greetCmd.MarkFlagRequired("name")
+	greetCmd.Flags().MarkDeprecated("times", "use a loop instead")
+
+	return greetCmd
+}
+
+func NewGroup() *cobra.Group {
+	return &cobra.Group{ID: "basic", Title: "Basic commands"}
+}
+
+func parseFlags() {
+	flag.String("output", "text", "the output format")
+	flag.Func("level", "the log level", func(value string) error {
+		return nil
+	})
+	flag.Parse()
+}
+
+func newFlagSet() *flag.FlagSet {
+	flagSet := flag.NewFlagSet("greet", flag.ExitOnError)
+	flagSet.Bool("loud", false, "greet loudly")
+	return flagSet
+}
+
+func setHeaders(header http.Header) {
+	header.Set("X-Greeting", "Welcome to the server")
+}
@@ -0,0 +1,10 @@
+package app
+
+import "fmt"
+
+func Run(name string, count int) {
+	fmt.Println("Hello world!")
+	fmt.Println("Hello, world")
+	fmt.Printf("You have %d new messages\n", count)
+	fmt.Println("%s")
+}
@@ -0,0 +1,5 @@
+package greetings
+
+func Hello() string {
+	return "Hello world"
+}
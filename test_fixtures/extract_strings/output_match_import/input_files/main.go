@@ -0,0 +1,12 @@
+package main
+
+import (
+	"fmt"
+
+	"example.com/greeter/internal/greetings"
+)
+
+func main() {
+	fmt.Println("Welcome to the greeter")
+	fmt.Println(greetings.Hello())
+}
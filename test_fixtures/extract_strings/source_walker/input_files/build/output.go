@@ -0,0 +1,5 @@
+package build
+
+func Output() string {
+	return "Built output"
+}
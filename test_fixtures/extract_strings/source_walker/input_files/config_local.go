@@ -0,0 +1,5 @@
+package main
+
+func localConfig() string {
+	return "Local configuration"
+}
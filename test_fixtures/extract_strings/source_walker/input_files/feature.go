@@ -0,0 +1,7 @@
+//go:build feature
+
+package main
+
+func feature() string {
+	return "Feature enabled"
+}
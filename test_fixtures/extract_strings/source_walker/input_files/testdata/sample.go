@@ -0,0 +1,5 @@
+package sample
+
+func Sample() string {
+	return "Sample test data"
+}
@@ -0,0 +1,18 @@
+package app
+
+import (
+	"embed"
+	"text/template"
+)
+
+//go:embed templates/*
+var templates embed.FS
+
+var usage = template.Must(template.New("usage").Parse(`Usage: {{.Name}} [command]
+
+{{T "Available commands:"}}
+{{range .Commands}}  {{.Name}}{{end}}`))
+
+func Run() {
+	println("Hello from Go")
+}
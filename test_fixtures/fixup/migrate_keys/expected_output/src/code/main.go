@@ -0,0 +1,9 @@
+package code
+
+import "fmt"
+
+func main(name string) {
+	// the greetings
+	fmt.Println(T("code.hello_world"))
+	fmt.Println(T("code.hello", map[string]interface{}{"Name": name}))
+}
@@ -0,0 +1,9 @@
+package code
+
+import "fmt"
+
+func main(name string) {
+	// the greetings
+	fmt.Println(T("Hello world!"))
+	fmt.Println(T("Hello {{.Name}}!", map[string]interface{}{"Name": name}))
+}
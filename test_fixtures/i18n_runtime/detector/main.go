@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+// usage: detector <resourcesDir> fixed <locale> | env [<envVar>...] | chain <envVar> <locale>
+func main() {
+	resourcesDir, mode, args := os.Args[1], os.Args[2], os.Args[3:]
+
+	var detectors []i18n.Detector
+	switch mode {
+	case "fixed":
+		detectors = append(detectors, i18n.NewFixedDetector(args[0]))
+	case "env":
+		detectors = append(detectors, i18n.NewEnvDetector(args...))
+	case "chain":
+		detectors = append(detectors, i18n.NewEnvDetector(args[0]), i18n.NewFixedDetector(args[1]))
+	}
+
+	T := i18n.Init("", resourcesDir, func(asset string) ([]byte, error) {
+		return ioutil.ReadFile(asset)
+	}, detectors...)
+
+	fmt.Println(T("Hello world"))
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+// usage: errors <resourcesDir> <locale> <fileName>
+func main() {
+	resourcesDir, locale, fileName := os.Args[1], os.Args[2], os.Args[3]
+
+	T := i18n.Init("", resourcesDir, func(asset string) ([]byte, error) {
+		return ioutil.ReadFile(asset)
+	}, i18n.NewFixedDetector(locale))
+
+	_, err := os.Open(fileName)
+	err = i18n.Errorf(T("Failed to open {{.Arg0}} at 100%: {{.Arg1}}", map[string]interface{}{"Arg0": fileName, "Arg1": i18n.WrappedArg(0)}), err)
+
+	var pathErr *fs.PathError
+	fmt.Println(err)
+	fmt.Println("is not exist:", errors.Is(err, os.ErrNotExist))
+	fmt.Println("as path error:", errors.As(err, &pathErr))
+}
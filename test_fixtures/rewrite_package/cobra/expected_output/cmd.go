@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	name    string
+	verbose bool
+)
+
+func NewGreetCommand() *cobra.Command {
+	greetCmd := &cobra.Command{
+		Use:       "greet NAME",
+		Aliases:   []string{"hello", "hi"},
+		ValidArgs: []string{"world"},
+		Short:     T("Greets someone"),
+		Long:      T("Greets someone by name, or the world when no name is given"),
+		Example:   T("app greet world"),
+		GroupID:   "basic",
+		Annotations: map[string]string{
+			"category": "greetings",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Println(T("Hello"))
+			return nil
+		},
+	}
+
+	greetCmd.Flags().StringVarP(&name, "name", "n", "world", T("the name to greet"))
+	greetCmd.Flags().BoolVar(&verbose, "verbose", false, T("show more details"))
+	greetCmd.Flags().Int("times", 1, T("number of greetings"))
+	greetCmd.MarkFlagRequired("name")
+	greetCmd.Flags().MarkDeprecated("times", T("use a loop instead"))
+
+	return greetCmd
+}
+
+func NewGroup() *cobra.Group {
+	return &cobra.Group{ID: "basic", Title: T("Basic commands")}
+}
+
+func parseFlags() {
+	flag.String("output", "text", T("the output format"))
+	flag.Func("level", T("the log level"), func(value string) error {
+		return nil
+	})
+	flag.Parse()
+}
+
+func newFlagSet() *flag.FlagSet {
+	flagSet := flag.NewFlagSet("greet", flag.ExitOnError)
+	flagSet.Bool("loud", false, T("greet loudly"))
+	return flagSet
+}
+
+func setHeaders(header http.Header) {
+	header.Set(T("X-Greeting"), T("Welcome to the server"))
+}
@@ -0,0 +1,9 @@
+package diff_option
+
+import "fmt"
+
+// Farewell prints the farewells
+func Farewell(name string) {
+	fmt.Println("Bye world!")
+	fmt.Printf("Bye %s!\n", name)
+}
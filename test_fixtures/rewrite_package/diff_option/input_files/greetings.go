@@ -0,0 +1,11 @@
+package diff_option
+
+import "fmt"
+
+// Greet prints the greetings
+func Greet(name string) {
+	fmt.Println("Hello world!")
+
+	// the name is interpolated
+	fmt.Printf("Hello %s!\n", name)
+}
@@ -0,0 +1,31 @@
+// Package input_files has comments which are kept when rewritten
+package input_files
+
+import (
+	"fmt" // printing
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+	"os"
+)
+
+// Comments prints messages,
+// with comments around them
+func Comments(name string) error {
+	// greet the user
+	fmt.Println(T("Hello")) // trailing comment
+
+	/* block comment */
+	fmt.Printf(T("Hello {{.Arg0}}, bye {{.Arg1}}", map[string]interface{}{ // the format
+		"Arg0": name, "Arg1": name}))
+
+	fmt.Println(
+		T("Multi"),
+		T("line"), // second line
+	)
+
+	if len(os.Args) > 1 {
+		fmt.Println(T("Some args") /* inline */, len(os.Args))
+	}
+
+	// wrap the error
+	return i18n.Errorf(T("Failed for {{.Arg0}}: {{.Arg1}}", map[string]interface{}{"Arg0": name, "Arg1": i18n.WrappedArg(0)}), os.ErrNotExist) // with i18n.Errorf
+}
@@ -0,0 +1,26 @@
+package input_files
+
+import (
+	"errors"
+	"fmt"
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+	"os"
+)
+
+func Errors(name string) error {
+	if len(name) == 0 {
+		return errors.New(T("No file name given"))
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return i18n.Errorf(T("Failed to open {{.Arg0}}: {{.Arg1}}", map[string]interface{}{"Arg0": name, "Arg1": i18n.WrappedArg(0)}), err)
+	}
+	defer file.Close()
+
+	if _, err := file.Stat(); err != nil {
+		return i18n.Errorf(T("Failed to stat {{printf \"%q\" .Arg0}} ({{.Arg1}}): {{.Arg2}}", map[string]interface{}{"Arg0": name, "Arg1": err, "Arg2": i18n.WrappedArg(0)}), errors.New(T("stat failed")))
+	}
+
+	return fmt.Errorf(T("File {{.Arg0}} is 100%% ignored", map[string]interface{}{"Arg0": name}))
+}
@@ -0,0 +1,9 @@
+package input_files
+
+import (
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+func FmtAlias(name string, err error) error {
+	return i18n.Errorf(T("Failed to load {{.Arg0}}: {{.Arg1}}", map[string]interface{}{"Arg0": name, "Arg1": i18n.WrappedArg(0)}), err)
+}
@@ -0,0 +1,11 @@
+package input_files
+
+import (
+	"example.com/app/i18n"
+	i18n4go "github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+func NamedImport(err error) error {
+	i18n.Setup()
+	return i18n4go.Errorf(T("Failed to set up: {{.Arg0}}", map[string]interface{}{"Arg0": i18n4go.WrappedArg(0)}), err)
+}
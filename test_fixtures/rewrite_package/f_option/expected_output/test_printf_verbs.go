@@ -0,0 +1,40 @@
+package input_files
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+)
+
+type Percent int
+
+func (p Percent) String() string {
+	return strconv.Itoa(int(p)) + string('%')
+}
+
+func PaddedOf(label string, value float64) string {
+	return label + strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func PrintfVerbs() string {
+	name := T("cruel")
+	price := 3.14159
+	count := 42
+	fmt.Printf(T("Price of {{printf \"%-10s\" .Arg0}} is {{printf \"%5.2f\" .Arg1}}\n", map[string]interface{}{"Arg0": name, "Arg1": price}))
+	fmt.Printf(T("Delta: {{printf \"%+d\" .Arg0}} items", map[string]interface{}{"Arg0": count}))
+	fmt.Printf(T("{{.Arg1}} owes {{.Arg0}}", map[string]interface{}{"Arg0": name, "Arg1": T("Evil")}))
+	fmt.Fprintf(os.Stdout, T("{{.Arg0}} is 100%% done after {{printf \"%*d\" .Arg1 .Arg2}} steps", map[string]interface{}{"Arg0": name, "Arg1": 5, "Arg2": count}))
+	fmt.Printf(T("Progress: {{printf \"%d\" .Arg0}} or {{.Arg1}}", map[string]interface{}{"Arg0": Percent(count), "Arg1": Percent(count)}))
+	fmt.Println(PaddedOf(T("Ratio %5.2f"), price))
+	log.Printf(T("Quoted {{printf \"%q\" .Arg0}} in hex {{printf \"%x\" .Arg1}}", map[string]interface{}{"Arg0": name, "Arg1": count}), T("extra"))
+
+	values := []interface{}{name, count}
+	fmt.Printf(T("Values: %v %v\n"), values...)
+
+	message := fmt.Sprintf(T("Hello {{.Arg0}}, you are {{printf \"%t\" .Arg1}}", map[string]interface{}{"Arg0": name, "Arg1": true}))
+	fmt.Println(message)
+
+	return fmt.Sprintf(T("Failed with {{printf \"%6.3v\" .Arg0}}", map[string]interface{}{"Arg0": errors.New(T("boom"))}))
+}
@@ -0,0 +1,20 @@
+package input_files
+
+import (
+	"fmt"
+
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+	tr "github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+var Tr i18n.TranslateFunc
+
+func AlreadyTranslated(name string) {
+	fmt.Println(T("translated with T"))
+	fmt.Println(i18n.T("translated with i18n.T"))
+	fmt.Println(tr.T("translated with tr.T"))
+	fmt.Println(Tr("translated with Tr"))
+	fmt.Println(T("Hello {{.Arg0}}", map[string]interface{}{"Arg0": name}))
+
+	fmt.Println("not translated yet")
+}
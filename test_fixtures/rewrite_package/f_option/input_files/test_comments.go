@@ -0,0 +1,30 @@
+// Package input_files has comments which are kept when rewritten
+package input_files
+
+import (
+	"fmt" // printing
+	"os"
+)
+
+// Comments prints messages,
+// with comments around them
+func Comments(name string) error {
+	// greet the user
+	fmt.Println("Hello") // trailing comment
+
+	/* block comment */
+	fmt.Printf("Hello %s, bye %s", // the format
+		name, name)
+
+	fmt.Println(
+		"Multi",
+		"line", // second line
+	)
+
+	if len(os.Args) > 1 {
+		fmt.Println("Some args" /* inline */, len(os.Args))
+	}
+
+	// wrap the error
+	return fmt.Errorf("Failed for %s: %w", name, os.ErrNotExist) // with i18n.Errorf
+}
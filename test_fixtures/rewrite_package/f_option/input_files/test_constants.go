@@ -0,0 +1,28 @@
+package input_files
+
+import (
+	"fmt"
+)
+
+type Color string
+
+const greeting = "Hello"
+
+const (
+	red Color = "red"
+)
+
+var sizes [len("abc")]int
+
+func Constants(color Color, name string) {
+	var favorite Color = "blue"
+
+	switch name {
+	case "admin", "root":
+		fmt.Println("Welcome back")
+	default:
+		fmt.Println("Welcome", name)
+	}
+
+	fmt.Println(greeting, favorite, red, sizes, color == "green")
+}
@@ -0,0 +1,25 @@
+package input_files
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+func Errors(name string) error {
+	if len(name) == 0 {
+		return errors.New("No file name given")
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("Failed to open %s: %w", name, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Stat(); err != nil {
+		return fmt.Errorf("Failed to stat %q (%v): %w", name, err, errors.New("stat failed"))
+	}
+
+	return fmt.Errorf("File %s is 100%% ignored", name)
+}
@@ -0,0 +1,9 @@
+package input_files
+
+import (
+	format "fmt"
+)
+
+func FmtAlias(name string, err error) error {
+	return format.Errorf("Failed to load %s: %w", name, err)
+}
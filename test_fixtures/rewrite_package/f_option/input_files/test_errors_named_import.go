@@ -0,0 +1,12 @@
+package input_files
+
+import (
+	"fmt"
+
+	"example.com/app/i18n"
+)
+
+func NamedImport(err error) error {
+	i18n.Setup()
+	return fmt.Errorf("Failed to set up: %w", err)
+}
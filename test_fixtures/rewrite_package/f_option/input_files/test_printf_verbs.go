@@ -0,0 +1,40 @@
+package input_files
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+)
+
+type Percent int
+
+func (p Percent) String() string {
+	return strconv.Itoa(int(p)) + string('%')
+}
+
+func PaddedOf(label string, value float64) string {
+	return label + strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func PrintfVerbs() string {
+	name := "cruel"
+	price := 3.14159
+	count := 42
+	fmt.Printf("Price of %-10s is %5.2f\n", name, price)
+	fmt.Printf("Delta: %+d items", count)
+	fmt.Printf("%[2]s owes %[1]s", name, "Evil")
+	fmt.Fprintf(os.Stdout, "%s is 100%% done after %*d steps", name, 5, count)
+	fmt.Printf("Progress: %d or %v", Percent(count), Percent(count))
+	fmt.Println(PaddedOf("Ratio %5.2f", price))
+	log.Printf("Quoted %q in hex %x", name, count, "extra")
+
+	values := []interface{}{name, count}
+	fmt.Printf("Values: %v %v\n", values...)
+
+	message := fmt.Sprintf("Hello %v, you are %t", name, true)
+	fmt.Println(message)
+
+	return fmt.Sprintf("Failed with %6.3v", errors.New("boom"))
+}
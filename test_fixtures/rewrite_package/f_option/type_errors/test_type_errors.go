@@ -0,0 +1,11 @@
+package type_errors
+
+import "fmt"
+
+func T(count int) int {
+	return count * 2
+}
+
+func main() {
+	fmt.Println("Hello world", T(2))
+}
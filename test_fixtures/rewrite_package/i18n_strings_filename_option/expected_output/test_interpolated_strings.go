@@ -13,5 +13,5 @@ func Interpolated() string {
 	fmt.Printf("Hello %s world!, bye from %s", name, myName)
 	fmt.Printf(T("Hello again:\t {{.Arg0}} world!\n", map[string]interface{}{"Arg0": name}))
 
-	fmt.Printf(T("Hello {{.Arg0}}({{.Arg1}}) world!, bye from {{.Arg2}}", map[string]interface{}{"Arg0": 10, "Arg1": name, "Arg2": T("Evil")}))
+	fmt.Printf(T("Hello {{printf \"%d\" .Arg0}}({{.Arg1}}) world!, bye from {{.Arg2}}", map[string]interface{}{"Arg0": 10, "Arg1": name, "Arg2": T("Evil")}))
 }
@@ -0,0 +1,11 @@
+package greetings
+
+import "fmt"
+
+// Greet prints the greetings
+func Greet(name string) {
+	fmt.Println(T("greetings.hello_world"))
+
+	// the name is interpolated
+	fmt.Printf(T("greetings.hello", map[string]interface{}{"Arg0": name}))
+}
@@ -0,0 +1,7 @@
+package greetings
+
+import "fmt"
+
+func Farewell(name string) string {
+	return fmt.Sprintf("Goodbye %v!", name)
+}
@@ -0,0 +1,27 @@
+package greetings
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Greet prints the greetings of name
+func Greet(name string, count int) {
+	fmt.Println("Hello world!")
+	fmt.Printf("Hello %v, you have %v messages\n", name, count)
+	fmt.Fprintf(os.Stderr, "Progress: %5.2f%%\n", 42.0)
+
+	// the place is translated too
+	message := fmt.Sprintf("Welcome %v to %v", name, "the whole world")
+	fmt.Println(message)
+}
+
+func Open(fileName string) error {
+	err := errors.New("file not found")
+	return fmt.Errorf("cannot open %v at 100%%: %w", fileName, err)
+}
+
+func Translate(id string) string {
+	return T(id)
+}
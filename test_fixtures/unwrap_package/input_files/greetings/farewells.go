@@ -0,0 +1,5 @@
+package greetings
+
+func Farewell(name string) string {
+	return T("Goodbye {{.Arg0}}!", map[string]interface{}{"Arg0": name})
+}
@@ -0,0 +1,32 @@
+package greetings
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+// Greet prints the greetings of name
+func Greet(name string, count int) {
+	fmt.Println(T("Hello world!"))
+	fmt.Printf(T("Hello {{.Arg0}}, you have {{.Arg1}} messages\n", map[string]interface{}{"Arg0": name, "Arg1": count}))
+	fmt.Fprintf(os.Stderr, T("Progress: {{printf \"%5.2f\" .Arg0}}%%\n", map[string]interface{}{"Arg0": 42.0}))
+
+	// the place is translated too
+	message := T("Welcome {{.Name}} to {{.Place}}", map[string]interface{}{
+		"Name":  name,
+		"Place": T("the world"),
+	})
+	fmt.Println(message)
+}
+
+func Open(fileName string) error {
+	err := errors.New(T("file not found"))
+	return i18n.Errorf(T("cannot open {{.Arg0}} at 100%: {{.Arg1}}", map[string]interface{}{"Arg0": fileName, "Arg1": i18n.WrappedArg(0)}), err)
+}
+
+func Translate(id string) string {
+	return T(id)
+}
@@ -0,0 +1,13 @@
+package greetings
+
+import (
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+var T i18n.TranslateFunc
+
+func init() {
+	T = i18n.Init("greetings", i18n.GetResourcesPath(), func(asset string) ([]byte, error) {
+		return Asset(asset)
+	})
+}
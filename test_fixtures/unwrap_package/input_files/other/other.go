@@ -0,0 +1,14 @@
+package other
+
+import (
+	"fmt"
+
+	"github.com/maximilien/i18n4go/i18n4go/i18n"
+)
+
+var T i18n.TranslateFunc
+
+func Print() {
+	fmt.Println(T("Hello world!"))
+	fmt.Println(T("Still used"))
+}